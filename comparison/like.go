@@ -0,0 +1,59 @@
+package comparison
+
+import "strings"
+
+// LikeEscape is the escape character of the like patterns
+const LikeEscape = `\`
+
+var likeEscaper = strings.NewReplacer(
+	LikeEscape, LikeEscape+LikeEscape,
+	"%", LikeEscape+"%",
+	"_", LikeEscape+"_",
+)
+
+// LikePattern returns the like pattern of the string matching operators,
+// the Like, NotLike and ILike patterns are returned as they are while the
+// wildcards in s are escaped with LikeEscape for HasPrefix, HasSuffix and Contains
+func LikePattern(op Operator, s string) string {
+	switch op {
+	case Like, NotLike, ILike:
+		return s
+	case HasPrefix:
+		return likeEscaper.Replace(s) + "%"
+	case HasSuffix:
+		return "%" + likeEscaper.Replace(s)
+	}
+
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+var globEscaper = strings.NewReplacer(
+	"*", "[*]",
+	"?", "[?]",
+	"[", "[[]",
+)
+
+// GlobPattern converts the like pattern to a case-sensitive glob pattern,
+// e.g. for sqlite where like is case-insensitive. The glob wildcards are
+// matched literally and a trailing escape character is dropped
+func GlobPattern(pattern string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(globEscaper.Replace(string(r)))
+			escaped = false
+		case string(r) == LikeEscape:
+			escaped = true
+		case r == '%':
+			b.WriteString("*")
+		case r == '_':
+			b.WriteString("?")
+		default:
+			b.WriteString(globEscaper.Replace(string(r)))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,24 @@
+package comparison
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "ab%", LikePattern(HasPrefix, "ab"))
+	assert.Equal(t, "%ab", LikePattern(HasSuffix, "ab"))
+	assert.Equal(t, "%ab%", LikePattern(Contains, "ab"))
+	assert.Equal(t, `%1\%\_\\!%`, LikePattern(Contains, `1%_\!`))
+	assert.Equal(t, `a\%_`, LikePattern(Like, `a\%_`))
+	assert.Equal(t, `a\%_`, LikePattern(NotLike, `a\%_`))
+}
+
+func TestGlobPattern(t *testing.T) {
+	assert.Equal(t, "a*b?", GlobPattern("a%b_"))
+	assert.Equal(t, `%_\a`, GlobPattern(`\%\_\\\a`))
+	assert.Equal(t, "[*][?][[]]", GlobPattern("*?[]"))
+	assert.Equal(t, "[*]", GlobPattern(`\*`))
+	assert.Equal(t, "a", GlobPattern(`a\`))
+}
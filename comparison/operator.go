@@ -25,6 +25,34 @@ func (op Operator) String() string {
 		return "In"
 	case NotIn:
 		return "NotIn"
+	case Exists:
+		return "Exists"
+	case NotExists:
+		return "NotExists"
+	case And:
+		return "And"
+	case Or:
+		return "Or"
+	case Not:
+		return "Not"
+	case Like:
+		return "Like"
+	case NotLike:
+		return "NotLike"
+	case ILike:
+		return "ILike"
+	case HasPrefix:
+		return "HasPrefix"
+	case HasSuffix:
+		return "HasSuffix"
+	case Contains:
+		return "Contains"
+	case Matches:
+		return "Matches"
+	case Between:
+		return "Between"
+	case NotBetween:
+		return "NotBetween"
 	}
 
 	return "Invalid"
@@ -53,6 +81,34 @@ func (op Operator) Desc() string {
 		return "in"
 	case NotIn:
 		return "not in"
+	case Exists:
+		return "exists"
+	case NotExists:
+		return "not exists"
+	case And:
+		return "and"
+	case Or:
+		return "or"
+	case Not:
+		return "not"
+	case Like:
+		return "like"
+	case NotLike:
+		return "not like"
+	case ILike:
+		return "case-insensitive like"
+	case HasPrefix:
+		return "has prefix"
+	case HasSuffix:
+		return "has suffix"
+	case Contains:
+		return "contains"
+	case Matches:
+		return "matches"
+	case Between:
+		return "between"
+	case NotBetween:
+		return "not between"
 	}
 
 	return ""
@@ -80,4 +136,39 @@ const (
 	In
 	// In is used to check if a value is not in the list
 	NotIn
+	// Exists is used to check if a related row exists
+	Exists
+	// NotExists is used to check if a related row doesn't exist
+	NotExists
+	// And groups the predicates so that all of them should match
+	And
+	// Or groups the predicates so that any of them should match
+	Or
+	// Not groups the predicates so that they shouldn't all match
+	Not
+	// Like matches a pattern where "%" matches any
+	// sequence of characters and "_" any character
+	Like
+	// NotLike is the negation of Like
+	NotLike
+	// ILike is a case-insensitive Like
+	ILike
+	// HasPrefix matches a string that starts with the argument
+	HasPrefix
+	// HasSuffix matches a string that ends with the argument
+	HasSuffix
+	// Contains matches a string that contains the argument
+	Contains
+	// Matches is a full-text search, the argument is a *TextSearch
+	Matches
+	// Between matches a value in the inclusive range, the
+	// argument is a []interface{} of the lower and upper bounds
+	Between
+	// NotBetween is the negation of Between
+	NotBetween
 )
+
+// IsGroup returns true if the operator groups other predicates
+func (op Operator) IsGroup() bool {
+	return op == And || op == Or || op == Not
+}
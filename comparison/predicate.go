@@ -1,12 +1,65 @@
 package comparison
 
-// Predicate is a predicate
+// Predicate is a predicate, the And, Or and
+// Not predicates have the grouped predicates as Arg
 type Predicate struct {
 	Col string
 	Op  Operator
 	Arg interface{}
 }
 
+// Group returns the grouped predicates of a group predicate
+func (p *Predicate) Group() []*Predicate {
+	preds, _ := p.Arg.([]*Predicate)
+	return preds
+}
+
+// Filters returns true if the predicates filter the rows, an empty In
+// or NotIn and a group without filtering predicates match every row
+func Filters(preds []*Predicate) bool {
+	for _, p := range preds {
+		if p.filters() {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Predicate) filters() bool {
+	switch p.Op {
+	case And:
+		return Filters(p.Group())
+	case Or:
+		// a member that matches every row makes the group match every row
+		for _, pred := range p.Group() {
+			if !pred.filters() {
+				return false
+			}
+		}
+	case In, NotIn:
+		args, _ := p.Arg.([]interface{})
+		return len(args) > 0
+	}
+	return true
+}
+
+// Edge is the argument of the Exists and NotExists operators,
+// Col is the column of the related collection that is
+// matched against the column of the predicate
+type Edge struct {
+	Name       string
+	Collection string
+	Col        string
+}
+
+// TextSearch is the argument of the Matches operator
+type TextSearch struct {
+	// Config is the text search configuration e.g. "english"
+	Config string
+	// Query is the search query
+	Query string
+}
+
 // Predicates is a predicate builder
 type Predicates struct {
 	list []*Predicate
@@ -21,3 +74,16 @@ func (p *Predicates) Add(ps ...*Predicate) {
 func (p *Predicates) All() []*Predicate {
 	return p.list
 }
+
+// Flatten returns the predicates along with the
+// predicates of the groups, depth-first
+func Flatten(preds []*Predicate) []*Predicate {
+	flat := []*Predicate{}
+	for _, p := range preds {
+		flat = append(flat, p)
+		if p.Op.IsGroup() {
+			flat = append(flat, Flatten(p.Group())...)
+		}
+	}
+	return flat
+}
@@ -0,0 +1,42 @@
+package comparison
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	eq := &Predicate{Col: "name", Op: Eq, Arg: "a"}
+	gt := &Predicate{Col: "age", Op: Gt, Arg: 30}
+	not := &Predicate{Op: Not, Arg: []*Predicate{gt}}
+	or := &Predicate{Op: Or, Arg: []*Predicate{eq, not}}
+
+	pb := &Predicates{}
+	pb.Add(or, eq)
+	assert.Equal(t, []*Predicate{or, eq, not, gt, eq}, Flatten(pb.All()))
+	assert.Equal(t, []*Predicate{eq, not}, or.Group())
+	assert.Nil(t, eq.Group())
+
+	assert.True(t, Or.IsGroup())
+	assert.False(t, Eq.IsGroup())
+	assert.Equal(t, "Not", Not.String())
+	assert.Equal(t, "and", And.Desc())
+}
+
+func TestFilters(t *testing.T) {
+	eq := &Predicate{Col: "name", Op: Eq, Arg: "a"}
+	in := &Predicate{Col: "id", Op: In, Arg: []interface{}{}}
+	notIn := &Predicate{Col: "id", Op: NotIn, Arg: []interface{}{1}}
+
+	assert.False(t, Filters(nil))
+	assert.True(t, Filters([]*Predicate{eq}))
+	assert.False(t, Filters([]*Predicate{in}))
+	assert.True(t, Filters([]*Predicate{in, notIn}))
+	assert.False(t, Filters([]*Predicate{{Op: And}}))
+	assert.False(t, Filters([]*Predicate{{Op: And, Arg: []*Predicate{in}}}))
+	assert.True(t, Filters([]*Predicate{{Op: And, Arg: []*Predicate{in, eq}}}))
+	assert.False(t, Filters([]*Predicate{{Op: Or, Arg: []*Predicate{in, eq}}}))
+	assert.True(t, Filters([]*Predicate{{Op: Or, Arg: []*Predicate{notIn, eq}}}))
+	assert.True(t, Filters([]*Predicate{{Op: Not, Arg: []*Predicate{in}}}))
+}
@@ -0,0 +1,71 @@
+package nero
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Cursor is an opaque and URL-safe cursor of the keyset pagination,
+// it holds the values of the sort columns of a row
+type Cursor string
+
+type cursorKey struct {
+	Col string          `json:"c"`
+	Val json.RawMessage `json:"v"`
+}
+
+// NewCursor encodes the values of the columns into a cursor
+func NewCursor(cols []string, vals []interface{}) (Cursor, error) {
+	if len(cols) != len(vals) {
+		return "", errors.New("expecting a value for each column")
+	}
+
+	keys := make([]cursorKey, 0, len(cols))
+	for i, col := range cols {
+		b, err := json.Marshal(vals[i])
+		if err != nil {
+			return "", errors.Wrapf(err, "marshal column %q", col)
+		}
+		keys = append(keys, cursorKey{Col: col, Val: b})
+	}
+
+	b, err := json.Marshal(keys)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal cursor")
+	}
+
+	return Cursor(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// Decode decodes the values of the cursor, dest returns the pointer
+// to decode the value of the column into. It returns the columns in order.
+func (c Cursor) Decode(dest func(col string) interface{}) ([]string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+
+	keys := []cursorKey{}
+	err = json.Unmarshal(b, &keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+
+	cols := make([]string, 0, len(keys))
+	for _, key := range keys {
+		v := dest(key.Col)
+		if v == nil {
+			return nil, errors.Errorf("invalid cursor column %q", key.Col)
+		}
+
+		err = json.Unmarshal(key.Val, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unmarshal column %q", key.Col)
+		}
+		cols = append(cols, key.Col)
+	}
+
+	return cols, nil
+}
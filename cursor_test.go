@@ -0,0 +1,57 @@
+package nero
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	now := time.Now().UTC()
+	cursor, err := NewCursor([]string{"age", "created_at", "id"},
+		[]interface{}{30, &now, "1"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(cursor), "=")
+
+	var (
+		age       int
+		createdAt *time.Time
+		id        string
+	)
+	cols, err := cursor.Decode(func(col string) interface{} {
+		switch col {
+		case "age":
+			return &age
+		case "created_at":
+			return &createdAt
+		case "id":
+			return &id
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"age", "created_at", "id"}, cols)
+	assert.Equal(t, 30, age)
+	require.NotNil(t, createdAt)
+	assert.True(t, now.Equal(*createdAt))
+	assert.Equal(t, "1", id)
+
+	// unknown column
+	_, err = cursor.Decode(func(col string) interface{} { return nil })
+	assert.Error(t, err)
+
+	// wrong type
+	_, err = cursor.Decode(func(col string) interface{} { return &id })
+	assert.Error(t, err)
+
+	_, err = Cursor("not a cursor").Decode(func(col string) interface{} { return &id })
+	assert.Error(t, err)
+
+	_, err = NewCursor([]string{"id"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewCursor([]string{"id"}, []interface{}{func() {}})
+	assert.Error(t, err)
+}
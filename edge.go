@@ -0,0 +1,88 @@
+package nero
+
+// EdgeKind is the kind of an edge
+type EdgeKind int
+
+// List of edge kinds
+const (
+	// EdgeHasMany is an edge to many rows of the referenced schema
+	EdgeHasMany EdgeKind = iota
+	// EdgeBelongsTo is an edge to one row of the referenced schema
+	EdgeBelongsTo
+	// EdgeManyToMany is an edge to many rows of the referenced
+	// schema through a join collection
+	EdgeManyToMany
+)
+
+// Edge is a relation to another schema
+type Edge struct {
+	cfg *EdgeConfig
+}
+
+// EdgeConfig is an edge configuration
+type EdgeConfig struct {
+	// Name is the edge name
+	Name string
+	// Kind is the edge kind
+	Kind EdgeKind
+	// Ref is the referenced schema
+	Ref Schemaer
+	// FK is the foreign key column, it is a column of the referenced schema
+	// for has-many edges, a column of the join collection for many-to-many
+	// edges, otherwise it is a column of the schema
+	FK string
+	// Join is the join collection of many-to-many edges
+	Join string
+	// RefFK is the column of the join collection that refers
+	// to the identity column of the referenced schema
+	RefFK string
+	// StructField overrides the struct field
+	StructField string
+}
+
+// HasMany creates a new has-many edge, fk is the column of the referenced
+// schema that refers to the identity column. The related rows are set to
+// a []*T field of the model.
+func HasMany(name string, ref Schemaer, fk string) *Edge {
+	return newEdge(name, EdgeHasMany, ref, fk)
+}
+
+// BelongsTo creates a new belongs-to edge, fk is the column of the schema
+// that refers to the identity column of the referenced schema. The related
+// row is set to a *T field of the model.
+func BelongsTo(name string, ref Schemaer, fk string) *Edge {
+	return newEdge(name, EdgeBelongsTo, ref, fk)
+}
+
+// ManyToMany creates a new many-to-many edge through the join collection,
+// fk and refFK are the columns of the join collection that refer to the
+// identity columns of the schema and the referenced schema respectively.
+// The associated rows are set to a []*T field of the model.
+func ManyToMany(name string, ref Schemaer, join, fk, refFK string) *Edge {
+	e := newEdge(name, EdgeManyToMany, ref, fk)
+	e.cfg.Join = join
+	e.cfg.RefFK = refFK
+	return e
+}
+
+func newEdge(name string, kind EdgeKind, ref Schemaer, fk string) *Edge {
+	return &Edge{
+		cfg: &EdgeConfig{
+			Name: name,
+			Kind: kind,
+			Ref:  ref,
+			FK:   fk,
+		},
+	}
+}
+
+// Cfg returns the edge configurations
+func (e *Edge) Cfg() *EdgeConfig {
+	return e.cfg
+}
+
+// StructField overrides the struct field name of the related rows
+func (e *Edge) StructField(structField string) *Edge {
+	e.cfg.StructField = structField
+	return e
+}
@@ -0,0 +1,59 @@
+package nero
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLockOutsideTx is returned when the rows are locked outside a transaction
+var ErrLockOutsideTx = errors.New("row locking requires a transaction")
+
+// UnfilteredError is returned when an update or a delete has no
+// predicates and wasn't explicitly allowed to affect all the rows
+type UnfilteredError struct {
+	// Op is the operation i.e. "update" or "delete"
+	Op string
+	// Collection is the collection name
+	Collection string
+}
+
+// Error implements error
+func (e *UnfilteredError) Error() string {
+	return fmt.Sprintf("%s on %q without predicates, use AllRows to %s all the rows",
+		e.Op, e.Collection, e.Op)
+}
+
+// ColumnTypeError is returned when an update copies
+// a column into a column of a different type
+type ColumnTypeError struct {
+	// Column is the updated column
+	Column string
+	// Source is the copied column
+	Source string
+}
+
+// Error implements error
+func (e *ColumnTypeError) Error() string {
+	return fmt.Sprintf("cannot set %q to %q, the columns have different types",
+		e.Column, e.Source)
+}
+
+// UnsupportedError is returned when a repository doesn't support an operation
+type UnsupportedError struct {
+	// Op is the operation e.g. "full-text search"
+	Op string
+	// Repository is the repository e.g. "SQLiteRepository"
+	Repository string
+}
+
+// Error implements error
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by %s", e.Op, e.Repository)
+}
+
+// ToSql implements squirrel.Sqlizer, so that the error can
+// be returned in place of a condition and fail the query
+func (e *UnsupportedError) ToSql() (string, []interface{}, error) {
+	return "", nil, e
+}
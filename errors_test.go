@@ -0,0 +1,30 @@
+package nero
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnfilteredError(t *testing.T) {
+	var err error = &UnfilteredError{Op: "delete", Collection: "users"}
+	assert.Equal(t, `delete on "users" without predicates, use AllRows to delete all the rows`, err.Error())
+
+	var uerr *UnfilteredError
+	assert.True(t, errors.As(err, &uerr))
+	assert.Equal(t, "delete", uerr.Op)
+}
+
+func TestColumnTypeError(t *testing.T) {
+	err := &ColumnTypeError{Column: "updated_at", Source: "name"}
+	assert.Equal(t, `cannot set "updated_at" to "name", the columns have different types`, err.Error())
+}
+
+func TestUnsupportedError(t *testing.T) {
+	err := &UnsupportedError{Op: "full-text search", Repository: "SQLiteRepository"}
+	assert.Equal(t, "full-text search is not supported by SQLiteRepository", err.Error())
+
+	_, _, serr := err.ToSql()
+	assert.Equal(t, err, serr)
+}
@@ -0,0 +1,90 @@
+package eval
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/sf9v/nero/aggregate"
+)
+
+// Aggregate applies the aggregate function to the values. Same as
+// in SQL, null values are ignored and the result of an aggregate
+// function other than count on an empty list is null.
+func Aggregate(fn aggregate.Function, vals []interface{}) (interface{}, error) {
+	if fn == aggregate.None {
+		if len(vals) == 0 {
+			return nil, nil
+		}
+		return vals[0], nil
+	}
+
+	nvals := []interface{}{}
+	for _, v := range vals {
+		nv, err := normalize(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if nv != nil {
+			nvals = append(nvals, nv)
+		}
+	}
+
+	switch fn {
+	case aggregate.Count:
+		return int64(len(nvals)), nil
+	case aggregate.Avg, aggregate.Sum:
+		if len(nvals) == 0 {
+			return nil, nil
+		}
+
+		isInt := true
+		var isum int64
+		var fsum float64
+		for _, v := range nvals {
+			switch n := v.(type) {
+			case int64:
+				isum += n
+				fsum += float64(n)
+			case uint64:
+				isum += int64(n)
+				fsum += float64(n)
+			case float64:
+				isInt = false
+				fsum += n
+			default:
+				return nil, errors.Errorf("cannot %s %T", fn.Desc(), v)
+			}
+		}
+
+		if fn == aggregate.Avg {
+			return fsum / float64(len(nvals)), nil
+		}
+
+		if isInt {
+			return isum, nil
+		}
+
+		return fsum, nil
+	case aggregate.Max, aggregate.Min:
+		if len(nvals) == 0 {
+			return nil, nil
+		}
+
+		res := nvals[0]
+		for _, v := range nvals[1:] {
+			cmp, err := compare(v, res)
+			if err != nil {
+				return nil, err
+			}
+
+			if (fn == aggregate.Max && cmp > 0) ||
+				(fn == aggregate.Min && cmp < 0) {
+				res = v
+			}
+		}
+
+		return res, nil
+	}
+
+	return nil, errors.Errorf("unsupported aggregate function %s", fn)
+}
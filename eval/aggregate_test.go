@@ -0,0 +1,46 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sf9v/nero/aggregate"
+)
+
+func TestAggregate(t *testing.T) {
+	one := 1
+	vals := []interface{}{&one, 2, nil, 3}
+	tests := []struct {
+		fn     aggregate.Function
+		vals   []interface{}
+		expect interface{}
+	}{
+		{fn: aggregate.Avg, vals: vals, expect: float64(2)},
+		{fn: aggregate.Count, vals: vals, expect: int64(3)},
+		{fn: aggregate.Max, vals: vals, expect: int64(3)},
+		{fn: aggregate.Min, vals: vals, expect: int64(1)},
+		{fn: aggregate.Sum, vals: vals, expect: int64(6)},
+		{fn: aggregate.Sum, vals: []interface{}{1, 1.5}, expect: 2.5},
+		{fn: aggregate.None, vals: vals, expect: &one},
+		{fn: aggregate.Avg, vals: []interface{}{nil}, expect: nil},
+		{fn: aggregate.Max, vals: []interface{}{}, expect: nil},
+		{fn: aggregate.None, vals: []interface{}{}, expect: nil},
+	}
+
+	for _, tc := range tests {
+		got, err := Aggregate(tc.fn, tc.vals)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expect, got, tc.fn.String())
+	}
+
+	_, err := Aggregate(aggregate.Sum, []interface{}{"a"})
+	assert.Error(t, err)
+
+	_, err = Aggregate(aggregate.Max, []interface{}{"a", 1})
+	assert.Error(t, err)
+
+	_, err = Aggregate(aggregate.Function(99), vals)
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package eval
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Assign assigns src to the value pointed to by dest. Pointers are
+// followed and numeric and string values are converted if needed.
+func Assign(dest, src interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return errors.New("destination must be a non-nil pointer")
+	}
+	dv = dv.Elem()
+
+	if src == nil {
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+	for !sv.Type().AssignableTo(dv.Type()) && sv.Kind() == reflect.Ptr {
+		if sv.IsNil() {
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		sv = sv.Elem()
+	}
+
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+
+	if dv.Kind() == reflect.Ptr {
+		nv := reflect.New(dv.Type().Elem())
+		err := Assign(nv.Interface(), sv.Interface())
+		if err != nil {
+			return err
+		}
+
+		dv.Set(nv)
+		return nil
+	}
+
+	if (isNumber(sv.Kind()) && isNumber(dv.Kind())) ||
+		(sv.Kind() == reflect.String && dv.Kind() == reflect.String) {
+		dv.Set(sv.Convert(dv.Type()))
+		return nil
+	}
+
+	return errors.Errorf("cannot assign %s to %s", sv.Type(), dv.Type())
+}
+
+// Sequence assigns the sequence number to the value pointed to by dest.
+// This is used for generating auto identities of string or integer types.
+func Sequence(dest interface{}, seq uint64) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return errors.New("destination must be a non-nil pointer")
+	}
+	dv = dv.Elem()
+
+	switch dv.Kind() {
+	case reflect.String:
+		dv.SetString(strconv.FormatUint(seq, 10))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dv.SetInt(int64(seq))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		dv.SetUint(seq)
+	default:
+		return errors.Errorf("cannot use a sequence for %s", dv.Type())
+	}
+
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package eval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssign(t *testing.T) {
+	var f float64
+	require.NoError(t, Assign(&f, int64(2)))
+	assert.Equal(t, float64(2), f)
+
+	var s string
+	require.NoError(t, Assign(&s, myString("a")))
+	assert.Equal(t, "a", s)
+
+	now := time.Now()
+	var tm time.Time
+	require.NoError(t, Assign(&tm, &now))
+	assert.Equal(t, now, tm)
+
+	var ptm *time.Time
+	require.NoError(t, Assign(&ptm, now))
+	assert.Equal(t, now, *ptm)
+
+	require.NoError(t, Assign(&ptm, (*time.Time)(nil)))
+	assert.Nil(t, ptm)
+
+	f = 1
+	require.NoError(t, Assign(&f, nil))
+	assert.Zero(t, f)
+
+	assert.Error(t, Assign(f, 1))
+	assert.Error(t, Assign(&f, "a"))
+	assert.Error(t, Assign(&ptm, "a"))
+}
+
+func TestSequence(t *testing.T) {
+	var s string
+	require.NoError(t, Sequence(&s, 1))
+	assert.Equal(t, "1", s)
+
+	var i int64
+	require.NoError(t, Sequence(&i, 2))
+	assert.Equal(t, int64(2), i)
+
+	var u uint
+	require.NoError(t, Sequence(&u, 3))
+	assert.Equal(t, uint(3), u)
+
+	var f float64
+	assert.Error(t, Sequence(&f, 1))
+	assert.Error(t, Sequence(f, 1))
+}
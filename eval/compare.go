@@ -0,0 +1,237 @@
+package eval
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Compare compares two values. The result is 0 if a == b, -1 if a < b and +1 if a > b.
+// Pointers are dereferenced and driver.Valuer are converted to their driver value
+// before comparing. Nil values are compared using the "nulls first" ordering.
+func Compare(a, b interface{}) (int, error) {
+	a, err := normalize(a)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err = normalize(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return compare(a, b)
+}
+
+func compare(a, b interface{}) (int, error) {
+	switch {
+	case a == nil && b == nil:
+		return 0, nil
+	case a == nil:
+		return -1, nil
+	case b == nil:
+		return 1, nil
+	}
+
+	switch av := a.(type) {
+	case int64:
+		switch bv := b.(type) {
+		case int64:
+			return compareInt(av, bv), nil
+		case uint64:
+			return compareFloat(float64(av), float64(bv)), nil
+		case float64:
+			return compareFloat(float64(av), bv), nil
+		}
+	case uint64:
+		switch bv := b.(type) {
+		case int64:
+			return compareFloat(float64(av), float64(bv)), nil
+		case uint64:
+			return compareFloat(float64(av), float64(bv)), nil
+		case float64:
+			return compareFloat(float64(av), bv), nil
+		}
+	case float64:
+		switch bv := b.(type) {
+		case int64:
+			return compareFloat(av, float64(bv)), nil
+		case uint64:
+			return compareFloat(av, float64(bv)), nil
+		case float64:
+			return compareFloat(av, bv), nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return compareString(av, bv), nil
+		}
+	case []byte:
+		if bv, ok := b.([]byte); ok {
+			return bytes.Compare(av, bv), nil
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return compareBool(av, bv), nil
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return compareTime(av, bv), nil
+		}
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return 0, nil
+	}
+
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if isList(ra.Kind()) && isList(rb.Kind()) {
+		return compareList(ra, rb)
+	}
+
+	return 0, errors.Errorf("cannot compare %T with %T", a, b)
+}
+
+func isList(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// compareList compares the elements of the lists in order, a shorter
+// list is less than a longer list that starts with the same elements
+func compareList(a, b reflect.Value) (int, error) {
+	for i := 0; i < a.Len() && i < b.Len(); i++ {
+		cmp, err := Compare(a.Index(i).Interface(), b.Index(i).Interface())
+		if err != nil || cmp != 0 {
+			return cmp, err
+		}
+	}
+
+	return compareInt(int64(a.Len()), int64(b.Len())), nil
+}
+
+// Equal returns true if a and b are equal. Unlike Compare, it
+// also supports values that cannot be ordered e.g. structs
+func Equal(a, b interface{}) (bool, error) {
+	a, err := normalize(a)
+	if err != nil {
+		return false, err
+	}
+
+	b, err = normalize(b)
+	if err != nil {
+		return false, err
+	}
+
+	cmp, err := compare(a, b)
+	if err != nil {
+		return false, nil
+	}
+
+	return cmp == 0, nil
+}
+
+var (
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// normalize converts v to one of nil, int64, uint64, float64,
+// string, []byte, bool, time.Time or the de-referenced value
+func normalize(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+
+	if rv.Type().Implements(valuerType) {
+		dv, err := v.(driver.Valuer).Value()
+		if err != nil {
+			return nil, errors.Wrap(err, "driver value")
+		}
+		// driver values are already normalized
+		return normalize(dv)
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		return normalize(rv.Elem().Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes(), nil
+		}
+	case reflect.Struct:
+		if rv.Type().ConvertibleTo(timeType) {
+			return rv.Convert(timeType).Interface(), nil
+		}
+	}
+
+	return v, nil
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareString(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
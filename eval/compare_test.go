@@ -0,0 +1,81 @@
+package eval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myString string
+
+type myStruct struct {
+	A int
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Second)
+	k1, k2 := ksuid.New(), ksuid.New()
+	if k1.String() > k2.String() {
+		k1, k2 = k2, k1
+	}
+
+	tests := []struct {
+		a, b   interface{}
+		expect int
+	}{
+		{a: 1, b: 2, expect: -1},
+		{a: int8(2), b: int64(2), expect: 0},
+		{a: uint(3), b: 2, expect: 1},
+		{a: 1.5, b: 1, expect: 1},
+		{a: 1, b: 1.5, expect: -1},
+		{a: uint(1), b: 1.5, expect: -1},
+		{a: "a", b: "b", expect: -1},
+		{a: myString("b"), b: "a", expect: 1},
+		{a: []byte("a"), b: []byte("a"), expect: 0},
+		{a: false, b: true, expect: -1},
+		{a: true, b: true, expect: 0},
+		{a: now, b: later, expect: -1},
+		{a: &later, b: now, expect: 1},
+		{a: k1, b: k2, expect: -1},
+		{a: nil, b: 1, expect: -1},
+		{a: 1, b: nil, expect: 1},
+		{a: (*time.Time)(nil), b: nil, expect: 0},
+		{a: myStruct{A: 1}, b: myStruct{A: 1}, expect: 0},
+		{a: []string{"a", "b"}, b: []string{"a", "c"}, expect: -1},
+		{a: []string{"a", "b"}, b: []string{"a"}, expect: 1},
+		{a: []int{1, 2}, b: []int64{1, 2}, expect: 0},
+	}
+
+	for _, tc := range tests {
+		got, err := Compare(tc.a, tc.b)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expect, got, "%v %v", tc.a, tc.b)
+	}
+
+	_, err := Compare("a", 1)
+	assert.Error(t, err)
+
+	_, err = Compare(myStruct{A: 1}, myStruct{A: 2})
+	assert.Error(t, err)
+
+	_, err = Compare([]string{"a"}, []int{1})
+	assert.Error(t, err)
+}
+
+func TestEqual(t *testing.T) {
+	eq, err := Equal(myStruct{A: 1}, myStruct{A: 1})
+	require.NoError(t, err)
+	assert.True(t, eq)
+
+	eq, err = Equal(myStruct{A: 1}, myStruct{A: 2})
+	require.NoError(t, err)
+	assert.False(t, eq)
+
+	eq, err = Equal("1", 1)
+	require.NoError(t, err)
+	assert.False(t, eq)
+}
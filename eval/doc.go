@@ -0,0 +1,3 @@
+// Package eval contains functions for evaluating predicates, sorts and aggregates in Go.
+// It is used by templates of back-ends that don't have a query language e.g. in-memory.
+package eval
@@ -0,0 +1,99 @@
+package eval
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sf9v/nero/comparison"
+)
+
+// matchString evaluates the string matching operators
+func matchString(op comparison.Operator, s, arg string) bool {
+	switch op {
+	case comparison.HasPrefix:
+		return strings.HasPrefix(s, arg)
+	case comparison.HasSuffix:
+		return strings.HasSuffix(s, arg)
+	case comparison.Contains:
+		return strings.Contains(s, arg)
+	case comparison.NotLike:
+		return !like(s, arg, false)
+	}
+
+	return like(s, arg, op == comparison.ILike)
+}
+
+// like matches s against the like pattern, same as in postgres
+// the wildcards are escaped with a backslash. The pattern is matched
+// rune by rune, backtracking to the last "%" on a mismatch
+func like(s, pattern string, fold bool) bool {
+	toks := likeTokens(pattern)
+	rs := []rune(s)
+
+	// star is the index of the last "%" token and
+	// mark is the index of the rune it was matched at
+	si, ti, star, mark := 0, 0, -1, 0
+	for si < len(rs) {
+		switch {
+		case ti < len(toks) && toks[ti].any:
+			star, mark = ti, si
+			ti++
+		case ti < len(toks) && toks[ti].match(rs[si], fold):
+			si++
+			ti++
+		case star >= 0:
+			// let the last "%" match one more rune
+			mark++
+			si, ti = mark, star+1
+		default:
+			return false
+		}
+	}
+
+	for ti < len(toks) && toks[ti].any {
+		ti++
+	}
+
+	return ti == len(toks)
+}
+
+// likeToken is either a "%" that matches any sequence of runes,
+// a "_" that matches any rune or a literal rune
+type likeToken struct {
+	any, one bool
+	r        rune
+}
+
+func (t likeToken) match(r rune, fold bool) bool {
+	switch {
+	case t.one || t.r == r:
+		return true
+	case fold:
+		return unicode.ToLower(t.r) == unicode.ToLower(r) ||
+			unicode.ToUpper(t.r) == unicode.ToUpper(r)
+	}
+	return false
+}
+
+// likeTokens splits the pattern into tokens, a trailing escape character is dropped
+func likeTokens(pattern string) []likeToken {
+	toks := []likeToken{}
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			toks = append(toks, likeToken{r: r})
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			toks = append(toks, likeToken{any: true})
+		case r == '_':
+			toks = append(toks, likeToken{one: true})
+		default:
+			toks = append(toks, likeToken{r: r})
+		}
+	}
+
+	return toks
+}
@@ -0,0 +1,47 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLike(t *testing.T) {
+	tests := []struct {
+		s, pattern string
+		fold       bool
+		expect     bool
+	}{
+		{s: "", pattern: "", expect: true},
+		{s: "", pattern: "%", expect: true},
+		{s: "", pattern: "_", expect: false},
+		{s: "abc", pattern: "abc", expect: true},
+		{s: "abc", pattern: "ab", expect: false},
+		{s: "abc", pattern: "a%", expect: true},
+		{s: "abc", pattern: "%c", expect: true},
+		{s: "abcbc", pattern: "%bc%bc", expect: true},
+		{s: "abcbd", pattern: "%b_", expect: true},
+		{s: "abcbd", pattern: "a%bc", expect: false},
+		{s: "a\nb", pattern: "a_b", expect: true},
+		{s: "a\nb", pattern: "a%", expect: true},
+		{s: "héllo", pattern: "h_llo", expect: true},
+		{s: "a%b", pattern: `a\%b`, expect: true},
+		{s: "axb", pattern: `a\%b`, expect: false},
+		{s: `a\b`, pattern: `a\\b`, expect: true},
+		{s: "a*b", pattern: "a*b", expect: true},
+		{s: "ab", pattern: `ab\`, expect: true},
+		{s: "ABC", pattern: "abc", expect: false},
+		{s: "ABC", pattern: "a%c", fold: true, expect: true},
+		{s: "ÉTÉ", pattern: "été", fold: true, expect: true},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expect, like(tc.s, tc.pattern, tc.fold), "%q like %q", tc.s, tc.pattern)
+	}
+}
+
+func BenchmarkLike(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		like("human_1234567890_mm", "%an\\_%\\_mm", false)
+	}
+}
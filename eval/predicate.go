@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/sf9v/nero/comparison"
+)
+
+// Predicate evaluates the operator against the column value and the predicate argument.
+// It follows the SQL semantics i.e. a null value is neither equal nor not equal to anything.
+func Predicate(op comparison.Operator, v, arg interface{}) (bool, error) {
+	t, err := Evaluate(op, v, arg)
+	return t == True, err
+}
+
+// Evaluate is the same as Predicate but it returns Unknown instead of False
+// when a null is compared, so that the result can be negated as in SQL
+func Evaluate(op comparison.Operator, v, arg interface{}) (Truth, error) {
+	v, err := normalize(v)
+	if err != nil {
+		return False, err
+	}
+
+	switch op {
+	case comparison.IsNull:
+		return truth(v == nil), nil
+	case comparison.IsNotNull:
+		return truth(v != nil), nil
+	case comparison.In, comparison.NotIn:
+		args, ok := arg.([]interface{})
+		if !ok {
+			return False, errors.Errorf("expecting %s argument to be []interface{}", op)
+		}
+
+		// an empty list matches everything, same as the sql templates
+		if len(args) == 0 {
+			return True, nil
+		}
+
+		if v == nil {
+			return Unknown, nil
+		}
+
+		// same as in sql, a value that is not in the list
+		// is unknown if the list contains a null
+		in := False
+		for _, a := range args {
+			a, err := normalize(a)
+			if err != nil {
+				return False, err
+			}
+
+			if a == nil {
+				in = Unknown
+				continue
+			}
+
+			eq, err := Equal(v, a)
+			if err != nil {
+				return False, err
+			}
+
+			if eq {
+				in = True
+				break
+			}
+		}
+
+		if op == comparison.NotIn {
+			return in.Not(), nil
+		}
+		return in, nil
+	case comparison.Between, comparison.NotBetween:
+		bounds, ok := arg.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return False, errors.Errorf("expecting %s argument to be the lower and upper bounds", op)
+		}
+
+		// same as in sql, between is the conjunction of v >= lo and v <= hi
+		lo, err := Evaluate(comparison.GtOrEq, v, bounds[0])
+		if err != nil {
+			return False, err
+		}
+		hi, err := Evaluate(comparison.LtOrEq, v, bounds[1])
+		if err != nil {
+			return False, err
+		}
+
+		if op == comparison.NotBetween {
+			return lo.And(hi).Not(), nil
+		}
+		return lo.And(hi), nil
+	}
+
+	arg, err = normalize(arg)
+	if err != nil {
+		return False, err
+	}
+
+	if v == nil || arg == nil {
+		return Unknown, nil
+	}
+
+	switch op {
+	case comparison.Like, comparison.NotLike, comparison.ILike,
+		comparison.HasPrefix, comparison.HasSuffix, comparison.Contains:
+		vs, ok := v.(string)
+		if !ok {
+			return False, errors.Errorf("cannot %s %T", op.Desc(), v)
+		}
+
+		as, ok := arg.(string)
+		if !ok {
+			return False, errors.Errorf("expecting %s argument to be a string", op)
+		}
+
+		return truth(matchString(op, vs, as)), nil
+	case comparison.Eq:
+		eq, err := Equal(v, arg)
+		return truth(eq), err
+	case comparison.NotEq:
+		eq, err := Equal(v, arg)
+		return truth(!eq), err
+	}
+
+	cmp, err := compare(v, arg)
+	if err != nil {
+		return False, err
+	}
+
+	switch op {
+	case comparison.Gt:
+		return truth(cmp > 0), nil
+	case comparison.GtOrEq:
+		return truth(cmp >= 0), nil
+	case comparison.Lt:
+		return truth(cmp < 0), nil
+	case comparison.LtOrEq:
+		return truth(cmp <= 0), nil
+	}
+
+	return False, errors.Errorf("unsupported operator %s", op)
+}
@@ -0,0 +1,138 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sf9v/nero/comparison"
+)
+
+func TestPredicate(t *testing.T) {
+	s := "a"
+	tests := []struct {
+		op     comparison.Operator
+		v, arg interface{}
+		expect bool
+	}{
+		{op: comparison.Eq, v: 1, arg: 1, expect: true},
+		{op: comparison.Eq, v: nil, arg: nil, expect: false},
+		{op: comparison.NotEq, v: 1, arg: 2, expect: true},
+		{op: comparison.NotEq, v: nil, arg: 2, expect: false},
+		{op: comparison.Gt, v: 2, arg: 1, expect: true},
+		{op: comparison.GtOrEq, v: 1, arg: 1, expect: true},
+		{op: comparison.Lt, v: 1, arg: 1, expect: false},
+		{op: comparison.LtOrEq, v: &s, arg: "a", expect: true},
+		{op: comparison.IsNull, v: (*string)(nil), expect: true},
+		{op: comparison.IsNotNull, v: &s, expect: true},
+		{op: comparison.In, v: 1, arg: []interface{}{2, 1}, expect: true},
+		{op: comparison.In, v: 3, arg: []interface{}{2, 1}, expect: false},
+		{op: comparison.In, v: 3, arg: []interface{}{}, expect: true},
+		{op: comparison.In, v: nil, arg: []interface{}{1}, expect: false},
+		{op: comparison.NotIn, v: 3, arg: []interface{}{2, 1}, expect: true},
+		{op: comparison.Like, v: "norn", arg: "n%n", expect: true},
+		{op: comparison.Like, v: "Norn", arg: "n_rn", expect: false},
+		{op: comparison.Like, v: "50%", arg: `5_\%`, expect: true},
+		{op: comparison.Like, v: "500", arg: `5_\%`, expect: false},
+		{op: comparison.Like, v: "a.b", arg: "a.b", expect: true},
+		{op: comparison.NotLike, v: "norn", arg: "h%", expect: true},
+		{op: comparison.ILike, v: "Norn", arg: "n_rn", expect: true},
+		{op: comparison.ILike, v: nil, arg: "%", expect: false},
+		{op: comparison.HasPrefix, v: &s, arg: "a", expect: true},
+		{op: comparison.HasSuffix, v: "a%", arg: "%", expect: true},
+		{op: comparison.Contains, v: "abc", arg: "%", expect: false},
+		{op: comparison.Between, v: 1, arg: []interface{}{1, 3}, expect: true},
+		{op: comparison.Between, v: 3, arg: []interface{}{1, 3}, expect: true},
+		{op: comparison.Between, v: 4, arg: []interface{}{1, 3}, expect: false},
+		{op: comparison.Between, v: nil, arg: []interface{}{1, 3}, expect: false},
+		{op: comparison.NotBetween, v: 4, arg: []interface{}{1, 3}, expect: true},
+		{op: comparison.NotBetween, v: &s, arg: []interface{}{"a", "b"}, expect: false},
+		{op: comparison.NotBetween, v: 4, arg: []interface{}{nil, 3}, expect: true},
+		{op: comparison.NotBetween, v: 2, arg: []interface{}{nil, 3}, expect: false},
+	}
+
+	for _, tc := range tests {
+		got, err := Predicate(tc.op, tc.v, tc.arg)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expect, got, "%s %v %v", tc.op, tc.v, tc.arg)
+	}
+
+	_, err := Predicate(comparison.In, 1, 1)
+	assert.Error(t, err)
+
+	_, err = Predicate(comparison.Between, 1, []interface{}{1})
+	assert.Error(t, err)
+
+	_, err = Predicate(comparison.Between, 1, []interface{}{"a", "b"})
+	assert.Error(t, err)
+
+	_, err = Predicate(comparison.Gt, 1, "a")
+	assert.Error(t, err)
+
+	_, err = Predicate(comparison.Like, 1, "a")
+	assert.Error(t, err)
+
+	_, err = Predicate(comparison.Like, "a", 1)
+	assert.Error(t, err)
+
+	_, err = Predicate(comparison.Operator(99), 1, 1)
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		op     comparison.Operator
+		v, arg interface{}
+		expect Truth
+	}{
+		{op: comparison.Eq, v: 1, arg: 1, expect: True},
+		{op: comparison.Eq, v: 1, arg: 2, expect: False},
+		{op: comparison.Eq, v: nil, arg: 1, expect: Unknown},
+		{op: comparison.NotEq, v: (*int)(nil), arg: 1, expect: Unknown},
+		{op: comparison.Gt, v: 1, arg: nil, expect: Unknown},
+		{op: comparison.Like, v: nil, arg: "%", expect: Unknown},
+		{op: comparison.IsNull, v: nil, expect: True},
+		{op: comparison.IsNotNull, v: nil, expect: False},
+		{op: comparison.In, v: nil, arg: []interface{}{}, expect: True},
+		{op: comparison.In, v: nil, arg: []interface{}{1}, expect: Unknown},
+		{op: comparison.In, v: 1, arg: []interface{}{nil, 1}, expect: True},
+		{op: comparison.In, v: 2, arg: []interface{}{nil, 1}, expect: Unknown},
+		{op: comparison.NotIn, v: 2, arg: []interface{}{nil, 1}, expect: Unknown},
+		{op: comparison.NotIn, v: 1, arg: []interface{}{nil, 1}, expect: False},
+		{op: comparison.Between, v: nil, arg: []interface{}{1, 3}, expect: Unknown},
+		{op: comparison.Between, v: 2, arg: []interface{}{nil, 3}, expect: Unknown},
+		{op: comparison.Between, v: 4, arg: []interface{}{nil, 3}, expect: False},
+		{op: comparison.NotBetween, v: 2, arg: []interface{}{1, nil}, expect: Unknown},
+		{op: comparison.NotBetween, v: 0, arg: []interface{}{1, nil}, expect: True},
+	}
+
+	for _, tc := range tests {
+		got, err := Evaluate(tc.op, tc.v, tc.arg)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expect, got, "%s %v %v", tc.op, tc.v, tc.arg)
+	}
+}
+
+func TestTruth(t *testing.T) {
+	truths := []Truth{False, True, Unknown}
+	not := []Truth{True, False, Unknown}
+	and := [][]Truth{
+		{False, False, False},
+		{False, True, Unknown},
+		{False, Unknown, Unknown},
+	}
+	or := [][]Truth{
+		{False, True, Unknown},
+		{True, True, True},
+		{Unknown, True, Unknown},
+	}
+
+	for i, a := range truths {
+		assert.Equal(t, not[i], a.Not(), "not %s", a)
+		for j, b := range truths {
+			assert.Equal(t, and[i][j], a.And(b), "%s and %s", a, b)
+			assert.Equal(t, or[i][j], a.Or(b), "%s or %s", a, b)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package eval
+
+// Truth is a value of the SQL three-valued logic
+type Truth int
+
+const (
+	// False is the result of a predicate that doesn't hold
+	False Truth = iota
+	// True is the result of a predicate that holds
+	True
+	// Unknown is the result of a predicate on a null value
+	Unknown
+)
+
+// truth converts b to True or False
+func truth(b bool) Truth {
+	if b {
+		return True
+	}
+	return False
+}
+
+// Not negates t, the negation of Unknown is Unknown
+func (t Truth) Not() Truth {
+	switch t {
+	case True:
+		return False
+	case False:
+		return True
+	}
+	return Unknown
+}
+
+// And returns the conjunction of t and u, it is False if either of them is False
+func (t Truth) And(u Truth) Truth {
+	switch {
+	case t == False || u == False:
+		return False
+	case t == True && u == True:
+		return True
+	}
+	return Unknown
+}
+
+// Or returns the disjunction of t and u, it is True if either of them is True
+func (t Truth) Or(u Truth) Truth {
+	switch {
+	case t == True || u == True:
+		return True
+	case t == False && u == False:
+		return False
+	}
+	return Unknown
+}
+
+// String implements fmt.Stringer
+func (t Truth) String() string {
+	switch t {
+	case True:
+		return "true"
+	case False:
+		return "false"
+	}
+	return "unknown"
+}
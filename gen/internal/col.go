@@ -2,6 +2,7 @@ package internal
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/jinzhu/inflection"
 	"github.com/sf9v/mira"
@@ -25,6 +26,9 @@ type Col struct {
 	Nullable,
 	// ColumnComparable enables comparison with other column
 	ColumnComparable bool
+	// TextSearch is the text search configuration,
+	// empty if the column is not full-text searchable
+	TextSearch string
 }
 
 // CamelName returns a camelized version of name
@@ -70,6 +74,11 @@ func (c *Col) IsArray() bool {
 		kind == reflect.Slice
 }
 
+// IsMap returns true if column is a map
+func (c *Col) IsMap() bool {
+	return c.Type.T().Kind() == reflect.Map
+}
+
 var valueScannerType = reflect.TypeOf(new(nero.ValueScanner)).Elem()
 
 // IsValueScanner returns true if column implements value scanner
@@ -81,3 +90,49 @@ func (c *Col) IsValueScanner() bool {
 
 	return t.Implements(valueScannerType)
 }
+
+// IsNumeric returns true if column is an integer or a float
+func (c *Col) IsNumeric() bool {
+	return c.Type.Kind() == mira.Numeric
+}
+
+// IsString returns true if column is a string or a pointer to a string
+func (c *Col) IsString() bool {
+	t := c.Type.T()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// IsStringIdent returns true if column is an identity of a string type
+// that doesn't implement value scanner
+func (c *Col) IsStringIdent() bool {
+	return c.Ident && c.Type.T().Kind() == reflect.String &&
+		!c.IsValueScanner()
+}
+
+// IsFullText returns true if column is full-text searchable
+func (c *Col) IsFullText() bool {
+	return len(c.TextSearch) > 0
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// IsOrderable returns true if column is a number, a string
+// or a time, or a pointer to one, i.e. it can be ranged over
+func (c *Col) IsOrderable() bool {
+	t := c.Type.T()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+
+	return t.ConvertibleTo(timeType)
+}
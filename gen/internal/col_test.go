@@ -2,6 +2,7 @@ package internal
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sf9v/mira"
@@ -24,12 +25,39 @@ func TestColMethods(t *testing.T) {
 	assert.Equal(t, "id", col.Identifier())
 	assert.Equal(t, "ids", col.IdentifierPlural())
 	assert.True(t, col.HasPreds())
+	assert.False(t, col.IsNumeric())
+	assert.True(t, col.IsStringIdent())
+
+	col = Col{Name: "age", Type: mira.NewType(0)}
+	assert.True(t, col.IsNumeric())
+
+	col = Col{Name: "age", Type: mira.NewType(new(int))}
+	assert.False(t, col.IsNumeric())
+
+	col = Col{Name: "name", Type: mira.NewType("")}
+	assert.True(t, col.IsString())
+	assert.False(t, col.IsStringIdent())
+	col = Col{Name: "name", Type: mira.NewType(new(string))}
+	assert.True(t, col.IsString())
+	col = Col{Name: "tags", Type: mira.NewType([]string{})}
+	assert.False(t, col.IsString())
+	assert.False(t, col.IsFullText())
+	assert.False(t, col.IsOrderable())
+	col = Col{Name: "updated_at", Type: mira.NewType(new(time.Time))}
+	assert.True(t, col.IsOrderable())
+	col = Col{Name: "age", Type: mira.NewType(0)}
+	assert.True(t, col.IsOrderable())
+	col = Col{Name: "title", Type: mira.NewType(""), TextSearch: "english"}
+	assert.True(t, col.IsFullText())
 
 	col = Col{Type: mira.NewType(example.Map{})}
 	assert.True(t, col.IsValueScanner())
 
+	assert.True(t, col.IsMap())
+
 	col = Col{Name: "tags", Type: mira.NewType([]string{})}
 	assert.True(t, col.IsArray())
+	assert.False(t, col.IsMap())
 	assert.False(t, col.HasPreds())
 	assert.False(t, col.IsValueScanner())
 
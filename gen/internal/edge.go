@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/sf9v/nero"
+	stringsx "github.com/sf9v/nero/x/strings"
+)
+
+// Edge is a relation to another schema
+type Edge struct {
+	// Name is the edge name
+	Name string
+	// StructField is the struct field of the related rows
+	StructField string
+	// Kind is the edge kind
+	Kind nero.EdgeKind
+	// Col is the column that is matched against the RefCol
+	Col *Col
+	// RefCol is the column of the referenced schema
+	RefCol *Col
+	// Ref is the referenced schema, its edges are not resolved
+	Ref *Schema
+	// Join is the join collection of many-to-many edges
+	Join string
+	// JoinCol is the column of the join collection matched against Col
+	JoinCol string
+	// JoinRefCol is the column of the join collection matched against RefCol
+	JoinRefCol string
+}
+
+func buildEdge(schema *Schema, cfg *nero.EdgeConfig) (*Edge, error) {
+	if cfg.Ref == nil {
+		return nil, errors.New("a referenced schema is required")
+	}
+
+	ref, err := buildSchema(cfg.Ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "referenced schema")
+	}
+
+	edge := &Edge{
+		Name:        cfg.Name,
+		StructField: stringsx.ToCamel(cfg.Name),
+		Kind:        cfg.Kind,
+		Ref:         ref,
+	}
+
+	if len(cfg.StructField) > 0 {
+		edge.StructField = cfg.StructField
+	}
+
+	switch cfg.Kind {
+	case nero.EdgeHasMany:
+		if schema.Ident == nil {
+			return nil, errors.New("has-many edge requires a single identity column")
+		}
+		edge.Col = schema.Ident
+		edge.RefCol = ref.Col(cfg.FK)
+	case nero.EdgeBelongsTo:
+		if ref.Ident == nil {
+			return nil, errors.New("belongs-to edge requires a single identity column in the referenced schema")
+		}
+		edge.Col = schema.Col(cfg.FK)
+		edge.RefCol = ref.Ident
+	case nero.EdgeManyToMany:
+		if schema.Ident == nil || ref.Ident == nil {
+			return nil, errors.New("many-to-many edge requires a single identity column in both schemas")
+		}
+
+		if cfg.Join == "" || cfg.FK == "" || cfg.RefFK == "" {
+			return nil, errors.New("many-to-many edge requires the join collection and columns")
+		}
+
+		edge.Col = schema.Ident
+		edge.RefCol = ref.Ident
+		edge.Join = cfg.Join
+		edge.JoinCol = cfg.FK
+		edge.JoinRefCol = cfg.RefFK
+		if edge.Col.Type.IsNillable() || edge.RefCol.Type.IsNillable() {
+			return nil, errors.New("identity columns can't be nillable")
+		}
+
+		return edge, nil
+	default:
+		return nil, errors.Errorf("unknown edge kind %d", cfg.Kind)
+	}
+
+	if edge.Col == nil || edge.RefCol == nil {
+		return nil, errors.Errorf("foreign key column %q not found", cfg.FK)
+	}
+
+	if edge.Col.Type.IsNillable() {
+		return nil, errors.Errorf("foreign key column %q can't be nillable", cfg.FK)
+	}
+
+	if edge.Col.Type.T() != edge.RefCol.Type.T() {
+		return nil, errors.Errorf("foreign key column %q type mismatch", cfg.FK)
+	}
+
+	return edge, nil
+}
+
+// IsHasMany returns true if the edge is a has-many edge
+func (e *Edge) IsHasMany() bool {
+	return e.Kind == nero.EdgeHasMany
+}
+
+// IsManyToMany returns true if the edge is a many-to-many edge
+func (e *Edge) IsManyToMany() bool {
+	return e.Kind == nero.EdgeManyToMany
+}
+
+// ExistsCollection returns the collection that is looked-up by the existence predicates
+func (e *Edge) ExistsCollection() string {
+	if e.IsManyToMany() {
+		return e.Join
+	}
+	return e.Ref.Collection
+}
+
+// ExistsCol returns the column of the ExistsCollection
+// that is matched against Col by the existence predicates
+func (e *Edge) ExistsCol() string {
+	if e.IsManyToMany() {
+		return e.JoinCol
+	}
+	return e.RefCol.Name
+}
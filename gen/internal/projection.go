@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/sf9v/mira"
+)
+
+// Projection is a DTO struct that the rows can be projected into
+type Projection struct {
+	// Type is the type info of the DTO
+	Type *mira.Type
+	// Fields are the fields of the DTO
+	Fields []*ProjectionField
+}
+
+// ProjectionField is a field of the DTO that is mapped to a column
+type ProjectionField struct {
+	// Name is the struct field name
+	Name string
+	// Col is the column of the field
+	Col *Col
+}
+
+func buildProjection(schema *Schema, v interface{}) (*Projection, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, errors.New("projection should be a struct")
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("projection %s should be a struct", t)
+	}
+
+	proj := &Projection{
+		Type: mira.NewType(reflect.New(t).Interface()),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" || sf.Tag.Get("nero") == "-" {
+			// unexported or ignored
+			continue
+		}
+
+		var col *Col
+		if name := sf.Tag.Get("nero"); name != "" {
+			col = schema.Col(name)
+		} else {
+			for _, c := range schema.Cols {
+				if c.Field() == sf.Name {
+					col = c
+					break
+				}
+			}
+		}
+
+		if col == nil {
+			return nil, errors.Errorf("field %q of %s doesn't match any column", sf.Name, t)
+		}
+
+		if sf.Type != col.Type.T() {
+			return nil, errors.Errorf("field %q of %s should be a %s to match column %q",
+				sf.Name, t, col.Type.T(), col.Name)
+		}
+
+		proj.Fields = append(proj.Fields, &ProjectionField{
+			Name: sf.Name,
+			Col:  col,
+		})
+	}
+
+	if len(proj.Fields) == 0 {
+		return nil, errors.Errorf("projection %s doesn't have any column", t)
+	}
+
+	return proj, nil
+}
@@ -13,10 +13,15 @@ import (
 
 // Schema is an internal schema
 type Schema struct {
-	Collection    string
-	Type          *mira.Type
-	Ident         *Col
+	Collection string
+	Type       *mira.Type
+	// Ident is the identity column, nil if the identity is composite
+	Ident *Col
+	// Idents are the identity columns
+	Idents        []*Col
 	Cols          []*Col
+	Edges         []*Edge
+	Projections   []*Projection
 	Pkg           string
 	SchemaImports []string
 	ColumnImports []string
@@ -25,6 +30,44 @@ type Schema struct {
 
 // BuildSchema builds schema from a nero.Schemaer to Schema
 func BuildSchema(s nero.Schemaer) (*Schema, error) {
+	schema, err := buildSchema(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edge := range s.Schema().Edges {
+		e, err := buildEdge(schema, edge.Cfg())
+		if err != nil {
+			return nil, errors.Wrapf(err, "edge %q", edge.Cfg().Name)
+		}
+
+		refImport := e.Ref.Type.PkgPath()
+		if !containsStr(schema.SchemaImports, refImport) {
+			schema.SchemaImports = append(schema.SchemaImports, refImport)
+		}
+
+		schema.Edges = append(schema.Edges, e)
+	}
+
+	for _, v := range s.Schema().Projections {
+		proj, err := buildProjection(schema, v)
+		if err != nil {
+			return nil, err
+		}
+
+		projImport := proj.Type.PkgPath()
+		if !containsStr(schema.SchemaImports, projImport) {
+			schema.SchemaImports = append(schema.SchemaImports, projImport)
+		}
+
+		schema.Projections = append(schema.Projections, proj)
+	}
+
+	return schema, nil
+}
+
+// buildSchema builds the schema without the edges
+func buildSchema(s nero.Schemaer) (*Schema, error) {
 	ns := s.Schema()
 	st := mira.NewType(s)
 
@@ -46,7 +89,6 @@ func BuildSchema(s nero.Schemaer) (*Schema, error) {
 
 	colImportMap := map[string]int{}
 
-	identCnt := 0
 	for _, column := range ns.Columns {
 		cfg := column.Cfg()
 		col := &Col{
@@ -57,15 +99,19 @@ func BuildSchema(s nero.Schemaer) (*Schema, error) {
 			Auto:             cfg.Auto,
 			Nullable:         cfg.Nullable,
 			ColumnComparable: cfg.ColumnComparable,
+			TextSearch:       cfg.TextSearch,
 		}
 
 		if len(cfg.StructField) > 0 {
 			col.StructField = cfg.StructField
 		}
 
+		if col.IsFullText() && !col.IsString() {
+			return nil, errors.Errorf("full-text searchable column %q should be a string", col.Name)
+		}
+
 		if cfg.Ident {
-			schema.Ident = col
-			identCnt++
+			schema.Idents = append(schema.Idents, col)
 		}
 
 		if col.Type.PkgPath() != "" {
@@ -82,13 +128,76 @@ func BuildSchema(s nero.Schemaer) (*Schema, error) {
 
 	schema.ColumnImports = columnImports
 
-	if identCnt == 0 {
+	if len(schema.Idents) == 0 {
 		return nil, errors.New("an identity column is required")
 	}
 
-	if identCnt > 1 {
-		return nil, errors.New("only one identity column is allowed")
+	if len(schema.Idents) == 1 {
+		schema.Ident = schema.Idents[0]
 	}
 
 	return schema, nil
 }
+
+// ManyToManyEdges returns the many-to-many edges
+func (s *Schema) ManyToManyEdges() []*Edge {
+	edges := []*Edge{}
+	for _, e := range s.Edges {
+		if e.IsManyToMany() {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Col returns the column with the name, nil if not found
+func (s *Schema) Col(name string) *Col {
+	for _, col := range s.Cols {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func containsStr(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCompositeIdent returns true if the schema has more than one identity column
+func (s *Schema) HasCompositeIdent() bool {
+	return len(s.Idents) > 1
+}
+
+// HasAutoIdent returns true if any of the identity columns is auto-filled
+func (s *Schema) HasAutoIdent() bool {
+	for _, col := range s.Idents {
+		if col.Auto {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFullText returns true if any of the columns is full-text searchable
+func (s *Schema) HasFullText() bool {
+	for _, col := range s.Cols {
+		if col.IsFullText() {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentV returns a value of the identity type, nil if the identity is composite
+func (s *Schema) IdentV() interface{} {
+	if s.Ident == nil {
+		return nil
+	}
+	return s.Ident.Type.V()
+}
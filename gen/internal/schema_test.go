@@ -26,6 +26,16 @@ func (*example2) Schema() *nero.Schema {
 	}
 }
 
+type example3 struct{}
+
+func (*example3) Schema() *nero.Schema {
+	return &nero.Schema{
+		Columns: []*nero.Column{
+			nero.NewColumn("id", int64(0)).Ident().FullText("english"),
+		},
+	}
+}
+
 func TestBuildSchema(t *testing.T) {
 	schema, err := BuildSchema(new(example.User))
 	require.NoError(t, err)
@@ -44,7 +54,128 @@ func TestBuildSchema(t *testing.T) {
 	_, err = BuildSchema(new(example1))
 	assert.Error(t, err)
 
-	// multiple idents defined
-	_, err = BuildSchema(new(example2))
+	// composite idents
+	schema, err = BuildSchema(new(example2))
+	require.NoError(t, err)
+	assert.True(t, schema.HasCompositeIdent())
+	assert.False(t, schema.HasAutoIdent())
+	assert.False(t, schema.HasFullText())
+	assert.Nil(t, schema.Ident)
+	assert.Nil(t, schema.IdentV())
+	assert.Len(t, schema.Idents, 2)
+
+	// full-text search on a non-string column
+	_, err = BuildSchema(new(example3))
+	assert.Error(t, err)
+}
+
+type parent struct{}
+
+func (*parent) Schema() *nero.Schema {
+	return &nero.Schema{
+		Columns: []*nero.Column{
+			nero.NewColumn("id", int64(0)).Ident(),
+		},
+		Edges: []*nero.Edge{
+			nero.HasMany("children", new(child), "parent_id"),
+			nero.ManyToMany("tags", new(tag), "parent_tags", "parent_id", "tag_id"),
+		},
+	}
+}
+
+type tag struct{}
+
+func (*tag) Schema() *nero.Schema {
+	return &nero.Schema{
+		Columns: []*nero.Column{
+			nero.NewColumn("id", int64(0)).Ident(),
+		},
+	}
+}
+
+type child struct{}
+
+func (*child) Schema() *nero.Schema {
+	return &nero.Schema{
+		Columns: []*nero.Column{
+			nero.NewColumn("id", int64(0)).Ident(),
+			nero.NewColumn("parent_id", int64(0)),
+			nero.NewColumn("name", ""),
+		},
+		Edges: []*nero.Edge{
+			nero.BelongsTo("parent", new(parent), "parent_id"),
+			nero.BelongsTo("named", new(parent), "name"),
+		},
+	}
+}
+
+type summary struct {
+	ID   int64
+	Name string `nero:"name"`
+	note string
+}
+
+type projected struct{ projs []interface{} }
+
+func (p *projected) Schema() *nero.Schema {
+	return &nero.Schema{
+		Columns: []*nero.Column{
+			nero.NewColumn("id", int64(0)).StructField("ID").Ident(),
+			nero.NewColumn("name", ""),
+		},
+		Projections: p.projs,
+	}
+}
+
+func TestBuildSchemaProjections(t *testing.T) {
+	schema, err := BuildSchema(&projected{projs: []interface{}{&summary{}}})
+	require.NoError(t, err)
+	require.Len(t, schema.Projections, 1)
+
+	proj := schema.Projections[0]
+	assert.Equal(t, "summary", proj.Type.Name())
+	require.Len(t, proj.Fields, 2)
+	assert.Equal(t, "ID", proj.Fields[0].Name)
+	assert.Equal(t, "id", proj.Fields[0].Col.Name)
+	assert.Equal(t, "name", proj.Fields[1].Col.Name)
+
+	// not a struct
+	_, err = BuildSchema(&projected{projs: []interface{}{""}})
+	assert.Error(t, err)
+
+	// unknown column
+	_, err = BuildSchema(&projected{projs: []interface{}{&struct {
+		Age int
+	}{}}})
+	assert.Error(t, err)
+
+	// type mismatch
+	_, err = BuildSchema(&projected{projs: []interface{}{&struct {
+		ID string
+	}{}}})
+	assert.Error(t, err)
+}
+
+func TestBuildSchemaEdges(t *testing.T) {
+	schema, err := BuildSchema(new(parent))
+	require.NoError(t, err)
+	require.Len(t, schema.Edges, 2)
+
+	edge := schema.Edges[0]
+	assert.True(t, edge.IsHasMany())
+	assert.Equal(t, "Children", edge.StructField)
+	assert.Equal(t, "id", edge.Col.Name)
+	assert.Equal(t, "parent_id", edge.RefCol.Name)
+	assert.Empty(t, edge.Ref.Edges)
+
+	edge = schema.Edges[1]
+	assert.True(t, edge.IsManyToMany())
+	assert.Equal(t, "parent_tags", edge.ExistsCollection())
+	assert.Equal(t, "parent_id", edge.ExistsCol())
+	assert.Equal(t, "tag_id", edge.JoinRefCol)
+	assert.Len(t, schema.ManyToManyEdges(), 1)
+
+	// type mismatch between "name" and "id"
+	_, err = BuildSchema(new(child))
 	assert.Error(t, err)
 }
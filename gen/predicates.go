@@ -11,8 +11,10 @@ import (
 func newPredicatesFile(schema *gen.Schema) (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
 	v := struct {
-		Ops    []comparison.Operator
-		Schema *gen.Schema
+		Ops       []comparison.Operator
+		StringOps []comparison.Operator
+		RangeOps  []comparison.Operator
+		Schema    *gen.Schema
 	}{
 		Ops: []comparison.Operator{
 			comparison.Eq,
@@ -26,6 +28,18 @@ func newPredicatesFile(schema *gen.Schema) (*bytes.Buffer, error) {
 			comparison.In,
 			comparison.NotIn,
 		},
+		StringOps: []comparison.Operator{
+			comparison.Like,
+			comparison.NotLike,
+			comparison.ILike,
+			comparison.HasPrefix,
+			comparison.HasSuffix,
+			comparison.Contains,
+		},
+		RangeOps: []comparison.Operator{
+			comparison.Between,
+			comparison.NotBetween,
+		},
 		Schema: schema,
 	}
 
@@ -38,6 +52,17 @@ func newPredicatesFile(schema *gen.Schema) (*bytes.Buffer, error) {
 			return op == comparison.In ||
 				op == comparison.NotIn
 		},
+		"stringOpArg": func(op comparison.Operator) string {
+			switch op {
+			case comparison.HasPrefix:
+				return "prefix"
+			case comparison.HasSuffix:
+				return "suffix"
+			case comparison.Contains:
+				return "substr"
+			}
+			return "pattern"
+		},
 	}).Parse(predicatesTmpl)
 	if err != nil {
 		return nil, err
@@ -130,6 +155,123 @@ type PredFunc func(*comparison.Predicates)
 				{{end}}
 			{{end}}
 		{{end -}}
+		{{if $col.IsString -}}
+			{{range $op := $.StringOps -}}
+				// {{$col.Field}}{{$op.String}} is a "{{$op.Desc}}" operator on "{{$col.Name}}" column
+				func {{$col.Field}}{{$op.String}} ({{stringOpArg $op}} string) PredFunc {
+					return func(pb *comparison.Predicates) {
+						pb.Add(&comparison.Predicate{
+							Col: "{{$col.Name}}",
+							Op: comparison.{{$op.String}},
+							Arg: {{stringOpArg $op}},
+						})
+					}
+				}
+
+			{{end -}}
+		{{end -}}
+		{{if $col.IsOrderable -}}
+			{{range $op := $.RangeOps -}}
+				// {{$col.Field}}{{$op.String}} is a "{{$op.Desc}}" operator on "{{$col.Name}}" column, the bounds are inclusive
+				func {{$col.Field}}{{$op.String}} (lo, hi {{printf "%T" $col.Type.V}}) PredFunc {
+					return func(pb *comparison.Predicates) {
+						pb.Add(&comparison.Predicate{
+							Col: "{{$col.Name}}",
+							Op: comparison.{{$op.String}},
+							Arg: []interface{}{lo, hi},
+						})
+					}
+				}
+
+			{{end -}}
+		{{end -}}
+		{{if $col.IsFullText -}}
+			// {{$col.Field}}Matches is a full-text search on "{{$col.Name}}" column
+			func {{$col.Field}}Matches (query string) PredFunc {
+				return func(pb *comparison.Predicates) {
+					pb.Add(&comparison.Predicate{
+						Col: "{{$col.Name}}",
+						Op: comparison.Matches,
+						Arg: &comparison.TextSearch{
+							Config: "{{$col.TextSearch}}",
+							Query: query,
+						},
+					})
+				}
+			}
+
+		{{end -}}
 	{{end}}
 {{end -}}
+
+{{range $edge := .Schema.Edges -}}
+// Has{{$edge.StructField}} is an "exists" operator on the "{{$edge.Name}}" edge
+func Has{{$edge.StructField}}() PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "{{$edge.Col.Name}}",
+			Op: comparison.Exists,
+			Arg: &comparison.Edge{
+				Name: "{{$edge.Name}}",
+				Collection: "{{$edge.ExistsCollection}}",
+				Col: "{{$edge.ExistsCol}}",
+			},
+		})
+	}
+}
+
+// HasNo{{$edge.StructField}} is a "not exists" operator on the "{{$edge.Name}}" edge
+func HasNo{{$edge.StructField}}() PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "{{$edge.Col.Name}}",
+			Op: comparison.NotExists,
+			Arg: &comparison.Edge{
+				Name: "{{$edge.Name}}",
+				Collection: "{{$edge.ExistsCollection}}",
+				Col: "{{$edge.ExistsCol}}",
+			},
+		})
+	}
+}
+
+{{end -}}
+// And is an "and" operator on the predicates
+func And(pfs ...PredFunc) PredFunc {
+	return groupPredicates(comparison.And, pfs)
+}
+
+// Or is an "or" operator on the predicates
+func Or(pfs ...PredFunc) PredFunc {
+	return groupPredicates(comparison.Or, pfs)
+}
+
+// Not is a "not" operator on the predicate
+func Not(pf PredFunc) PredFunc {
+	return groupPredicates(comparison.Not, []PredFunc{pf})
+}
+
+func groupPredicates(op comparison.Operator, pfs []PredFunc) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Op: op,
+			Arg: buildPredicates(pfs).All(),
+		})
+	}
+}
+
+{{if .Schema.HasCompositeIdent -}}
+// IdentEq is an "equal" operator on all the identity columns
+func IdentEq(ident Ident) PredFunc {
+	return func(pb *comparison.Predicates) {
+		{{range $col := .Schema.Idents -}}
+			pb.Add(&comparison.Predicate{
+				Col: "{{$col.Name}}",
+				Op: comparison.Eq,
+				Arg: ident.{{$col.Field}},
+			})
+		{{end -}}
+	}
+}
+{{end -}}
 `
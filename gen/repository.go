@@ -3,6 +3,7 @@ package gen
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/jinzhu/inflection"
@@ -16,6 +17,9 @@ func newRepositoryFile(schema *gen.Schema) (*bytes.Buffer, error) {
 		"type": func(v interface{}) string {
 			return fmt.Sprintf("%T", v)
 		},
+		"elem": func(v interface{}) string {
+			return strings.TrimPrefix(fmt.Sprintf("%T", v), "*")
+		},
 	}).Parse(repositoryTmpl)
 	if err != nil {
 		return nil, err
@@ -34,6 +38,9 @@ import (
 	"context"
 	"github.com/pkg/errors"
 	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/sort"
 	{{range $import := .SchemaImports -}}
 		"{{$import}}"
 	{{end -}}
@@ -47,13 +54,25 @@ type Repository interface {
 	// Tx begins a new transaction
 	Tx(context.Context) (nero.Tx, error)
 	// Create creates a new {{.Type.Name}}
-	Create(context.Context, *Creator) (id {{type .Ident.Type.V}}, err error)
+	Create(context.Context, *Creator) (id {{if .HasCompositeIdent}}Ident{{else}}{{type .Ident.Type.V}}{{end}}, err error)
 	// CreateTx creates a new type .Type.Name}} inside a transaction
-	CreateTx(context.Context, nero.Tx, *Creator) (id {{type .Ident.Type.V}}, err error)
-	// CreateMany creates many {{.Type.Name}}
-	CreateMany(context.Context, ...*Creator) error
-	// CreateManyTx creates many {{.Type.Name}} inside a transaction
-	CreateManyTx(context.Context, nero.Tx, ...*Creator) error
+	CreateTx(context.Context, nero.Tx, *Creator) (id {{if .HasCompositeIdent}}Ident{{else}}{{type .Ident.Type.V}}{{end}}, err error)
+	// CreateReturning creates a new {{.Type.Name}} and returns the created row
+	CreateReturning(context.Context, *Creator) ({{type .Type.V}}, error)
+	// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+	CreateReturningTx(context.Context, nero.Tx, *Creator) ({{type .Type.V}}, error)
+	// CreateMany creates many {{.Type.Name}} and returns the identities in order
+	CreateMany(context.Context, ...*Creator) (ids []{{if .HasCompositeIdent}}Ident{{else}}{{type .Ident.Type.V}}{{end}}, err error)
+	// CreateManyTx creates many {{.Type.Name}} inside a transaction and returns the identities in order
+	CreateManyTx(context.Context, nero.Tx, ...*Creator) (ids []{{if .HasCompositeIdent}}Ident{{else}}{{type .Ident.Type.V}}{{end}}, err error)
+	// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+	Upsert(context.Context, *Upserter) (id {{if .HasCompositeIdent}}Ident{{else}}{{type .Ident.Type.V}}{{end}}, err error)
+	// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+	UpsertTx(context.Context, nero.Tx, *Upserter) (id {{if .HasCompositeIdent}}Ident{{else}}{{type .Ident.Type.V}}{{end}}, err error)
+	// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+	UpsertMany(context.Context, *Upserter) error
+	// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+	UpsertManyTx(context.Context, nero.Tx, *Upserter) error
 	// Query queries many {{.Type.Name}}
 	Query(context.Context, *Queryer) ([]{{type .Type.V}}, error)
 	// QueryTx queries many {{.Type.V}} inside a transaction
@@ -62,19 +81,62 @@ type Repository interface {
 	QueryOne(context.Context, *Queryer) ({{type .Type.V}}, error)
 	// QueryOneTx queries one {{.Type.Name}} inside a transaction
 	QueryOneTx(context.Context, nero.Tx, *Queryer) ({{type .Type.V}}, error)
+	// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+	QueryPage(context.Context, *Queryer) (*Page, error)
+	// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+	QueryPageTx(context.Context, nero.Tx, *Queryer) (*Page, error)
+	// Count counts the {{.Type.Name}} rows that match the predicates of the query
+	Count(context.Context, *Queryer) (int64, error)
+	// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+	CountTx(context.Context, nero.Tx, *Queryer) (int64, error)
+	// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+	Exists(context.Context, *Queryer) (bool, error)
+	// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+	ExistsTx(context.Context, nero.Tx, *Queryer) (bool, error)
 	// Update updates {{.Type.Name}}
 	Update(context.Context, *Updater) (rowsAffected int64, err error)
 	// UpdateTx updates {{.Type.Name}} inside a transaction
 	UpdateTx(context.Context, nero.Tx, *Updater) (rowsAffected int64, err error)
+	// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+	UpdateReturning(context.Context, *Updater) ([]{{type .Type.V}}, error)
+	// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+	UpdateReturningTx(context.Context, nero.Tx, *Updater) ([]{{type .Type.V}}, error)
 	// Delete deletes {{.Type.Name}}
 	Delete(context.Context, *Deleter) (rowsAffected int64, err error)
 	// Delete deletes {{.Type.Name}} inside a transaction
 	DeleteTx(context.Context, nero.Tx, *Deleter) (rowsAffected int64, err error)
+	// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+	DeleteReturning(context.Context, *Deleter) ([]{{type .Type.V}}, error)
+	// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+	DeleteReturningTx(context.Context, nero.Tx, *Deleter) ([]{{type .Type.V}}, error)
 	// Aggregate performs aggregate query
 	Aggregate(context.Context, *Aggregator) error
 	// Aggregate performs aggregate query inside a transaction
 	AggregateTx(context.Context, nero.Tx, *Aggregator) error
+	{{range $edge := .ManyToManyEdges -}}
+	// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+	Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error
+	// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+	Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error
+	// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+	Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error
+	// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+	Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error
+	// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+	Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error
+	// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+	Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error
+	{{end -}}
+}
+
+{{if .HasCompositeIdent -}}
+// Ident is the composite identity of {{.Type.Name}}
+type Ident struct {
+	{{range $col := .Idents -}}
+		{{$col.Field}} {{type $col.Type.V}}
+	{{end -}}
 }
+{{end -}}
 
 // Creator is a create builder for {{.Type.Name}}
 type Creator struct {
@@ -83,6 +145,7 @@ type Creator struct {
 		{{$col.Identifier}} {{type $col.Type.V}}
 		{{end -}}
 	{{end -}}
+	columns map[Column]bool
 }
 
 // NewCreator is a factory for Creator
@@ -95,17 +158,222 @@ func NewCreator() *Creator {
 		// {{$col.Field}} is a setter for {{$col.Identifier}}
 		func (c *Creator) {{$col.Field}}({{$col.Identifier}} {{type $col.Type.V}}) *Creator {
 			c.{{$col.Identifier}} = {{$col.Identifier}}
+			c.set(Column{{$col.Field}}, false)
 			return c
 		}
+
+		{{if $col.Nullable -}}
+		// SetNull{{$col.Field}} sets {{$col.Identifier}} to NULL
+		func (c *Creator) SetNull{{$col.Field}}() *Creator {
+			var {{$col.Identifier}} {{type $col.Type.V}}
+			c.{{$col.Identifier}} = {{$col.Identifier}}
+			c.set(Column{{$col.Field}}, true)
+			return c
+		}
+
+		{{end -}}
 	{{end -}}
 {{end -}}
 
+func (c *Creator) set(col Column, null bool) {
+	if c.columns == nil {
+		c.columns = map[Column]bool{}
+	}
+	c.columns[col] = null
+}
+
+// IsSet returns true if the column was explicitly set
+func (c *Creator) IsSet(col Column) bool {
+	_, ok := c.columns[col]
+	return ok
+}
+
+// IsNull returns true if the column was explicitly set to NULL
+func (c *Creator) IsNull(col Column) bool {
+	return c.columns[col]
+}
+
+// Columns returns the explicitly set columns of the create builder
+func (c *Creator) Columns() []Column {
+	cols := []Column{}
+	{{range $col := .Cols -}}
+		{{if ne $col.Auto true -}}
+			if c.IsSet(Column{{$col.Field}}) {
+				cols = append(cols, Column{{$col.Field}})
+			}
+		{{end -}}
+	{{end -}}
+	return cols
+}
+
+// groupCreators splits the creators into the runs of consecutive creators
+// that set the same columns, each run can be inserted in a single statement
+func groupCreators(cs []*Creator) [][]*Creator {
+	groups := [][]*Creator{}
+	for i, c := range cs {
+		if i > 0 && sameColumns(cs[i-1], c) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], c)
+			continue
+		}
+		groups = append(groups, []*Creator{c})
+	}
+	return groups
+}
+
+// sameColumns returns true if the creators set the same columns
+func sameColumns(a, b *Creator) bool {
+	if len(a.columns) != len(b.columns) {
+		return false
+	}
+	for col := range a.columns {
+		if !b.IsSet(col) {
+			return false
+		}
+	}
+	return true
+}
+
+// Upserter is an upsert builder for {{.Type.Name}}
+type Upserter struct {
+	cs        []*Creator
+	conflict  []Column
+	updates   []Column
+	doNothing bool
+}
+
+// NewUpserter is a factory for Upserter
+func NewUpserter(cs ...*Creator) *Upserter {
+	return &Upserter{cs: cs}
+}
+
+// Create adds creators to the upsert builder
+func (u *Upserter) Create(cs ...*Creator) *Upserter {
+	u.cs = append(u.cs, cs...)
+	return u
+}
+
+// OnConflict sets the conflict target, defaults to the identity unless
+// it is auto-filled in which case the conflict target is required
+func (u *Upserter) OnConflict(cols ...Column) *Upserter {
+	u.conflict = append(u.conflict, cols...)
+	return u
+}
+
+// Update sets the columns to update on conflict,
+// defaults to the set columns of the creators
+func (u *Upserter) Update(cols ...Column) *Upserter {
+	u.updates = append(u.updates, cols...)
+	return u
+}
+
+// DoNothing leaves the conflicting rows as they are
+func (u *Upserter) DoNothing() *Upserter {
+	u.doNothing = true
+	return u
+}
+
+// Creators returns the creators of the upsert builder
+func (u *Upserter) Creators() []*Creator {
+	return u.cs
+}
+
+// ConflictColumns returns the conflict target of the upsert builder
+func (u *Upserter) ConflictColumns() []Column {
+	{{if .HasAutoIdent -}}
+	// the auto-filled identities can't be set by the creators,
+	// so they are not a default conflict target
+	return u.conflict
+	{{- else -}}
+	if len(u.conflict) > 0 {
+		return u.conflict
+	}
+
+	return []Column{
+		{{range $col := .Idents -}}
+			Column{{$col.Field}},
+		{{end -}}
+	}
+	{{- end}}
+}
+
+// UpdateColumns returns the columns to update on conflict,
+// it is empty if the conflicting rows are left as they are
+func (u *Upserter) UpdateColumns() []Column {
+	if u.doNothing {
+		return []Column{}
+	}
+
+	if len(u.updates) > 0 {
+		return u.updates
+	}
+
+	conflict := map[Column]struct{}{}
+	for _, col := range u.ConflictColumns() {
+		conflict[col] = struct{}{}
+	}
+
+	cols := []Column{}
+	{{range $col := .Cols -}}
+		{{if ne $col.Auto true -}}
+			if _, ok := conflict[Column{{$col.Field}}]; !ok && u.isSet(Column{{$col.Field}}) {
+				cols = append(cols, Column{{$col.Field}})
+			}
+		{{end -}}
+	{{end -}}
+	return cols
+}
+
+func (u *Upserter) isSet(col Column) bool {
+	for _, c := range u.cs {
+		if c.IsSet(col) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that the conflict columns are set in every creator
+// and that the creators set the same columns
+func (u *Upserter) validate(one bool) error {
+	if one && len(u.cs) != 1 {
+		return errors.Errorf("expecting 1 creator, got %d", len(u.cs))
+	}
+
+	if len(u.ConflictColumns()) == 0 {
+		return errors.New("conflict target required, use OnConflict to set it")
+	}
+
+	for _, c := range u.cs {
+		for _, col := range u.ConflictColumns() {
+			if !c.IsSet(col) {
+				return errors.Errorf("conflict column %q is not set", col)
+			}
+		}
+
+		if !sameColumns(c, u.cs[0]) {
+			return errors.New("expecting the creators to set the same columns")
+		}
+	}
+
+	return nil
+}
+
 // Queryer is a query builder for {{.Type.Name}}
 type Queryer struct {
-	limit  uint
-	offset uint
-	pfs    []PredFunc
-	sfs    []SortFunc
+	limit    uint
+	offset   uint
+	pfs      []PredFunc
+	sfs      []SortFunc
+	cols     []Column
+	after    nero.Cursor
+	before   nero.Cursor
+	paged    bool
+	reversed bool
+	lock     nero.LockStrength
+	wait     nero.LockWait
+	{{range $edge := .Edges -}}
+		with{{$edge.StructField}} bool
+	{{end -}}
 }
 
 // NewQueryer is a factory for Queryer
@@ -137,6 +405,458 @@ func (q *Queryer) Offset(offset uint) *Queryer {
 	return q
 }
 
+// Select limits the selected columns, the other fields of the rows are left empty
+func (q *Queryer) Select(cols ...Column) *Queryer {
+	q.cols = append(q.cols, cols...)
+	return q
+}
+
+// Selected returns the selected columns of the query, nil if all the columns are selected
+func (q *Queryer) Selected() []Column {
+	return q.cols
+}
+
+// ForUpdate locks the queried rows for update until the end of the transaction
+func (q *Queryer) ForUpdate() *Queryer {
+	q.lock = nero.ForUpdate
+	return q
+}
+
+// ForNoKeyUpdate locks the queried rows for update, except
+// for the identities, until the end of the transaction
+func (q *Queryer) ForNoKeyUpdate() *Queryer {
+	q.lock = nero.ForNoKeyUpdate
+	return q
+}
+
+// ForShare locks the queried rows against update until the end of the transaction
+func (q *Queryer) ForShare() *Queryer {
+	q.lock = nero.ForShare
+	return q
+}
+
+// NoWait fails the query instead of waiting for the rows that are locked
+// by the other transactions, it has no effect if the rows aren't locked
+func (q *Queryer) NoWait() *Queryer {
+	q.wait = nero.NoWait
+	return q
+}
+
+// SkipLocked skips the rows that are locked by the other
+// transactions, it has no effect if the rows aren't locked
+func (q *Queryer) SkipLocked() *Queryer {
+	q.wait = nero.SkipLocked
+	return q
+}
+
+// Lock returns the row lock of the query
+func (q *Queryer) Lock() (nero.LockStrength, nero.LockWait) {
+	return q.lock, q.wait
+}
+
+// allColumns returns all the columns of {{.Type.Name}} in the order of the schema
+func allColumns() []Column {
+	return []Column{
+		{{range $col := .Cols -}}
+			Column{{$col.Field}},
+		{{end -}}
+	}
+}
+
+// columns returns the columns to be queried, it includes the columns
+// that are needed for loading the edges and the keyset pagination
+func (q *Queryer) columns() []Column {
+	if len(q.cols) == 0 {
+		return allColumns()
+	}
+
+	cols := []Column{}
+	seen := map[Column]bool{}
+	add := func(col Column) {
+		if !seen[col] {
+			seen[col] = true
+			cols = append(cols, col)
+		}
+	}
+	for _, col := range q.cols {
+		add(col)
+	}
+	{{range $edge := .Edges -}}
+	if q.with{{$edge.StructField}} {
+		add(Column{{$edge.Col.Field}})
+	}
+	{{end -}}
+	if q.paged || q.after != "" || q.before != "" {
+		keys, _ := q.keys()
+		for _, key := range keys {
+			add(columnOf(key.Col))
+		}
+	}
+
+	return cols
+}
+
+// columnOf returns the column with the name
+func columnOf(name string) Column {
+	switch name {
+	{{range $col := .Cols -}}
+	case "{{$col.Name}}":
+		return Column{{$col.Field}}
+	{{end -}}
+	}
+	return Column(-1)
+}
+
+// project returns a copy of the row with only the columns
+func project(row {{type .Type.V}}, cols []Column) {{type .Type.V}} {
+	cp := new({{elem .Type.V}})
+	for _, col := range cols {
+		switch col {
+		{{range $col := .Cols -}}
+		case Column{{$col.Field}}:
+			cp.{{$col.Field}} = row.{{$col.Field}}
+		{{end -}}
+		}
+	}
+	return cp
+}
+
+{{range $proj := .Projections -}}
+// Select{{$proj.Type.Name}} selects the columns of {{$proj.Type.Name}}
+func (q *Queryer) Select{{$proj.Type.Name}}() *Queryer {
+	return q.Select(
+		{{range $field := $proj.Fields -}}
+			Column{{$field.Col.Field}},
+		{{end -}}
+	)
+}
+
+// To{{plural $proj.Type.Name}} projects the rows into {{$proj.Type.Name}}
+func To{{plural $proj.Type.Name}}(rows []{{type $.Type.V}}) []{{type $proj.Type.V}} {
+	result := make([]{{type $proj.Type.V}}, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &{{elem $proj.Type.V}}{
+			{{range $field := $proj.Fields -}}
+				{{$field.Name}}: row.{{$field.Col.Field}},
+			{{end -}}
+		})
+	}
+	return result
+}
+
+{{end -}}
+// After queries the rows after the cursor, see Page
+func (q *Queryer) After(cursor nero.Cursor) *Queryer {
+	q.after, q.before = cursor, ""
+	return q
+}
+
+// Before queries the rows before the cursor, see Page
+func (q *Queryer) Before(cursor nero.Cursor) *Queryer {
+	q.before, q.after = cursor, ""
+	return q
+}
+
+// Cursors returns the after and before cursors of the query
+func (q *Queryer) Cursors() (after, before nero.Cursor) {
+	return q.after, q.before
+}
+
+// Predicates returns the predicates of the query
+func (q *Queryer) Predicates() *comparison.Predicates {
+	return buildPredicates(q.pfs)
+}
+
+// Sorts returns the sorting expressions of the query
+func (q *Queryer) Sorts() *sort.Sorts {
+	return buildSorts(q.sfs)
+}
+
+// LimitOffset returns the limit and offset of the query
+func (q *Queryer) LimitOffset() (limit, offset uint) {
+	return q.limit, q.offset
+}
+
+// Page is a page of {{.Type.Name}}, pass the cursors to
+// Queryer.After and Queryer.Before to query the adjacent pages
+type Page struct {
+	Rows []{{type .Type.V}}
+	// Next is the cursor of the last row, empty if there are no rows
+	Next nero.Cursor
+	// Prev is the cursor of the first row, empty if there are no rows
+	Prev nero.Cursor
+}
+
+// newPage creates a page of the rows queried by the query
+func newPage(q *Queryer, rows []{{type .Type.V}}) (*Page, error) {
+	page := &Page{Rows: rows}
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	keys, err := q.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	page.Prev, err = newCursor(keys, rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	page.Next, err = newCursor(keys, rows[len(rows)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// filter returns a query with only the predicates of the query
+func (q *Queryer) filter() *Queryer {
+	return &Queryer{pfs: q.pfs}
+}
+
+// page returns a copy of the query that is sorted by the keys of the pagination
+func (q *Queryer) page() *Queryer {
+	cp := *q
+	cp.paged = true
+	return &cp
+}
+
+// keys returns the sorts of the keyset pagination, the identity is the tie-breaker
+func (q *Queryer) keys() ([]*sort.Sort, error) {
+	keys := []*sort.Sort{}
+	for _, s := range buildSorts(q.sfs).All() {
+		if s.Rank != nil {
+			return nil, errors.New("cannot paginate by the full-text search rank")
+		}
+		keys = append(keys, s)
+	}
+
+	{{range $col := .Idents -}}
+	if !hasKey(keys, "{{$col.Name}}") {
+		keys = append(keys, &sort.Sort{Col: "{{$col.Name}}", Direction: sort.Asc})
+	}
+	{{end -}}
+
+	return keys, nil
+}
+
+// keyset returns a copy of the query with the predicates and the sorts of the
+// keyset pagination, the sorts are reversed when querying before a cursor
+func (q *Queryer) keyset() (*Queryer, error) {
+	if !q.paged && q.after == "" && q.before == "" {
+		return q, nil
+	}
+
+	keys, err := q.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	cp := *q
+	cp.after, cp.before = "", ""
+	cp.reversed = q.before != ""
+	cp.sfs = []SortFunc{}
+	for _, key := range keys {
+		key := *key
+		if cp.reversed {
+			key.Direction = reverseDirection(key.Direction)
+		}
+		cp.sfs = append(cp.sfs, func(s *sort.Sorts) {
+			s.Add(&key)
+		})
+	}
+
+	cursor := q.after
+	if cp.reversed {
+		cursor = q.before
+	}
+	if cursor == "" {
+		return &cp, nil
+	}
+
+	row := new({{elem .Type.V}})
+	cols, err := cursor.Decode(func(col string) interface{} {
+		return cursorField(row, col)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cols) != len(keys) {
+		return nil, errors.New("cursor doesn't match the sorts of the query")
+	}
+
+	or := []*comparison.Predicate{}
+	for i, key := range keys {
+		if cols[i] != key.Col {
+			return nil, errors.New("cursor doesn't match the sorts of the query")
+		}
+
+		val := cursorValue(row, key.Col)
+		if val == nil {
+			return nil, errors.Errorf("cursor has a null value for column %q", key.Col)
+		}
+
+		// the previous keys are equal and this one is after the cursor
+		and := []*comparison.Predicate{}
+		for _, prev := range keys[:i] {
+			and = append(and, &comparison.Predicate{
+				Col: prev.Col,
+				Op:  comparison.Eq,
+				Arg: cursorValue(row, prev.Col),
+			})
+		}
+
+		op := comparison.Gt
+		if (key.Direction == sort.Desc) != cp.reversed {
+			op = comparison.Lt
+		}
+		and = append(and, &comparison.Predicate{Col: key.Col, Op: op, Arg: val})
+		or = append(or, &comparison.Predicate{Op: comparison.And, Arg: and})
+	}
+
+	cp.pfs = append(append([]PredFunc{}, q.pfs...), func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{Op: comparison.Or, Arg: or})
+	})
+
+	return &cp, nil
+}
+
+// newCursor creates the cursor of the row
+func newCursor(keys []*sort.Sort, row {{type .Type.V}}) (nero.Cursor, error) {
+	cols := make([]string, 0, len(keys))
+	vals := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		cols = append(cols, key.Col)
+		vals = append(vals, cursorValue(row, key.Col))
+	}
+	return nero.NewCursor(cols, vals)
+}
+
+// cursorValue returns the value of the column, nil if it's null
+func cursorValue(row {{type .Type.V}}, col string) interface{} {
+	switch col {
+	{{range $col := .Cols -}}
+	case "{{$col.Name}}":
+		{{if $col.Nullable -}}
+		if row.{{$col.Field}} == nil {
+			return nil
+		}
+		{{end -}}
+		return row.{{$col.Field}}
+	{{end -}}
+	}
+	return nil
+}
+
+// cursorField returns the pointer to the field of the column
+func cursorField(row {{type .Type.V}}, col string) interface{} {
+	switch col {
+	{{range $col := .Cols -}}
+	case "{{$col.Name}}":
+		return &row.{{$col.Field}}
+	{{end -}}
+	}
+	return nil
+}
+
+func hasKey(keys []*sort.Sort, col string) bool {
+	for _, key := range keys {
+		if key.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+func reverseDirection(direction sort.Direction) sort.Direction {
+	if direction == sort.Desc {
+		return sort.Asc
+	}
+	return sort.Desc
+}
+
+// reverseRows reverses the rows in place
+func reverseRows(rows []{{type .Type.V}}) {
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
+
+{{if .Edges -}}
+{{range $edge := .Edges -}}
+// With{{$edge.StructField}} eager-loads the "{{$edge.Name}}" edge
+func (q *Queryer) With{{$edge.StructField}}() *Queryer {
+	q.with{{$edge.StructField}} = true
+	return q
+}
+
+{{end -}}
+
+// Edges returns the names of the edges to be eager-loaded
+func (q *Queryer) Edges() []string {
+	edges := []string{}
+	{{range $edge := .Edges -}}
+		if q.with{{$edge.StructField}} {
+			edges = append(edges, "{{$edge.Name}}")
+		}
+	{{end -}}
+	return edges
+}
+
+{{range $edge := .Edges -}}
+// edge{{$edge.StructField}}Keys returns the distinct keys of the "{{$edge.Name}}" edge
+func edge{{$edge.StructField}}Keys(rows []{{type $.Type.V}}) []{{type $edge.Col.Type.V}} {
+	keys := []{{type $edge.Col.Type.V}}{}
+	seen := map[{{type $edge.Col.Type.V}}]struct{}{}
+	for _, row := range rows {
+		if _, ok := seen[row.{{$edge.Col.Field}}]; ok {
+			continue
+		}
+		seen[row.{{$edge.Col.Field}}] = struct{}{}
+		keys = append(keys, row.{{$edge.Col.Field}})
+	}
+	return keys
+}
+
+{{if $edge.IsManyToMany -}}
+// setEdge{{$edge.StructField}} sets the "{{$edge.Name}}" edge of the rows from the associated rows by key
+func setEdge{{$edge.StructField}}(rows []{{type $.Type.V}}, related map[{{type $edge.Col.Type.V}}][]{{type $edge.Ref.Type.V}}) {
+	for _, row := range rows {
+		row.{{$edge.StructField}} = append([]{{type $edge.Ref.Type.V}}{}, related[row.{{$edge.Col.Field}}]...)
+	}
+}
+{{- else -}}
+// setEdge{{$edge.StructField}} sets the "{{$edge.Name}}" edge of the rows from the related rows
+func setEdge{{$edge.StructField}}(rows []{{type $.Type.V}}, related []{{type $edge.Ref.Type.V}}) {
+	{{if $edge.IsHasMany -}}
+		grouped := map[{{type $edge.Col.Type.V}}][]{{type $edge.Ref.Type.V}}{}
+		for _, r := range related {
+			grouped[r.{{$edge.RefCol.Field}}] = append(grouped[r.{{$edge.RefCol.Field}}], r)
+		}
+
+		for _, row := range rows {
+			row.{{$edge.StructField}} = append([]{{type $edge.Ref.Type.V}}{}, grouped[row.{{$edge.Col.Field}}]...)
+		}
+	{{- else -}}
+		byKey := map[{{type $edge.Col.Type.V}}]{{type $edge.Ref.Type.V}}{}
+		for _, r := range related {
+			byKey[r.{{$edge.RefCol.Field}}] = r
+		}
+
+		for _, row := range rows {
+			row.{{$edge.StructField}} = byKey[row.{{$edge.Col.Field}}]
+		}
+	{{- end}}
+}
+{{- end}}
+
+{{end -}}
+{{end -}}
+
 // Updater is an update builder for {{.Type.Name}}
 type Updater struct {
 	{{range $col := .Cols -}}
@@ -144,7 +864,28 @@ type Updater struct {
 		{{$col.Identifier}} {{type $col.Type.V}}
 		{{end -}}
 	{{end -}}
+	columns map[Column]bool
+	exprs []*updateExpr
 	pfs []PredFunc
+	all bool
+}
+
+// updateOp is the operator of an update expression
+type updateOp int
+
+const (
+	updateIncr updateOp = iota
+	updateDecr
+	updateCopy
+)
+
+// updateExpr is an update expression on a column
+type updateExpr struct {
+	col Column
+	op  updateOp
+	// arg is the amount for increments and decrements
+	// and the source column for copies
+	arg interface{}
 }
 
 // NewUpdater is a factory for Updater
@@ -155,22 +896,194 @@ func NewUpdater() *Updater {
 {{range $col := .Cols}}
 	{{if ne $col.Auto true -}}
 		// {{$col.Field}} is a setter for {{$col.Identifier}}
-		func (c *Updater) {{$col.Field}}({{$col.Identifier}} {{type $col.Type.V}}) *Updater {
-			c.{{$col.Identifier}} = {{$col.Identifier}}
-			return c
+		func (u *Updater) {{$col.Field}}({{$col.Identifier}} {{type $col.Type.V}}) *Updater {
+			u.{{$col.Identifier}} = {{$col.Identifier}}
+			u.set(Column{{$col.Field}}, false)
+			return u
+		}
+
+		{{if $col.Nullable -}}
+		// SetNull{{$col.Field}} sets {{$col.Identifier}} to NULL
+		func (u *Updater) SetNull{{$col.Field}}() *Updater {
+			var {{$col.Identifier}} {{type $col.Type.V}}
+			u.{{$col.Identifier}} = {{$col.Identifier}}
+			u.set(Column{{$col.Field}}, true)
+			return u
 		}
+
+		{{end -}}
+
+		{{if $col.IsNumeric -}}
+		// {{$col.Field}}Incr atomically increments {{$col.Identifier}} by n
+		func (u *Updater) {{$col.Field}}Incr(n {{type $col.Type.V}}) *Updater {
+			u.setExpr(Column{{$col.Field}}, updateIncr, n)
+			return u
+		}
+
+		// {{$col.Field}}Decr atomically decrements {{$col.Identifier}} by n
+		func (u *Updater) {{$col.Field}}Decr(n {{type $col.Type.V}}) *Updater {
+			u.setExpr(Column{{$col.Field}}, updateDecr, n)
+			return u
+		}
+
+		{{end -}}
+
+		{{if $col.ColumnComparable -}}
+		// {{$col.Field}}FromCol sets {{$col.Identifier}} to the value of col
+		func (u *Updater) {{$col.Field}}FromCol(col Column) *Updater {
+			u.setExpr(Column{{$col.Field}}, updateCopy, col)
+			return u
+		}
+
+		{{end -}}
 	{{end -}}
 {{end -}}
 
+func (u *Updater) set(col Column, null bool) {
+	u.unsetExpr(col)
+	if u.columns == nil {
+		u.columns = map[Column]bool{}
+	}
+	u.columns[col] = null
+}
+
+func (u *Updater) setExpr(col Column, op updateOp, arg interface{}) {
+	delete(u.columns, col)
+	u.unsetExpr(col)
+	u.exprs = append(u.exprs, &updateExpr{col: col, op: op, arg: arg})
+}
+
+func (u *Updater) unsetExpr(col Column) {
+	exprs := []*updateExpr{}
+	for _, e := range u.exprs {
+		if e.col != col {
+			exprs = append(exprs, e)
+		}
+	}
+	u.exprs = exprs
+}
+
+// apply applies the update expression to dst using the values of src
+func (e *updateExpr) apply(dst, src {{type .Type.V}}) error {
+	switch e.col {
+	{{range $col := .Cols -}}
+		{{if and (ne $col.Auto true) (or $col.IsNumeric $col.ColumnComparable) -}}
+		case Column{{$col.Field}}:
+			{{if $col.IsNumeric -}}
+			switch e.op {
+			case updateIncr:
+				dst.{{$col.Field}} = src.{{$col.Field}} + e.arg.({{type $col.Type.V}})
+				return nil
+			case updateDecr:
+				dst.{{$col.Field}} = src.{{$col.Field}} - e.arg.({{type $col.Type.V}})
+				return nil
+			}
+			{{end -}}
+			{{if $col.ColumnComparable -}}
+			if e.op == updateCopy {
+				switch e.arg {
+				{{range $src := $.Cols -}}
+					{{if eq (printf "%T" $src.Type.V) (printf "%T" $col.Type.V) -}}
+					case Column{{$src.Field}}:
+						dst.{{$col.Field}} = src.{{$src.Field}}
+						return nil
+					{{end -}}
+				{{end -}}
+				}
+			}
+			{{end -}}
+		{{end -}}
+	{{end -}}
+	}
+	return errors.Errorf("unsupported update expression on %s", e.col)
+}
+
+// sameType returns false if the update expression
+// copies a column of a different type
+func (e *updateExpr) sameType() bool {
+	if e.op != updateCopy {
+		return true
+	}
+
+	switch e.col {
+	{{range $col := .Cols -}}
+		{{if and (ne $col.Auto true) $col.ColumnComparable -}}
+		case Column{{$col.Field}}:
+			switch e.arg {
+			{{range $src := $.Cols -}}
+				{{if eq (printf "%T" $src.Type.V) (printf "%T" $col.Type.V) -}}
+				case Column{{$src.Field}}:
+					return true
+				{{end -}}
+			{{end -}}
+			}
+		{{end -}}
+	{{end -}}
+	}
+	return false
+}
+
+// IsSet returns true if the column was explicitly set
+func (u *Updater) IsSet(col Column) bool {
+	_, ok := u.columns[col]
+	return ok
+}
+
+// IsNull returns true if the column was explicitly set to NULL
+func (u *Updater) IsNull(col Column) bool {
+	return u.columns[col]
+}
+
+// Columns returns the explicitly set columns of the update builder
+func (u *Updater) Columns() []Column {
+	cols := []Column{}
+	{{range $col := .Cols -}}
+		{{if ne $col.Auto true -}}
+			if u.IsSet(Column{{$col.Field}}) {
+				cols = append(cols, Column{{$col.Field}})
+			}
+		{{end -}}
+	{{end -}}
+	return cols
+}
+
 // Where adds predicates to the update builder
 func (u *Updater) Where(pfs ...PredFunc) *Updater {
 	u.pfs = append(u.pfs, pfs...)
 	return u
 }
 
+// Predicates returns the predicates of the update builder
+func (u *Updater) Predicates() *comparison.Predicates {
+	return buildPredicates(u.pfs)
+}
+
+// AllRows allows the update builder to update all
+// the rows when there are no predicates
+func (u *Updater) AllRows() *Updater {
+	u.all = true
+	return u
+}
+
+// Guard returns an error if the update builder copies a column of a different
+// type, or has no filtering predicates and AllRows wasn't called
+func (u *Updater) Guard() error {
+	for _, e := range u.exprs {
+		if !e.sameType() {
+			return &nero.ColumnTypeError{Column: e.col.String(), Source: e.arg.(Column).String()}
+		}
+	}
+
+	if !u.all && !comparison.Filters(u.Predicates().All()) {
+		return &nero.UnfilteredError{Op: "update", Collection: "{{.Collection}}"}
+	}
+	return nil
+}
+
 // Deleter is a delete builder for {{.Type.Name}}
 type Deleter struct {
 	pfs []PredFunc
+	all bool
 }
 
 // NewDeleter is a factory for Deleter
@@ -184,6 +1097,27 @@ func (d *Deleter) Where(pfs ...PredFunc) *Deleter {
 	return d
 }
 
+// Predicates returns the predicates of the delete builder
+func (d *Deleter) Predicates() *comparison.Predicates {
+	return buildPredicates(d.pfs)
+}
+
+// AllRows allows the delete builder to delete all
+// the rows when there are no predicates
+func (d *Deleter) AllRows() *Deleter {
+	d.all = true
+	return d
+}
+
+// Guard returns an error if the delete builder has no
+// filtering predicates and AllRows wasn't called
+func (d *Deleter) Guard() error {
+	if !d.all && !comparison.Filters(d.Predicates().All()) {
+		return &nero.UnfilteredError{Op: "delete", Collection: "{{.Collection}}"}
+	}
+	return nil
+}
+
 // Aggregator is an aggregate builder for {{.Type.Name}}
 type Aggregator struct {
 	v      interface{}
@@ -225,6 +1159,46 @@ func (a *Aggregator) Group(cols ...Column) *Aggregator {
 	return a
 }
 
+// Aggregates returns the aggregate functions of the aggregate builder
+func (a *Aggregator) Aggregates() *aggregate.Aggregates {
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+	return aggs
+}
+
+// Predicates returns the predicates of the aggregate builder
+func (a *Aggregator) Predicates() *comparison.Predicates {
+	return buildPredicates(a.pfs)
+}
+
+// Sorts returns the sorting expressions of the aggregate builder
+func (a *Aggregator) Sorts() *sort.Sorts {
+	return buildSorts(a.sfs)
+}
+
+// Groups returns the grouping columns of the aggregate builder
+func (a *Aggregator) Groups() []Column {
+	return a.groups
+}
+
+func buildPredicates(pfs []PredFunc) *comparison.Predicates {
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	return pb
+}
+
+func buildSorts(sfs []SortFunc) *sort.Sorts {
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	return sorts
+}
+
 // rollback performs a rollback
 func rollback(tx nero.Tx, err error) error {
 	rerr := tx.Rollback()
@@ -38,6 +38,9 @@ const sortTmpl = `
 package {{.Schema.Pkg}}
 
 import (
+	{{if .Schema.HasFullText -}}
+		"github.com/sf9v/nero/comparison"
+	{{end -}}
 	"github.com/sf9v/nero/sort"
 )
 
@@ -55,4 +58,23 @@ func {{$direction.String}}(col Column) SortFunc {
 	}
 }
 {{end}}
+{{range $col := .Schema.Cols -}}
+{{if $col.IsFullText -}}
+// {{$col.Field}}Rank sorts by the full-text search rank of
+// "{{$col.Name}}" column against the query, best matches first
+func {{$col.Field}}Rank(query string) SortFunc {
+	return func(s *sort.Sorts) {
+		s.Add(&sort.Sort{
+			Col: "{{$col.Name}}",
+			Direction: sort.Desc,
+			Rank: &comparison.TextSearch{
+				Config: "{{$col.TextSearch}}",
+				Query: query,
+			},
+		})
+	}
+}
+
+{{end -}}
+{{end -}}
 `
@@ -3,13 +3,20 @@ module github.com/sf9v/nero
 go 1.15
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/Masterminds/squirrel v1.5.0
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/google/uuid v1.1.2
+	github.com/jackc/pgconn v1.8.0
+	github.com/jackc/pgtype v1.6.2
+	github.com/jackc/pgx/v4 v4.10.1
 	github.com/jinzhu/inflection v1.0.0
 	github.com/lib/pq v1.9.0
 	github.com/pkg/errors v0.9.1
 	github.com/segmentio/ksuid v1.0.3
 	github.com/sf9v/mira v0.0.0-20200915071822-32044ca9f4d3
 	github.com/stretchr/testify v1.6.1
+	go.etcd.io/bbolt v1.3.5
 	golang.org/x/tools v0.0.0-20201208233053-a543418bbed2
+	modernc.org/sqlite v1.10.6
 )
@@ -0,0 +1,46 @@
+package nero
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// JSON wraps v in a ValueScanner that stores it as a JSON document. This is
+// used by back-ends that don't have native support for array and map columns.
+// When scanning, v must be a pointer.
+func JSON(v interface{}) ValueScanner {
+	return &jsonValue{v: v}
+}
+
+type jsonValue struct {
+	v interface{}
+}
+
+// Value implements driver.Valuer
+func (j *jsonValue) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal json")
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (j *jsonValue) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.Errorf("unable to scan %T into json", src)
+	}
+
+	return errors.Wrap(json.Unmarshal(b, j.v), "unmarshal json")
+}
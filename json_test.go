@@ -0,0 +1,32 @@
+package nero
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	v, err := JSON([]string{"one", "two"}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, `["one","two"]`, v)
+
+	_, err = JSON(func() {}).Value()
+	assert.Error(t, err)
+
+	tags := []string{}
+	require.NoError(t, JSON(&tags).Scan([]byte(`["one","two"]`)))
+	assert.Equal(t, []string{"one", "two"}, tags)
+
+	m := map[string]string{}
+	require.NoError(t, JSON(&m).Scan(`{"a":"b"}`))
+	assert.Equal(t, map[string]string{"a": "b"}, m)
+
+	tags = nil
+	require.NoError(t, JSON(&tags).Scan(nil))
+	assert.Nil(t, tags)
+
+	assert.Error(t, JSON(&tags).Scan(1))
+	assert.Error(t, JSON(&tags).Scan("{"))
+}
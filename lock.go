@@ -0,0 +1,53 @@
+package nero
+
+// LockStrength is the strength of a row lock
+type LockStrength int
+
+func (s LockStrength) String() string {
+	switch s {
+	case ForUpdate:
+		return "FOR UPDATE"
+	case ForNoKeyUpdate:
+		return "FOR NO KEY UPDATE"
+	case ForShare:
+		return "FOR SHARE"
+	}
+
+	return ""
+}
+
+const (
+	// NoLock doesn't lock the rows
+	NoLock LockStrength = iota
+	// ForUpdate locks the rows for update or delete
+	ForUpdate
+	// ForNoKeyUpdate is a weaker ForUpdate that doesn't
+	// block the rows that only reference the locked rows
+	ForNoKeyUpdate
+	// ForShare locks the rows against update and delete
+	ForShare
+)
+
+// LockWait is how a row lock waits for the rows
+// that are locked by the other transactions
+type LockWait int
+
+func (w LockWait) String() string {
+	switch w {
+	case NoWait:
+		return "NOWAIT"
+	case SkipLocked:
+		return "SKIP LOCKED"
+	}
+
+	return ""
+}
+
+const (
+	// Wait waits for the rows to be unlocked
+	Wait LockWait = iota
+	// NoWait fails instead of waiting for the rows
+	NoWait
+	// SkipLocked skips the rows instead of waiting for them
+	SkipLocked
+)
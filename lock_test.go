@@ -0,0 +1,18 @@
+package nero
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	assert.Equal(t, "FOR UPDATE", ForUpdate.String())
+	assert.Equal(t, "FOR NO KEY UPDATE", ForNoKeyUpdate.String())
+	assert.Equal(t, "FOR SHARE", ForShare.String())
+	assert.Empty(t, NoLock.String())
+
+	assert.Equal(t, "NOWAIT", NoWait.String())
+	assert.Equal(t, "SKIP LOCKED", SkipLocked.String())
+	assert.Empty(t, Wait.String())
+}
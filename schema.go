@@ -13,6 +13,12 @@ type Schema struct {
 	Collection string
 	// Columns is the list of columns
 	Columns []*Column
+	// Edges is the list of relations to other schemas
+	Edges []*Edge
+	// Projections is the list of DTO structs that the rows can be
+	// projected into, the fields are matched to the columns by the
+	// "nero" struct tag or by the struct field of the column
+	Projections []interface{}
 	// Templates is the list of custom repository templates
 	Templates []Templater
 }
@@ -39,6 +45,9 @@ type ColumnConfig struct {
 	// ColumnComparable is a column that can be compared
 	// with other columns in the same collection/table
 	ColumnComparable bool
+	// TextSearch is the text search configuration
+	// of a full-text searchable column
+	TextSearch string
 }
 
 // NewColumn creates a new column
@@ -82,6 +91,16 @@ func (c *Column) ColumnComparable() *Column {
 	return c
 }
 
+// FullText is a full-text searchable column, config is the
+// text search configuration e.g. "english" and defaults to "simple"
+func (c *Column) FullText(config string) *Column {
+	if config == "" {
+		config = "simple"
+	}
+	c.cfg.TextSearch = config
+	return c
+}
+
 // StructField overrides the struct field name. Use this when the
 // inferred struct field is wrong. e.g. The struct field of the model
 // is "ID" but being referred to as "Id" in the generated code
@@ -24,4 +24,35 @@ func TestColumn(t *testing.T) {
 
 	cfg = NewColumn("comparable", "").ColumnComparable().Cfg()
 	assert.True(t, cfg.ColumnComparable)
+
+	cfg = NewColumn("title", "").FullText("english").Cfg()
+	assert.Equal(t, "english", cfg.TextSearch)
+
+	cfg = NewColumn("body", "").FullText("").Cfg()
+	assert.Equal(t, "simple", cfg.TextSearch)
+}
+
+type ref struct{}
+
+func (*ref) Schema() *Schema {
+	return &Schema{}
+}
+
+func TestEdge(t *testing.T) {
+	cfg := HasMany("orders", new(ref), "user_id").Cfg()
+	assert.Equal(t, "orders", cfg.Name)
+	assert.Equal(t, EdgeHasMany, cfg.Kind)
+	assert.Equal(t, "user_id", cfg.FK)
+	assert.NotNil(t, cfg.Ref)
+
+	cfg = BelongsTo("user", new(ref), "user_id").
+		StructField("Owner").Cfg()
+	assert.Equal(t, EdgeBelongsTo, cfg.Kind)
+	assert.Equal(t, "Owner", cfg.StructField)
+
+	cfg = ManyToMany("tags", new(ref), "user_tags", "user_id", "tag_id").Cfg()
+	assert.Equal(t, EdgeManyToMany, cfg.Kind)
+	assert.Equal(t, "user_tags", cfg.Join)
+	assert.Equal(t, "user_id", cfg.FK)
+	assert.Equal(t, "tag_id", cfg.RefFK)
 }
@@ -1,9 +1,13 @@
 package sort
 
+import "github.com/sf9v/nero/comparison"
+
 // Sort is a sorter
 type Sort struct {
 	Col       string
 	Direction Direction
+	// Rank sorts by the full-text search rank of the column instead
+	Rank *comparison.TextSearch
 }
 
 // Sorts is a collection of sorters
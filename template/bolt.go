@@ -0,0 +1,1462 @@
+package template
+
+import "github.com/sf9v/nero"
+
+// BoltTemplate is the template for generating a bbolt repository
+type BoltTemplate struct {
+	filename string
+}
+
+var _ nero.Templater = (*BoltTemplate)(nil)
+
+// NewBoltTemplate returns a new BoltTemplate
+func NewBoltTemplate() *BoltTemplate {
+	return &BoltTemplate{
+		filename: "bolt.go",
+	}
+}
+
+// WithFilename overrides the default filename
+func (t *BoltTemplate) WithFilename(filename string) *BoltTemplate {
+	t.filename = filename
+	return t
+}
+
+// Filename returns the filename
+func (t *BoltTemplate) Filename() string {
+	return t.filename
+}
+
+// Content returns the template content
+func (t *BoltTemplate) Content() string {
+	return boltTmpl
+}
+
+const boltTmpl = `
+// Code generated by nero, DO NOT EDIT.
+package {{.Pkg}}
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	stdsort "sort"
+	"github.com/pkg/errors"
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/eval"
+	"github.com/sf9v/nero/sort"
+	"go.etcd.io/bbolt"
+	{{range $import := .SchemaImports -}}
+		"{{$import}}"
+	{{end -}}
+	{{range $import := .ColumnImports -}}
+		"{{$import}}"
+	{{end -}}
+)
+
+// BoltRepository implements the Repository interface by storing {{.Type.Name}}
+// in a bbolt bucket. Each row is stored as a JSON encoded record keyed by
+// the identity, predicates, sorts and aggregates are evaluated by scanning.
+type BoltRepository struct {
+	db *bbolt.DB
+	{{range $edge := .Edges -}}
+		{{lowerCamel $edge.StructField}}Loader func(context.Context, []{{type $edge.RefCol.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error)
+	{{end -}}
+}
+
+var _ Repository = (*BoltRepository)(nil)
+
+// NewBoltRepository is a factory for BoltRepository
+func NewBoltRepository(db *bbolt.DB) *BoltRepository {
+	return &BoltRepository{
+		db: db,
+	}
+}
+
+// boltBucket is the name of the bucket
+var boltBucket = []byte("{{.Collection}}")
+
+// boltTx is a read-write bbolt transaction
+type boltTx struct {
+	ctx context.Context
+	tx  *bbolt.Tx
+}
+
+var _ nero.Tx = (*boltTx)(nil)
+
+// Commit commits the transaction
+func (tx *boltTx) Commit() error {
+	// same as database/sql, the transaction is
+	// rolled back when the context is done
+	if err := tx.ctx.Err(); err != nil {
+		_ = tx.tx.Rollback()
+		return err
+	}
+
+	return tx.tx.Commit()
+}
+
+// Rollback aborts the transaction
+func (tx *boltTx) Rollback() error {
+	err := tx.tx.Rollback()
+	if err == bbolt.ErrTxClosed {
+		return sql.ErrTxDone
+	}
+	return err
+}
+
+func (bt *BoltRepository) getTx(tx nero.Tx) (*bbolt.Tx, error) {
+	txx, ok := tx.(*boltTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *boltTx")
+	}
+
+	// bbolt sets the db to nil when the transaction is closed
+	if txx.tx.DB() == nil {
+		return nil, sql.ErrTxDone
+	}
+
+	return txx.tx, nil
+}
+
+// Tx begins a new read-write transaction, bbolt allows only
+// one read-write transaction at a time
+func (bt *BoltRepository) Tx(ctx context.Context) (nero.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tx, err := bt.db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltTx{ctx: ctx, tx: tx}, nil
+}
+
+// Create creates a new {{.Type.Name}}
+func (bt *BoltRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
+	var id {{identType $}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		id, err = bt.create(ctx, tx, c)
+		return err
+	})
+	return id, err
+}
+
+// CreateTx creates a new {{.Type.Name}} inside a transaction
+func (bt *BoltRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return bt.create(ctx, txx, c)
+}
+
+func (bt *BoltRepository) create(ctx context.Context, tx *bbolt.Tx, c *Creator) ({{identType $}}, error) {
+	row, err := bt.createRow(ctx, tx, c)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return bt.ident(row), nil
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (bt *BoltRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	var row *{{type .Type.V}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		row, err = bt.createRow(ctx, tx, c)
+		return err
+	})
+	return row, err
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (bt *BoltRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bt.createRow(ctx, txx, c)
+}
+
+func (bt *BoltRepository) createRow(ctx context.Context, tx *bbolt.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b, err := tx.CreateBucketIfNotExists(boltBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	row := bt.newRow(c)
+	{{if .HasAutoIdent -}}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return nil, err
+		}
+
+		{{range $col := .Idents -}}
+			{{if $col.Auto -}}
+				err = eval.Sequence(&row.{{$col.Field}}, seq)
+				if err != nil {
+					return nil, err
+				}
+			{{end -}}
+		{{end -}}
+	{{end}}
+
+	err = bt.put(b, row, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return row, nil
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (bt *BoltRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	var idents []{{identType $}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		idents, err = bt.createMany(ctx, tx, cs...)
+		return err
+	})
+	return idents, err
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (bt *BoltRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bt.createMany(ctx, txx, cs...)
+}
+
+func (bt *BoltRepository) createMany(ctx context.Context, tx *bbolt.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	idents := make([]{{identType $}}, 0, len(cs))
+	for _, c := range cs {
+		ident, err := bt.create(ctx, tx, c)
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+	}
+
+	return idents, nil
+}
+
+// newRow creates a new row from the creator without the auto columns
+func (bt *BoltRepository) newRow(c *Creator) *{{type .Type.V}} {
+	return &{{type .Type.V}}{
+		{{range $col := .Cols -}}
+			{{if ne $col.Auto true -}}
+				{{$col.Field}}: c.{{$col.Identifier}},
+			{{end -}}
+		{{end -}}
+	}
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (bt *BoltRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	var id {{identType $}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		id, err = bt.upsert(ctx, tx, u, u.Creators()[0])
+		return err
+	})
+	return id, err
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (bt *BoltRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	return bt.upsert(ctx, txx, u, u.Creators()[0])
+}
+
+func (bt *BoltRepository) upsert(ctx context.Context, tx *bbolt.Tx, u *Upserter, c *Creator) ({{identType $}}, error) {
+	if err := ctx.Err(); err != nil {
+		return {{identZero $}}, err
+	}
+
+	rows, err := bt.rows(tx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	row := bt.newRow(c)
+	for _, existing := range rows {
+		if !bt.conflicts(existing, row, u.ConflictColumns()) {
+			continue
+		}
+
+		ident := bt.ident(existing)
+		cols := u.UpdateColumns()
+		if len(cols) == 0 {
+			return ident, nil
+		}
+
+		bt.copyColumns(existing, row, cols)
+		b := tx.Bucket(boltBucket)
+		changed := bt.ident(existing) != ident
+		if changed {
+			err = bt.del(b, ident)
+			if err != nil {
+				return {{identZero $}}, err
+			}
+		}
+
+		err = bt.put(b, existing, changed)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return bt.ident(existing), nil
+	}
+
+	return bt.create(ctx, tx, c)
+}
+
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (bt *BoltRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	if len(u.Creators()) == 0 {
+		return nil
+	}
+
+	return bt.db.Update(func(tx *bbolt.Tx) error {
+		return bt.upsertMany(ctx, tx, u)
+	})
+}
+
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (bt *BoltRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return bt.upsertMany(ctx, txx, u)
+}
+
+func (bt *BoltRepository) upsertMany(ctx context.Context, tx *bbolt.Tx, u *Upserter) error {
+	if len(u.Creators()) == 0 {
+		return nil
+	}
+
+	if err := u.validate(false); err != nil {
+		return err
+	}
+
+	for _, c := range u.Creators() {
+		_, err := bt.upsert(ctx, tx, u, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conflicts returns true if the rows have the same values in the columns
+func (bt *BoltRepository) conflicts(a, b *{{type .Type.V}}, cols []Column) bool {
+	for _, col := range cols {
+		if !reflect.DeepEqual(bt.value(a, col.String()), bt.value(b, col.String())) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyColumns copies the values of the columns from src to dst
+func (bt *BoltRepository) copyColumns(dst, src *{{type .Type.V}}, cols []Column) {
+	for _, col := range cols {
+		switch col {
+		{{range $col := .Cols -}}
+			{{if ne $col.Auto true -}}
+				case Column{{$col.Field}}:
+					dst.{{$col.Field}} = src.{{$col.Field}}
+			{{end -}}
+		{{end -}}
+		}
+	}
+}
+
+// ident returns the identity of the row
+func (bt *BoltRepository) ident(row *{{type .Type.V}}) {{identType $}} {
+	{{if .HasCompositeIdent -}}
+		return Ident{
+			{{range $col := .Idents -}}
+				{{$col.Field}}: row.{{$col.Field}},
+			{{end -}}
+		}
+	{{- else -}}
+		return row.{{.Ident.Field}}
+	{{- end}}
+}
+
+// key returns the key of the identity
+func (bt *BoltRepository) key(ident {{identType $}}) ([]byte, error) {
+	return json.Marshal(ident)
+}
+
+// put encodes and stores the row
+func (bt *BoltRepository) put(b *bbolt.Bucket, row *{{type .Type.V}}, unique bool) error {
+	key, err := bt.key(bt.ident(row))
+	if err != nil {
+		return errors.Wrap(err, "encode key")
+	}
+
+	if unique && b.Get(key) != nil {
+		return errors.Errorf("duplicate identity %v", bt.ident(row))
+	}
+
+	rec := map[string]json.RawMessage{}
+	{{range $col := .Cols -}}
+		rec["{{$col.Name}}"], err = json.Marshal(row.{{$col.Field}})
+		if err != nil {
+			return errors.Wrapf(err, "encode column %q", "{{$col.Name}}")
+		}
+	{{end}}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(key, data)
+}
+
+// rows decodes all the rows in the bucket
+func (bt *BoltRepository) rows(tx *bbolt.Tx) ([]*{{type .Type.V}}, error) {
+	rows := []*{{type .Type.V}}{}
+	b := tx.Bucket(boltBucket)
+	if b == nil {
+		return rows, nil
+	}
+
+	err := b.ForEach(func(_, data []byte) error {
+		rec := map[string]json.RawMessage{}
+		err := json.Unmarshal(data, &rec)
+		if err != nil {
+			return err
+		}
+
+		row := &{{type .Type.V}}{}
+		{{range $col := .Cols -}}
+			if raw, ok := rec["{{$col.Name}}"]; ok {
+				err = json.Unmarshal(raw, &row.{{$col.Field}})
+				if err != nil {
+					return errors.Wrapf(err, "decode column %q", "{{$col.Name}}")
+				}
+			}
+		{{end}}
+
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Query queries many {{.Type.Name}}
+func (bt *BoltRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	var {{plural (lowerCamel .Type.Name)}} []*{{type .Type.V}}
+	err := bt.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		{{plural (lowerCamel .Type.Name)}}, err = bt.query(ctx, tx, q)
+		return err
+	})
+	return {{plural (lowerCamel .Type.Name)}}, err
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (bt *BoltRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bt.query(ctx, txx, q)
+}
+
+func (bt *BoltRepository) query(ctx context.Context, tx *bbolt.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the row locks are no-op since the bolt transactions are exclusive
+
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := bt.rows(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = bt.filter(ctx, tx, rows, q.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bt.sortRows(rows, q.sfs)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.offset > 0 {
+		if int(q.offset) >= len(rows) {
+			rows = rows[:0]
+		} else {
+			rows = rows[q.offset:]
+		}
+	}
+
+	if q.limit > 0 && int(q.limit) < len(rows) {
+		rows = rows[:q.limit]
+	}
+
+	if len(q.cols) > 0 {
+		cols := q.columns()
+		for i, row := range rows {
+			rows[i] = project(row, cols)
+		}
+	}
+	{{if .Edges}}
+	err = bt.loadEdges(ctx, tx, q, rows)
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	if q.reversed {
+		reverseRows(rows)
+	}
+
+	return rows, nil
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (bt *BoltRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	var {{lowerCamel .Type.Name}} *{{type .Type.V}}
+	err := bt.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		{{lowerCamel .Type.Name}}, err = bt.queryOne(ctx, tx, q)
+		return err
+	})
+	return {{lowerCamel .Type.Name}}, err
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (bt *BoltRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bt.queryOne(ctx, txx, q)
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (bt *BoltRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := bt.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (bt *BoltRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := bt.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (bt *BoltRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	rows, err := bt.Query(ctx, q.filter())
+	return int64(len(rows)), err
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (bt *BoltRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	rows, err := bt.QueryTx(ctx, tx, q.filter())
+	return int64(len(rows)), err
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (bt *BoltRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	count, err := bt.Count(ctx, q)
+	return count > 0, err
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (bt *BoltRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	count, err := bt.CountTx(ctx, tx, q)
+	return count > 0, err
+}
+
+func (bt *BoltRepository) queryOne(ctx context.Context, tx *bbolt.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	rows, err := bt.query(ctx, tx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	// same as the sql back-ends
+	if len(rows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// the closest row before the cursor is the last one
+	if _, before := q.Cursors(); before != "" {
+		return rows[len(rows)-1], nil
+	}
+
+	return rows[0], nil
+}
+
+{{if .Edges -}}
+{{range $edge := .Edges -}}
+// With{{$edge.StructField}}Loader sets the loader of the related rows of the "{{$edge.Name}}" edge.
+// The loader is required for eager-loading and filtering by the edge since
+// the related rows are not kept by this repository.
+func (bt *BoltRepository) With{{$edge.StructField}}Loader(loader func(ctx context.Context, keys []{{type $edge.RefCol.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error)) *BoltRepository {
+	bt.{{lowerCamel $edge.StructField}}Loader = loader
+	return bt
+}
+
+{{if $edge.IsManyToMany -}}
+// load{{$edge.StructField}} loads the associated rows of the "{{$edge.Name}}" edge by key
+func (bt *BoltRepository) load{{$edge.StructField}}(ctx context.Context, tx *bbolt.Tx, keys []{{type $edge.Col.Type.V}}) (map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}, error) {
+	if bt.{{lowerCamel $edge.StructField}}Loader == nil {
+		return nil, errors.New("loader is not set")
+	}
+
+	links := map[{{type $edge.Col.Type.V}}][]{{type $edge.RefCol.Type.V}}{}
+	refKeys := []{{type $edge.RefCol.Type.V}}{}
+	seen := map[{{type $edge.RefCol.Type.V}}]struct{}{}
+	for _, key := range keys {
+		l, err := bt.links{{$edge.StructField}}(tx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		links[key] = l
+		for _, refKey := range l {
+			if _, ok := seen[refKey]; !ok {
+				seen[refKey] = struct{}{}
+				refKeys = append(refKeys, refKey)
+			}
+		}
+	}
+
+	loaded, err := bt.{{lowerCamel $edge.StructField}}Loader(ctx, refKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[{{type $edge.RefCol.Type.V}}]*{{type $edge.Ref.Type.V}}{}
+	for _, r := range loaded {
+		byKey[r.{{$edge.RefCol.Field}}] = r
+	}
+
+	related := map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}{}
+	for key, l := range links {
+		for _, refKey := range l {
+			if r, ok := byKey[refKey]; ok {
+				related[key] = append(related[key], r)
+			}
+		}
+	}
+
+	return related, nil
+}
+{{- else -}}
+// load{{$edge.StructField}} loads the related rows of the "{{$edge.Name}}" edge
+func (bt *BoltRepository) load{{$edge.StructField}}(ctx context.Context, keys []{{type $edge.Col.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error) {
+	if bt.{{lowerCamel $edge.StructField}}Loader == nil {
+		return nil, errors.New("loader is not set")
+	}
+	return bt.{{lowerCamel $edge.StructField}}Loader(ctx, keys)
+}
+{{- end}}
+
+{{end -}}
+
+// loadEdges eager-loads the edges of the rows
+func (bt *BoltRepository) loadEdges(ctx context.Context, tx *bbolt.Tx, q *Queryer, rows []*{{type .Type.V}}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	{{range $edge := .Edges}}
+	if q.with{{$edge.StructField}} {
+		related, err := bt.load{{$edge.StructField}}(ctx, {{if $edge.IsManyToMany}}tx, {{end}}edge{{$edge.StructField}}Keys(rows))
+		if err != nil {
+			return errors.Wrap(err, "load {{$edge.Name}}")
+		}
+		setEdge{{$edge.StructField}}(rows, related)
+	}
+	{{end}}
+	return nil
+}
+
+// relatedKeys returns the keys of the rows that have related
+// rows for the edges of the predicates, by edge name
+func (bt *BoltRepository) relatedKeys(ctx context.Context, tx *bbolt.Tx, rows []*{{type .Type.V}}, preds []*comparison.Predicate) (map[string]map[interface{}]struct{}, error) {
+	related := map[string]map[interface{}]struct{}{}
+	for _, p := range preds {
+		edge, ok := p.Arg.(*comparison.Edge)
+		if !ok {
+			continue
+		}
+
+		if _, ok := related[edge.Name]; ok {
+			continue
+		}
+
+		keys := map[interface{}]struct{}{}
+		switch edge.Name {
+		{{range $edge := .Edges -}}
+			case "{{$edge.Name}}":
+				{{if $edge.IsManyToMany -}}
+					// the associations are kept by this repository
+					for _, key := range edge{{$edge.StructField}}Keys(rows) {
+						links, err := bt.links{{$edge.StructField}}(tx, key)
+						if err != nil {
+							return nil, errors.Wrap(err, "load {{$edge.Name}}")
+						}
+
+						if len(links) > 0 {
+							keys[key] = struct{}{}
+						}
+					}
+				{{else -}}
+					loaded, err := bt.load{{$edge.StructField}}(ctx, edge{{$edge.StructField}}Keys(rows))
+					if err != nil {
+						return nil, errors.Wrap(err, "load {{$edge.Name}}")
+					}
+
+					for _, r := range loaded {
+						keys[r.{{$edge.RefCol.Field}}] = struct{}{}
+					}
+				{{end -}}
+		{{end -}}
+		}
+		related[edge.Name] = keys
+	}
+
+	return related, nil
+}
+
+{{end -}}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (bt *BoltRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return bt.db.Update(func(tx *bbolt.Tx) error {
+		return bt.add{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	})
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (bt *BoltRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return bt.add{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+func (bt *BoltRepository) add{{$edge.StructField}}(ctx context.Context, tx *bbolt.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	links, err := bt.links{{$edge.StructField}}(tx, id)
+	if err != nil {
+		return err
+	}
+
+	links, err = bt.appendLinks{{$edge.StructField}}(links, refIDs)
+	if err != nil {
+		return err
+	}
+
+	return bt.putLinks{{$edge.StructField}}(tx, id, links)
+}
+
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (bt *BoltRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return bt.db.Update(func(tx *bbolt.Tx) error {
+		return bt.remove{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	})
+}
+
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (bt *BoltRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return bt.remove{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+func (bt *BoltRepository) remove{{$edge.StructField}}(ctx context.Context, tx *bbolt.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	links, err := bt.links{{$edge.StructField}}(tx, id)
+	if err != nil {
+		return err
+	}
+
+	updated := []{{type $edge.RefCol.Type.V}}{}
+	for _, link := range links {
+		removed := false
+		for _, refID := range refIDs {
+			if link == refID {
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			updated = append(updated, link)
+		}
+	}
+
+	return bt.putLinks{{$edge.StructField}}(tx, id, updated)
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (bt *BoltRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return bt.db.Update(func(tx *bbolt.Tx) error {
+		return bt.set{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	})
+}
+
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (bt *BoltRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return bt.set{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+func (bt *BoltRepository) set{{$edge.StructField}}(ctx context.Context, tx *bbolt.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	links, err := bt.appendLinks{{$edge.StructField}}(nil, refIDs)
+	if err != nil {
+		return err
+	}
+
+	return bt.putLinks{{$edge.StructField}}(tx, id, links)
+}
+
+// appendLinks{{$edge.StructField}} appends the associations, duplicates
+// are not allowed same as the join collection primary key
+func (bt *BoltRepository) appendLinks{{$edge.StructField}}(links, refIDs []{{type $edge.RefCol.Type.V}}) ([]{{type $edge.RefCol.Type.V}}, error) {
+	updated := append([]{{type $edge.RefCol.Type.V}}{}, links...)
+	for _, refID := range refIDs {
+		for _, link := range updated {
+			if link == refID {
+				return nil, errors.Errorf("duplicate association %v", refID)
+			}
+		}
+		updated = append(updated, refID)
+	}
+
+	return updated, nil
+}
+
+// links{{$edge.StructField}}Bucket keeps the "{{$edge.Name}}" associations by key
+var links{{$edge.StructField}}Bucket = []byte("{{$edge.Join}}:{{$edge.JoinCol}}")
+
+// links{{$edge.StructField}} returns the "{{$edge.Name}}" associations of the key
+func (bt *BoltRepository) links{{$edge.StructField}}(tx *bbolt.Tx, key {{type $edge.Col.Type.V}}) ([]{{type $edge.RefCol.Type.V}}, error) {
+	b := tx.Bucket(links{{$edge.StructField}}Bucket)
+	if b == nil {
+		return nil, nil
+	}
+
+	k, err := json.Marshal(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode key")
+	}
+
+	data := b.Get(k)
+	if data == nil {
+		return nil, nil
+	}
+
+	links := []{{type $edge.RefCol.Type.V}}{}
+	err = json.Unmarshal(data, &links)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode links")
+	}
+
+	return links, nil
+}
+
+// putLinks{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the key
+func (bt *BoltRepository) putLinks{{$edge.StructField}}(tx *bbolt.Tx, key {{type $edge.Col.Type.V}}, links []{{type $edge.RefCol.Type.V}}) error {
+	b, err := tx.CreateBucketIfNotExists(links{{$edge.StructField}}Bucket)
+	if err != nil {
+		return err
+	}
+
+	k, err := json.Marshal(key)
+	if err != nil {
+		return errors.Wrap(err, "encode key")
+	}
+
+	if len(links) == 0 {
+		return b.Delete(k)
+	}
+
+	data, err := json.Marshal(links)
+	if err != nil {
+		return errors.Wrap(err, "encode links")
+	}
+
+	return b.Put(k, data)
+}
+{{end}}
+
+// value returns the value of the column
+func (bt *BoltRepository) value(row *{{type .Type.V}}, col string) interface{} {
+	switch col {
+	{{range $col := .Cols -}}
+		case "{{$col.Name}}":
+			return row.{{$col.Field}}
+	{{end -}}
+	}
+
+	return nil
+}
+
+// filter returns the rows that matches the predicates
+func (bt *BoltRepository) filter(ctx context.Context, tx *bbolt.Tx, rows []*{{type .Type.V}}, pfs []PredFunc) ([]*{{type .Type.V}}, error) {
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+
+	{{if .Edges -}}
+		related, err := bt.relatedKeys(ctx, tx, rows, comparison.Flatten(pb.All()))
+		if err != nil {
+			return nil, err
+		}
+	{{end}}
+	filtered := []*{{type .Type.V}}{}
+	for _, row := range rows {
+		ok, err := bt.match(row, pb.All(){{if .Edges}}, related{{end}})
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			filtered = append(filtered, row)
+		}
+	}
+
+	return filtered, nil
+}
+
+// match returns true if the row matches all the predicates
+func (bt *BoltRepository) match(row *{{type .Type.V}}, preds []*comparison.Predicate{{if .Edges}}, related map[string]map[interface{}]struct{}{{end}}) (bool, error) {
+	t, err := bt.evaluate(row, preds{{if .Edges}}, related{{end}})
+	return t == eval.True, err
+}
+
+// evaluate evaluates the conjunction of the predicates against the row,
+// the result is unknown if a null is compared so that negating it doesn't match the row
+func (bt *BoltRepository) evaluate(row *{{type .Type.V}}, preds []*comparison.Predicate{{if .Edges}}, related map[string]map[interface{}]struct{}{{end}}) (eval.Truth, error) {
+	t := eval.True
+	for _, p := range preds {
+		if t == eval.False {
+			break
+		}
+
+		if p.Op.IsGroup() {
+			gt, err := bt.evaluateGroup(row, p{{if .Edges}}, related{{end}})
+			if err != nil {
+				return eval.False, err
+			}
+			t = t.And(gt)
+			continue
+		}
+
+		{{if .Edges -}}
+			if edge, ok := p.Arg.(*comparison.Edge); ok {
+				_, exists := related[edge.Name][bt.value(row, p.Col)]
+				if exists != (p.Op == comparison.Exists) {
+					return eval.False, nil
+				}
+				continue
+			}
+
+		{{end -}}
+		if p.Op == comparison.Matches {
+			return eval.False, &nero.UnsupportedError{Op: "full-text search", Repository: "BoltRepository"}
+		}
+
+		arg := p.Arg
+		if col, ok := p.Arg.(Column); ok {
+			arg = bt.value(row, col.String())
+		}
+
+		pt, err := eval.Evaluate(p.Op, bt.value(row, p.Col), arg)
+		if err != nil {
+			return eval.False, errors.Wrapf(err, "column %q", p.Col)
+		}
+		t = t.And(pt)
+	}
+
+	return t, nil
+}
+
+// evaluateGroup evaluates the group predicate against the row
+func (bt *BoltRepository) evaluateGroup(row *{{type .Type.V}}, p *comparison.Predicate{{if .Edges}}, related map[string]map[interface{}]struct{}{{end}}) (eval.Truth, error) {
+	if p.Op == comparison.Or {
+		t := eval.False
+		for _, pred := range p.Group() {
+			pt, err := bt.evaluate(row, []*comparison.Predicate{pred}{{if .Edges}}, related{{end}})
+			if err != nil {
+				return eval.False, err
+			}
+
+			t = t.Or(pt)
+			if t == eval.True {
+				break
+			}
+		}
+		return t, nil
+	}
+
+	t, err := bt.evaluate(row, p.Group(){{if .Edges}}, related{{end}})
+	if err != nil {
+		return eval.False, err
+	}
+
+	if p.Op == comparison.Not {
+		return t.Not(), nil
+	}
+	return t, nil
+}
+
+// sortRows sorts the rows in place
+func (bt *BoltRepository) sortRows(rows []*{{type .Type.V}}, sfs []SortFunc) error {
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+
+	var err error
+	stdsort.SliceStable(rows, func(i, j int) bool {
+		less, lerr := bt.less(rows[i], rows[j], sorts.All())
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		return less
+	})
+
+	return err
+}
+
+// less returns true if row a should be sorted before row b
+func (bt *BoltRepository) less(a, b *{{type .Type.V}}, sorts []*sort.Sort) (bool, error) {
+	for _, s := range sorts {
+		if s.Rank != nil {
+			return false, &nero.UnsupportedError{Op: "full-text search", Repository: "BoltRepository"}
+		}
+
+		cmp, err := eval.Compare(bt.value(a, s.Col), bt.value(b, s.Col))
+		if err != nil {
+			return false, errors.Wrapf(err, "column %q", s.Col)
+		}
+
+		if cmp == 0 {
+			continue
+		}
+
+		if s.Direction == sort.Desc {
+			return cmp > 0, nil
+		}
+
+		return cmp < 0, nil
+	}
+
+	return false, nil
+}
+
+// Update updates {{.Type.Name}}
+func (bt *BoltRepository) Update(ctx context.Context, u *Updater) (int64, error) {
+	var rows []*{{type .Type.V}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		rows, err = bt.update(ctx, tx, u)
+		return err
+	})
+	return int64(len(rows)), err
+}
+
+// UpdateTx updates {{.Type.Name}} inside a transaction
+func (bt *BoltRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updater) (int64, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := bt.update(ctx, txx, u)
+	return int64(len(rows)), err
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (bt *BoltRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	var rows []*{{type .Type.V}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		rows, err = bt.update(ctx, tx, u)
+		return err
+	})
+	return rows, err
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (bt *BoltRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bt.update(ctx, txx, u)
+}
+
+// update updates the matching rows and returns them
+func (bt *BoltRepository) update(ctx context.Context, tx *bbolt.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	setters := []func(*{{type .Type.V}}){}
+	{{range $col := .Cols -}}
+		{{if ne $col.Auto true -}}
+			if u.IsSet(Column{{$col.Field}}) {
+				setters = append(setters, func(row *{{type $.Type.V}}) {
+					row.{{$col.Field}} = u.{{$col.Identifier}}
+				})
+			}
+		{{end -}}
+	{{end}}
+
+	// same as the sql back-ends
+	if len(setters) == 0 && len(u.exprs) == 0 {
+		return nil, errors.New("nothing to update")
+	}
+
+	all, err := bt.rows(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := bt.filter(ctx, tx, all, u.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	b := tx.Bucket(boltBucket)
+	for _, row := range rows {
+		ident := bt.ident(row)
+		old := *row
+		for _, set := range setters {
+			set(row)
+		}
+		for _, e := range u.exprs {
+			if err := e.apply(row, &old); err != nil {
+				return nil, err
+			}
+		}
+
+		changed := bt.ident(row) != ident
+		if changed {
+			err = bt.del(b, ident)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = bt.put(b, row, changed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rows, nil
+}
+
+// del deletes the row with the identity
+func (bt *BoltRepository) del(b *bbolt.Bucket, ident {{identType $}}) error {
+	key, err := bt.key(ident)
+	if err != nil {
+		return errors.Wrap(err, "encode key")
+	}
+
+	return b.Delete(key)
+}
+
+// Delete deletes {{.Type.Name}}
+func (bt *BoltRepository) Delete(ctx context.Context, d *Deleter) (int64, error) {
+	var rows []*{{type .Type.V}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		rows, err = bt.delete(ctx, tx, d)
+		return err
+	})
+	return int64(len(rows)), err
+}
+
+// DeleteTx deletes {{.Type.Name}} inside a transaction
+func (bt *BoltRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Deleter) (int64, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := bt.delete(ctx, txx, d)
+	return int64(len(rows)), err
+}
+
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (bt *BoltRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	var rows []*{{type .Type.V}}
+	err := bt.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		rows, err = bt.delete(ctx, tx, d)
+		return err
+	})
+	return rows, err
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (bt *BoltRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bt.delete(ctx, txx, d)
+}
+
+// delete deletes the matching rows and returns them
+func (bt *BoltRepository) delete(ctx context.Context, tx *bbolt.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	all, err := bt.rows(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := bt.filter(ctx, tx, all, d.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	b := tx.Bucket(boltBucket)
+	for _, row := range rows {
+		err = bt.del(b, bt.ident(row))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rows, nil
+}
+
+// Aggregate runs aggregate operations
+func (bt *BoltRepository) Aggregate(ctx context.Context, a *Aggregator) error {
+	return bt.db.View(func(tx *bbolt.Tx) error {
+		return bt.aggregate(ctx, tx, a)
+	})
+}
+
+// AggregateTx runs aggregate operations inside a transaction
+func (bt *BoltRepository) AggregateTx(ctx context.Context, tx nero.Tx, a *Aggregator) error {
+	txx, err := bt.getTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return bt.aggregate(ctx, txx, a)
+}
+
+func (bt *BoltRepository) aggregate(ctx context.Context, tx *bbolt.Tx, a *Aggregator) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+
+	v := reflect.ValueOf(a.v).Elem()
+	t := reflect.TypeOf(v.Interface()).Elem()
+	if t.NumField() != len(aggs.All()) {
+		return errors.New("aggregate columns and destination struct field count should match")
+	}
+
+	all, err := bt.rows(tx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := bt.filter(ctx, tx, all, a.pfs)
+	if err != nil {
+		return err
+	}
+
+	// group the rows by the values of the group columns,
+	// without group columns all the rows are in one group
+	groups := [][]*{{type .Type.V}}{}
+	if len(a.groups) == 0 {
+		groups = append(groups, rows)
+	}
+	for _, row := range rows {
+		if len(a.groups) == 0 {
+			break
+		}
+
+		found := false
+		for i, group := range groups {
+			eq := true
+			for _, col := range a.groups {
+				eq, err = eval.Equal(bt.value(row, col.String()),
+					bt.value(group[0], col.String()))
+				if err != nil {
+					return err
+				}
+
+				if !eq {
+					break
+				}
+			}
+
+			if eq {
+				groups[i] = append(group, row)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			groups = append(groups, []*{{type .Type.V}}{row})
+		}
+	}
+
+	sorts := &sort.Sorts{}
+	for _, sf := range a.sfs {
+		sf(sorts)
+	}
+	stdsort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i]) == 0 || len(groups[j]) == 0 {
+			return false
+		}
+
+		less, lerr := bt.less(groups[i][0], groups[j][0], sorts.All())
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		return less
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		ve := reflect.New(t).Elem()
+		for i, agg := range aggs.All() {
+			vals := make([]interface{}, 0, len(group))
+			for _, row := range group {
+				vals = append(vals, bt.value(row, agg.Col))
+			}
+
+			res, err := eval.Aggregate(agg.Fn, vals)
+			if err != nil {
+				return errors.Wrapf(err, "column %q", agg.Col)
+			}
+
+			err = eval.Assign(ve.Field(i).Addr().Interface(), res)
+			if err != nil {
+				return errors.Wrapf(err, "column %q", agg.Col)
+			}
+		}
+
+		v.Set(reflect.Append(v, ve))
+	}
+
+	return nil
+}
+`
@@ -0,0 +1,17 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltTemplate(t *testing.T) {
+	tmpl := NewBoltTemplate().WithFilename("bt.go")
+
+	assert.Equal(t, "bt.go", tmpl.Filename())
+
+	_, err := ParseTemplate(tmpl.Content())
+	require.NoError(t, err)
+}
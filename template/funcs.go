@@ -55,3 +55,23 @@ func pluralFunc(s string) string {
 func lowerCamelFunc(s string) string {
 	return stringsx.ToLowerCamel(s)
 }
+
+// identSchema is the part of the schema used for resolving the identity
+type identSchema interface {
+	HasCompositeIdent() bool
+	IdentV() interface{}
+}
+
+func identTypeFunc(s identSchema) string {
+	if s.HasCompositeIdent() {
+		return "Ident"
+	}
+	return typeFunc(s.IdentV())
+}
+
+func identZeroFunc(s identSchema) string {
+	if s.HasCompositeIdent() {
+		return "(Ident{})"
+	}
+	return zeroFunc(s.IdentV())
+}
@@ -8,6 +8,14 @@ import (
 
 type myType struct{}
 
+type testIdentSchema struct {
+	composite bool
+	v         interface{}
+}
+
+func (s testIdentSchema) HasCompositeIdent() bool { return s.composite }
+func (s testIdentSchema) IdentV() interface{}     { return s.v }
+
 func TestFuncs(t *testing.T) {
 	t.Run("typeFunc", func(t *testing.T) {
 		got := typeFunc(1)
@@ -64,4 +72,14 @@ func TestFuncs(t *testing.T) {
 		expect := "theMatrix"
 		assert.Equal(t, got, expect)
 	})
+
+	t.Run("identFuncs", func(t *testing.T) {
+		s := testIdentSchema{v: int64(0)}
+		assert.Equal(t, "int64", identTypeFunc(s))
+		assert.Equal(t, "0", identZeroFunc(s))
+
+		s = testIdentSchema{composite: true}
+		assert.Equal(t, "Ident", identTypeFunc(s))
+		assert.Equal(t, "(Ident{})", identZeroFunc(s))
+	})
 }
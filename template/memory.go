@@ -0,0 +1,1457 @@
+package template
+
+import "github.com/sf9v/nero"
+
+// MemoryTemplate is the template for generating an in-memory repository
+type MemoryTemplate struct {
+	filename string
+}
+
+var _ nero.Templater = (*MemoryTemplate)(nil)
+
+// NewMemoryTemplate returns a new MemoryTemplate
+func NewMemoryTemplate() *MemoryTemplate {
+	return &MemoryTemplate{
+		filename: "memory.go",
+	}
+}
+
+// WithFilename overrides the default filename
+func (t *MemoryTemplate) WithFilename(filename string) *MemoryTemplate {
+	t.filename = filename
+	return t
+}
+
+// Filename returns the filename
+func (t *MemoryTemplate) Filename() string {
+	return t.filename
+}
+
+// Content returns the template content
+func (t *MemoryTemplate) Content() string {
+	return memoryTmpl
+}
+
+const memoryTmpl = `
+// Code generated by nero, DO NOT EDIT.
+package {{.Pkg}}
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	stdsort "sort"
+	"sync"
+	"sync/atomic"
+	"github.com/pkg/errors"
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/eval"
+	"github.com/sf9v/nero/sort"
+	{{range $import := .SchemaImports -}}
+		"{{$import}}"
+	{{end -}}
+	{{range $import := .ColumnImports -}}
+		"{{$import}}"
+	{{end -}}
+)
+
+// MemoryRepository implements the Repository interface by keeping
+// {{.Type.Name}} in memory. It is safe for concurrent use and is
+// mainly intended for testing.
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	seq   uint64
+	store *memoryStore
+	{{range $edge := .Edges -}}
+		{{lowerCamel $edge.StructField}}Loader func(context.Context, []{{type $edge.RefCol.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error)
+	{{end -}}
+}
+
+var _ Repository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository is a factory for MemoryRepository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		store: newMemoryStore(),
+	}
+}
+
+// memoryStore keeps the rows by identity in insertion order
+type memoryStore struct {
+	rows map[{{identType $}}]*{{type .Type.V}}
+	keys []{{identType $}}
+	// dirty is the set of modified keys, only tracked for transactions
+	dirty map[{{identType $}}]struct{}
+	{{range $edge := .ManyToManyEdges -}}
+		// {{lowerCamel $edge.StructField}}Links are the "{{$edge.Name}}" associations by key
+		{{lowerCamel $edge.StructField}}Links map[{{type $edge.Col.Type.V}}][]{{type $edge.RefCol.Type.V}}
+		{{lowerCamel $edge.StructField}}Dirty map[{{type $edge.Col.Type.V}}]struct{}
+	{{end -}}
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		rows: map[{{identType $}}]*{{type .Type.V}}{},
+		{{range $edge := .ManyToManyEdges -}}
+			{{lowerCamel $edge.StructField}}Links: map[{{type $edge.Col.Type.V}}][]{{type $edge.RefCol.Type.V}}{},
+		{{end -}}
+	}
+}
+
+// all returns all the rows in insertion order
+func (s *memoryStore) all() []*{{type .Type.V}} {
+	rows := make([]*{{type .Type.V}}, 0, len(s.keys))
+	for _, key := range s.keys {
+		rows = append(rows, s.rows[key])
+	}
+	return rows
+}
+
+// key returns the identity of the row
+func (s *memoryStore) key(row *{{type .Type.V}}) {{identType $}} {
+	{{if .HasCompositeIdent -}}
+		return Ident{
+			{{range $col := .Idents -}}
+				{{$col.Field}}: row.{{$col.Field}},
+			{{end -}}
+		}
+	{{- else -}}
+		return row.{{.Ident.Field}}
+	{{- end}}
+}
+
+// put inserts or replaces a row, rows must never be modified in
+// place since they are shared with the transaction snapshots
+func (s *memoryStore) put(row *{{type .Type.V}}) {
+	key := s.key(row)
+	if _, ok := s.rows[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.rows[key] = row
+
+	if s.dirty != nil {
+		s.dirty[key] = struct{}{}
+	}
+}
+
+// del deletes a row
+func (s *memoryStore) del(key {{identType $}}) {
+	if _, ok := s.rows[key]; !ok {
+		return
+	}
+	delete(s.rows, key)
+
+	for i, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:i:i], s.keys[i+1:]...)
+			break
+		}
+	}
+
+	if s.dirty != nil {
+		s.dirty[key] = struct{}{}
+	}
+}
+
+// snapshot returns a copy of the store that tracks modified keys
+func (s *memoryStore) snapshot() *memoryStore {
+	ss := &memoryStore{
+		rows:  make(map[{{identType $}}]*{{type .Type.V}}, len(s.rows)),
+		keys:  make([]{{identType $}}, len(s.keys)),
+		dirty: map[{{identType $}}]struct{}{},
+	}
+	for key, row := range s.rows {
+		ss.rows[key] = row
+	}
+	copy(ss.keys, s.keys)
+	{{range $edge := .ManyToManyEdges -}}
+		ss.{{lowerCamel $edge.StructField}}Links = make(map[{{type $edge.Col.Type.V}}][]{{type $edge.RefCol.Type.V}}, len(s.{{lowerCamel $edge.StructField}}Links))
+		for key, links := range s.{{lowerCamel $edge.StructField}}Links {
+			ss.{{lowerCamel $edge.StructField}}Links[key] = links
+		}
+		ss.{{lowerCamel $edge.StructField}}Dirty = map[{{type $edge.Col.Type.V}}]struct{}{}
+	{{end -}}
+	return ss
+}
+{{range $edge := .ManyToManyEdges}}
+// setLinks{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the key,
+// the links must never be modified in place same as the rows
+func (s *memoryStore) setLinks{{$edge.StructField}}(key {{type $edge.Col.Type.V}}, links []{{type $edge.RefCol.Type.V}}) {
+	if len(links) == 0 {
+		delete(s.{{lowerCamel $edge.StructField}}Links, key)
+	} else {
+		s.{{lowerCamel $edge.StructField}}Links[key] = links
+	}
+
+	if s.{{lowerCamel $edge.StructField}}Dirty != nil {
+		s.{{lowerCamel $edge.StructField}}Dirty[key] = struct{}{}
+	}
+}
+{{end}}
+
+// memoryTx is a transaction that works on a snapshot of the
+// rows, the modified rows are written back on commit
+type memoryTx struct {
+	ctx   context.Context
+	mu    sync.Mutex
+	repo  *MemoryRepository
+	store *memoryStore
+	done  bool
+}
+
+var _ nero.Tx = (*memoryTx)(nil)
+
+// Commit commits the transaction
+func (tx *memoryTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	// same as database/sql, the transaction is
+	// rolled back when the context is done
+	if err := tx.ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.repo.mu.Lock()
+	defer tx.repo.mu.Unlock()
+	for _, key := range tx.store.keys {
+		if _, ok := tx.store.dirty[key]; ok {
+			tx.repo.store.put(tx.store.rows[key])
+		}
+	}
+	for key := range tx.store.dirty {
+		if _, ok := tx.store.rows[key]; !ok {
+			tx.repo.store.del(key)
+		}
+	}
+	{{range $edge := .ManyToManyEdges -}}
+		for key := range tx.store.{{lowerCamel $edge.StructField}}Dirty {
+			tx.repo.store.setLinks{{$edge.StructField}}(key, tx.store.{{lowerCamel $edge.StructField}}Links[key])
+		}
+	{{end -}}
+
+	return nil
+}
+
+// Rollback aborts the transaction
+func (tx *memoryTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	return nil
+}
+
+// lockTx locks the transaction, the caller must unlock it when done
+func (mr *MemoryRepository) lockTx(tx nero.Tx) (*memoryTx, error) {
+	txx, ok := tx.(*memoryTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *memoryTx")
+	}
+
+	txx.mu.Lock()
+	if txx.done {
+		txx.mu.Unlock()
+		return nil, sql.ErrTxDone
+	}
+
+	return txx, nil
+}
+
+// Tx begins a new transaction
+func (mr *MemoryRepository) Tx(ctx context.Context) (nero.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+	return &memoryTx{
+		ctx:   ctx,
+		repo:  mr,
+		store: mr.store.snapshot(),
+	}, nil
+}
+
+// Create creates a new {{.Type.Name}}
+func (mr *MemoryRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.create(ctx, mr.store, c)
+}
+
+// CreateTx creates a new {{.Type.Name}} inside a transaction
+func (mr *MemoryRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.create(ctx, txx.store, c)
+}
+
+func (mr *MemoryRepository) create(ctx context.Context, s *memoryStore, c *Creator) ({{identType $}}, error) {
+	if err := ctx.Err(); err != nil {
+		return {{identZero $}}, err
+	}
+
+	row, err := mr.newRow(c)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	key := s.key(row)
+	if _, ok := s.rows[key]; ok {
+		return {{identZero $}}, errors.Errorf("duplicate identity %v", key)
+	}
+	s.put(row)
+
+	return key, nil
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (mr *MemoryRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.createReturning(ctx, mr.store, c)
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (mr *MemoryRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.createReturning(ctx, txx.store, c)
+}
+
+func (mr *MemoryRepository) createReturning(ctx context.Context, s *memoryStore, c *Creator) (*{{type .Type.V}}, error) {
+	key, err := mr.create(ctx, s, c)
+	if err != nil {
+		return nil, err
+	}
+
+	row := *s.rows[key]
+	return &row, nil
+}
+
+// newRow creates a new row from the creator
+func (mr *MemoryRepository) newRow(c *Creator) (*{{type .Type.V}}, error) {
+	row := &{{type .Type.V}}{
+		{{range $col := .Cols -}}
+			{{if ne $col.Auto true -}}
+				{{$col.Field}}: c.{{$col.Identifier}},
+			{{end -}}
+		{{end -}}
+	}
+
+	{{if .HasAutoIdent -}}
+		seq := atomic.AddUint64(&mr.seq, 1)
+		{{range $col := .Idents -}}
+			{{if $col.Auto -}}
+				if err := eval.Sequence(&row.{{$col.Field}}, seq); err != nil {
+					return nil, err
+				}
+			{{end -}}
+		{{end -}}
+	{{end}}
+
+	return row, nil
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (mr *MemoryRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.createMany(ctx, mr.store, cs...)
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (mr *MemoryRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.createMany(ctx, txx.store, cs...)
+}
+
+func (mr *MemoryRepository) createMany(ctx context.Context, s *memoryStore, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rows := []*{{type .Type.V}}{}
+	keys := map[{{identType $}}]struct{}{}
+	for _, c := range cs {
+		row, err := mr.newRow(c)
+		if err != nil {
+			return nil, err
+		}
+
+		key := s.key(row)
+		_, inStore := s.rows[key]
+		_, inRows := keys[key]
+		if inStore || inRows {
+			return nil, errors.Errorf("duplicate identity %v", key)
+		}
+
+		keys[key] = struct{}{}
+		rows = append(rows, row)
+	}
+
+	idents := make([]{{identType $}}, 0, len(rows))
+	for _, row := range rows {
+		s.put(row)
+		idents = append(idents, s.key(row))
+	}
+
+	return idents, nil
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (mr *MemoryRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	return mr.upsert(ctx, mr.store, u, u.Creators()[0])
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (mr *MemoryRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+	defer txx.mu.Unlock()
+
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	return mr.upsert(ctx, txx.store, u, u.Creators()[0])
+}
+
+func (mr *MemoryRepository) upsert(ctx context.Context, s *memoryStore, u *Upserter, c *Creator) ({{identType $}}, error) {
+	if err := ctx.Err(); err != nil {
+		return {{identZero $}}, err
+	}
+
+	row, err := mr.newRow(c)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	for _, existing := range s.all() {
+		if !mr.conflicts(existing, row, u.ConflictColumns()) {
+			continue
+		}
+
+		key := s.key(existing)
+		cols := u.UpdateColumns()
+		if len(cols) == 0 {
+			return key, nil
+		}
+
+		updated := *existing
+		mr.copyColumns(&updated, row, cols)
+		newKey := s.key(&updated)
+		if newKey != key {
+			if _, ok := s.rows[newKey]; ok {
+				return {{identZero $}}, errors.Errorf("duplicate identity %v", newKey)
+			}
+			s.del(key)
+		}
+		s.put(&updated)
+
+		return newKey, nil
+	}
+
+	key := s.key(row)
+	if _, ok := s.rows[key]; ok {
+		return {{identZero $}}, errors.Errorf("duplicate identity %v", key)
+	}
+	s.put(row)
+
+	return key, nil
+}
+
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (mr *MemoryRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	// the rows are upserted in a snapshot so that nothing
+	// is changed if one of them fails, same as a single insert
+	ss := mr.store.snapshot()
+	err := mr.upsertMany(ctx, ss, u)
+	if err != nil {
+		return err
+	}
+
+	mr.store = ss
+	mr.store.dirty = nil
+	return nil
+}
+
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (mr *MemoryRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.upsertMany(ctx, txx.store, u)
+}
+
+func (mr *MemoryRepository) upsertMany(ctx context.Context, s *memoryStore, u *Upserter) error {
+	if len(u.Creators()) == 0 {
+		return nil
+	}
+
+	if err := u.validate(false); err != nil {
+		return err
+	}
+
+	for _, c := range u.Creators() {
+		_, err := mr.upsert(ctx, s, u, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conflicts returns true if the rows have the same values in the columns
+func (mr *MemoryRepository) conflicts(a, b *{{type .Type.V}}, cols []Column) bool {
+	for _, col := range cols {
+		if !reflect.DeepEqual(mr.value(a, col.String()), mr.value(b, col.String())) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyColumns copies the values of the columns from src to dst
+func (mr *MemoryRepository) copyColumns(dst, src *{{type .Type.V}}, cols []Column) {
+	for _, col := range cols {
+		switch col {
+		{{range $col := .Cols -}}
+			{{if ne $col.Auto true -}}
+				case Column{{$col.Field}}:
+					dst.{{$col.Field}} = src.{{$col.Field}}
+			{{end -}}
+		{{end -}}
+		}
+	}
+}
+
+// Query queries many {{.Type.Name}}
+func (mr *MemoryRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+	return mr.query(ctx, mr.store, q)
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (mr *MemoryRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.query(ctx, txx.store, q)
+}
+
+func (mr *MemoryRepository) query(ctx context.Context, s *memoryStore, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the row locks are no-op so that the code using them can be tested
+
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mr.filter(ctx, s, s.all(), q.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.sortRows(rows, q.sfs)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.offset > 0 {
+		if int(q.offset) >= len(rows) {
+			rows = rows[:0]
+		} else {
+			rows = rows[q.offset:]
+		}
+	}
+
+	if q.limit > 0 && int(q.limit) < len(rows) {
+		rows = rows[:q.limit]
+	}
+
+	// return copies so that the callers can't modify the stored rows
+	cols := q.columns()
+	result := make([]*{{type .Type.V}}, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, project(row, cols))
+	}
+	{{if .Edges}}
+	err = mr.loadEdges(ctx, s, q, result)
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	if q.reversed {
+		reverseRows(result)
+	}
+
+	return result, nil
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (mr *MemoryRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+	return mr.queryOne(ctx, mr.store, q)
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (mr *MemoryRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.queryOne(ctx, txx.store, q)
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (mr *MemoryRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := mr.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (mr *MemoryRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := mr.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (mr *MemoryRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	rows, err := mr.Query(ctx, q.filter())
+	return int64(len(rows)), err
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (mr *MemoryRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	rows, err := mr.QueryTx(ctx, tx, q.filter())
+	return int64(len(rows)), err
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (mr *MemoryRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	count, err := mr.Count(ctx, q)
+	return count > 0, err
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (mr *MemoryRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	count, err := mr.CountTx(ctx, tx, q)
+	return count > 0, err
+}
+
+func (mr *MemoryRepository) queryOne(ctx context.Context, s *memoryStore, q *Queryer) (*{{type .Type.V}}, error) {
+	rows, err := mr.query(ctx, s, q)
+	if err != nil {
+		return nil, err
+	}
+
+	// same as the sql back-ends
+	if len(rows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// the closest row before the cursor is the last one
+	if _, before := q.Cursors(); before != "" {
+		return rows[len(rows)-1], nil
+	}
+
+	return rows[0], nil
+}
+
+{{if .Edges -}}
+{{range $edge := .Edges -}}
+// With{{$edge.StructField}}Loader sets the loader of the related rows of the "{{$edge.Name}}" edge.
+// The loader is required for eager-loading and filtering by the edge since
+// the related rows are not kept by this repository.
+func (mr *MemoryRepository) With{{$edge.StructField}}Loader(loader func(ctx context.Context, keys []{{type $edge.RefCol.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error)) *MemoryRepository {
+	mr.{{lowerCamel $edge.StructField}}Loader = loader
+	return mr
+}
+
+{{if $edge.IsManyToMany -}}
+// load{{$edge.StructField}} loads the associated rows of the "{{$edge.Name}}" edge by key
+func (mr *MemoryRepository) load{{$edge.StructField}}(ctx context.Context, s *memoryStore, keys []{{type $edge.Col.Type.V}}) (map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}, error) {
+	if mr.{{lowerCamel $edge.StructField}}Loader == nil {
+		return nil, errors.New("loader is not set")
+	}
+
+	links := map[{{type $edge.Col.Type.V}}][]{{type $edge.RefCol.Type.V}}{}
+	refKeys := []{{type $edge.RefCol.Type.V}}{}
+	seen := map[{{type $edge.RefCol.Type.V}}]struct{}{}
+	for _, key := range keys {
+		l, err := mr.links{{$edge.StructField}}(s, key)
+		if err != nil {
+			return nil, err
+		}
+
+		links[key] = l
+		for _, refKey := range l {
+			if _, ok := seen[refKey]; !ok {
+				seen[refKey] = struct{}{}
+				refKeys = append(refKeys, refKey)
+			}
+		}
+	}
+
+	loaded, err := mr.{{lowerCamel $edge.StructField}}Loader(ctx, refKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[{{type $edge.RefCol.Type.V}}]*{{type $edge.Ref.Type.V}}{}
+	for _, r := range loaded {
+		byKey[r.{{$edge.RefCol.Field}}] = r
+	}
+
+	related := map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}{}
+	for key, l := range links {
+		for _, refKey := range l {
+			if r, ok := byKey[refKey]; ok {
+				related[key] = append(related[key], r)
+			}
+		}
+	}
+
+	return related, nil
+}
+{{- else -}}
+// load{{$edge.StructField}} loads the related rows of the "{{$edge.Name}}" edge
+func (mr *MemoryRepository) load{{$edge.StructField}}(ctx context.Context, keys []{{type $edge.Col.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error) {
+	if mr.{{lowerCamel $edge.StructField}}Loader == nil {
+		return nil, errors.New("loader is not set")
+	}
+	return mr.{{lowerCamel $edge.StructField}}Loader(ctx, keys)
+}
+{{- end}}
+
+{{end -}}
+
+// loadEdges eager-loads the edges of the rows
+func (mr *MemoryRepository) loadEdges(ctx context.Context, s *memoryStore, q *Queryer, rows []*{{type .Type.V}}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	{{range $edge := .Edges}}
+	if q.with{{$edge.StructField}} {
+		related, err := mr.load{{$edge.StructField}}(ctx, {{if $edge.IsManyToMany}}s, {{end}}edge{{$edge.StructField}}Keys(rows))
+		if err != nil {
+			return errors.Wrap(err, "load {{$edge.Name}}")
+		}
+		setEdge{{$edge.StructField}}(rows, related)
+	}
+	{{end}}
+	return nil
+}
+
+// relatedKeys returns the keys of the rows that have related
+// rows for the edges of the predicates, by edge name
+func (mr *MemoryRepository) relatedKeys(ctx context.Context, s *memoryStore, rows []*{{type .Type.V}}, preds []*comparison.Predicate) (map[string]map[interface{}]struct{}, error) {
+	related := map[string]map[interface{}]struct{}{}
+	for _, p := range preds {
+		edge, ok := p.Arg.(*comparison.Edge)
+		if !ok {
+			continue
+		}
+
+		if _, ok := related[edge.Name]; ok {
+			continue
+		}
+
+		keys := map[interface{}]struct{}{}
+		switch edge.Name {
+		{{range $edge := .Edges -}}
+			case "{{$edge.Name}}":
+				{{if $edge.IsManyToMany -}}
+					// the associations are kept by this repository
+					for _, key := range edge{{$edge.StructField}}Keys(rows) {
+						links, err := mr.links{{$edge.StructField}}(s, key)
+						if err != nil {
+							return nil, errors.Wrap(err, "load {{$edge.Name}}")
+						}
+
+						if len(links) > 0 {
+							keys[key] = struct{}{}
+						}
+					}
+				{{else -}}
+					loaded, err := mr.load{{$edge.StructField}}(ctx, edge{{$edge.StructField}}Keys(rows))
+					if err != nil {
+						return nil, errors.Wrap(err, "load {{$edge.Name}}")
+					}
+
+					for _, r := range loaded {
+						keys[r.{{$edge.RefCol.Field}}] = struct{}{}
+					}
+				{{end -}}
+		{{end -}}
+		}
+		related[edge.Name] = keys
+	}
+
+	return related, nil
+}
+
+{{end -}}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (mr *MemoryRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.add{{$edge.StructField}}(ctx, mr.store, id, refIDs...)
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (mr *MemoryRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.add{{$edge.StructField}}(ctx, txx.store, id, refIDs...)
+}
+
+func (mr *MemoryRepository) add{{$edge.StructField}}(ctx context.Context, s *memoryStore, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	links, err := mr.links{{$edge.StructField}}(s, id)
+	if err != nil {
+		return err
+	}
+
+	links, err = mr.appendLinks{{$edge.StructField}}(links, refIDs)
+	if err != nil {
+		return err
+	}
+
+	return mr.putLinks{{$edge.StructField}}(s, id, links)
+}
+
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (mr *MemoryRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.remove{{$edge.StructField}}(ctx, mr.store, id, refIDs...)
+}
+
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (mr *MemoryRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.remove{{$edge.StructField}}(ctx, txx.store, id, refIDs...)
+}
+
+func (mr *MemoryRepository) remove{{$edge.StructField}}(ctx context.Context, s *memoryStore, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	links, err := mr.links{{$edge.StructField}}(s, id)
+	if err != nil {
+		return err
+	}
+
+	updated := []{{type $edge.RefCol.Type.V}}{}
+	for _, link := range links {
+		removed := false
+		for _, refID := range refIDs {
+			if link == refID {
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			updated = append(updated, link)
+		}
+	}
+
+	return mr.putLinks{{$edge.StructField}}(s, id, updated)
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (mr *MemoryRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.set{{$edge.StructField}}(ctx, mr.store, id, refIDs...)
+}
+
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (mr *MemoryRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.set{{$edge.StructField}}(ctx, txx.store, id, refIDs...)
+}
+
+func (mr *MemoryRepository) set{{$edge.StructField}}(ctx context.Context, s *memoryStore, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	links, err := mr.appendLinks{{$edge.StructField}}(nil, refIDs)
+	if err != nil {
+		return err
+	}
+
+	return mr.putLinks{{$edge.StructField}}(s, id, links)
+}
+
+// appendLinks{{$edge.StructField}} appends the associations, duplicates
+// are not allowed same as the join collection primary key
+func (mr *MemoryRepository) appendLinks{{$edge.StructField}}(links, refIDs []{{type $edge.RefCol.Type.V}}) ([]{{type $edge.RefCol.Type.V}}, error) {
+	updated := append([]{{type $edge.RefCol.Type.V}}{}, links...)
+	for _, refID := range refIDs {
+		for _, link := range updated {
+			if link == refID {
+				return nil, errors.Errorf("duplicate association %v", refID)
+			}
+		}
+		updated = append(updated, refID)
+	}
+
+	return updated, nil
+}
+
+// links{{$edge.StructField}} returns the "{{$edge.Name}}" associations of the key
+func (mr *MemoryRepository) links{{$edge.StructField}}(s *memoryStore, key {{type $edge.Col.Type.V}}) ([]{{type $edge.RefCol.Type.V}}, error) {
+	return s.{{lowerCamel $edge.StructField}}Links[key], nil
+}
+
+// putLinks{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the key
+func (mr *MemoryRepository) putLinks{{$edge.StructField}}(s *memoryStore, key {{type $edge.Col.Type.V}}, links []{{type $edge.RefCol.Type.V}}) error {
+	s.setLinks{{$edge.StructField}}(key, links)
+	return nil
+}
+{{end}}
+
+// value returns the value of the column
+func (mr *MemoryRepository) value(row *{{type .Type.V}}, col string) interface{} {
+	switch col {
+	{{range $col := .Cols -}}
+		case "{{$col.Name}}":
+			return row.{{$col.Field}}
+	{{end -}}
+	}
+
+	return nil
+}
+
+// filter returns the rows that matches the predicates
+func (mr *MemoryRepository) filter(ctx context.Context, s *memoryStore, rows []*{{type .Type.V}}, pfs []PredFunc) ([]*{{type .Type.V}}, error) {
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+
+	{{if .Edges -}}
+		related, err := mr.relatedKeys(ctx, s, rows, comparison.Flatten(pb.All()))
+		if err != nil {
+			return nil, err
+		}
+	{{end}}
+	filtered := []*{{type .Type.V}}{}
+	for _, row := range rows {
+		ok, err := mr.match(row, pb.All(){{if .Edges}}, related{{end}})
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			filtered = append(filtered, row)
+		}
+	}
+
+	return filtered, nil
+}
+
+// match returns true if the row matches all the predicates
+func (mr *MemoryRepository) match(row *{{type .Type.V}}, preds []*comparison.Predicate{{if .Edges}}, related map[string]map[interface{}]struct{}{{end}}) (bool, error) {
+	t, err := mr.evaluate(row, preds{{if .Edges}}, related{{end}})
+	return t == eval.True, err
+}
+
+// evaluate evaluates the conjunction of the predicates against the row,
+// the result is unknown if a null is compared so that negating it doesn't match the row
+func (mr *MemoryRepository) evaluate(row *{{type .Type.V}}, preds []*comparison.Predicate{{if .Edges}}, related map[string]map[interface{}]struct{}{{end}}) (eval.Truth, error) {
+	t := eval.True
+	for _, p := range preds {
+		if t == eval.False {
+			break
+		}
+
+		if p.Op.IsGroup() {
+			gt, err := mr.evaluateGroup(row, p{{if .Edges}}, related{{end}})
+			if err != nil {
+				return eval.False, err
+			}
+			t = t.And(gt)
+			continue
+		}
+
+		{{if .Edges -}}
+			if edge, ok := p.Arg.(*comparison.Edge); ok {
+				_, exists := related[edge.Name][mr.value(row, p.Col)]
+				if exists != (p.Op == comparison.Exists) {
+					return eval.False, nil
+				}
+				continue
+			}
+
+		{{end -}}
+		if p.Op == comparison.Matches {
+			return eval.False, &nero.UnsupportedError{Op: "full-text search", Repository: "MemoryRepository"}
+		}
+
+		arg := p.Arg
+		if col, ok := p.Arg.(Column); ok {
+			arg = mr.value(row, col.String())
+		}
+
+		pt, err := eval.Evaluate(p.Op, mr.value(row, p.Col), arg)
+		if err != nil {
+			return eval.False, errors.Wrapf(err, "column %q", p.Col)
+		}
+		t = t.And(pt)
+	}
+
+	return t, nil
+}
+
+// evaluateGroup evaluates the group predicate against the row
+func (mr *MemoryRepository) evaluateGroup(row *{{type .Type.V}}, p *comparison.Predicate{{if .Edges}}, related map[string]map[interface{}]struct{}{{end}}) (eval.Truth, error) {
+	if p.Op == comparison.Or {
+		t := eval.False
+		for _, pred := range p.Group() {
+			pt, err := mr.evaluate(row, []*comparison.Predicate{pred}{{if .Edges}}, related{{end}})
+			if err != nil {
+				return eval.False, err
+			}
+
+			t = t.Or(pt)
+			if t == eval.True {
+				break
+			}
+		}
+		return t, nil
+	}
+
+	t, err := mr.evaluate(row, p.Group(){{if .Edges}}, related{{end}})
+	if err != nil {
+		return eval.False, err
+	}
+
+	if p.Op == comparison.Not {
+		return t.Not(), nil
+	}
+	return t, nil
+}
+
+// sortRows sorts the rows in place
+func (mr *MemoryRepository) sortRows(rows []*{{type .Type.V}}, sfs []SortFunc) error {
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+
+	var err error
+	stdsort.SliceStable(rows, func(i, j int) bool {
+		less, lerr := mr.less(rows[i], rows[j], sorts.All())
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		return less
+	})
+
+	return err
+}
+
+// less returns true if row a should be sorted before row b
+func (mr *MemoryRepository) less(a, b *{{type .Type.V}}, sorts []*sort.Sort) (bool, error) {
+	for _, s := range sorts {
+		if s.Rank != nil {
+			return false, &nero.UnsupportedError{Op: "full-text search", Repository: "MemoryRepository"}
+		}
+
+		cmp, err := eval.Compare(mr.value(a, s.Col), mr.value(b, s.Col))
+		if err != nil {
+			return false, errors.Wrapf(err, "column %q", s.Col)
+		}
+
+		if cmp == 0 {
+			continue
+		}
+
+		if s.Direction == sort.Desc {
+			return cmp > 0, nil
+		}
+
+		return cmp < 0, nil
+	}
+
+	return false, nil
+}
+
+// Update updates {{.Type.Name}}
+func (mr *MemoryRepository) Update(ctx context.Context, u *Updater) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	rows, err := mr.update(ctx, mr.store, u)
+	return int64(len(rows)), err
+}
+
+// UpdateTx updates {{.Type.Name}} inside a transaction
+func (mr *MemoryRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updater) (int64, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	defer txx.mu.Unlock()
+
+	rows, err := mr.update(ctx, txx.store, u)
+	return int64(len(rows)), err
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (mr *MemoryRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.update(ctx, mr.store, u)
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (mr *MemoryRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.update(ctx, txx.store, u)
+}
+
+// update updates the matching rows and returns copies of the updated rows
+func (mr *MemoryRepository) update(ctx context.Context, s *memoryStore, u *Updater) ([]*{{type .Type.V}}, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	setters := []func(*{{type .Type.V}}){}
+	{{range $col := .Cols -}}
+		{{if ne $col.Auto true -}}
+			if u.IsSet(Column{{$col.Field}}) {
+				setters = append(setters, func(row *{{type $.Type.V}}) {
+					row.{{$col.Field}} = u.{{$col.Identifier}}
+				})
+			}
+		{{end -}}
+	{{end}}
+
+	// same as the sql back-ends
+	if len(setters) == 0 && len(u.exprs) == 0 {
+		return nil, errors.New("nothing to update")
+	}
+
+	rows, err := mr.filter(ctx, s, s.all(), u.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*{{type .Type.V}}, 0, len(rows))
+	for _, row := range rows {
+		key := s.key(row)
+		updated := *row
+		for _, set := range setters {
+			set(&updated)
+		}
+		for _, e := range u.exprs {
+			if err := e.apply(&updated, row); err != nil {
+				return nil, err
+			}
+		}
+
+		if newKey := s.key(&updated); newKey != key {
+			if _, ok := s.rows[newKey]; ok {
+				return nil, errors.Errorf("duplicate identity %v", newKey)
+			}
+			s.del(key)
+		}
+		s.put(&updated)
+
+		cp := updated
+		result = append(result, &cp)
+	}
+
+	return result, nil
+}
+
+// Delete deletes {{.Type.Name}}
+func (mr *MemoryRepository) Delete(ctx context.Context, d *Deleter) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	rows, err := mr.delete(ctx, mr.store, d)
+	return int64(len(rows)), err
+}
+
+// DeleteTx deletes {{.Type.Name}} inside a transaction
+func (mr *MemoryRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Deleter) (int64, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	defer txx.mu.Unlock()
+
+	rows, err := mr.delete(ctx, txx.store, d)
+	return int64(len(rows)), err
+}
+
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (mr *MemoryRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return mr.delete(ctx, mr.store, d)
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (mr *MemoryRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.delete(ctx, txx.store, d)
+}
+
+// delete deletes the matching rows and returns them
+func (mr *MemoryRepository) delete(ctx context.Context, s *memoryStore, d *Deleter) ([]*{{type .Type.V}}, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := mr.filter(ctx, s, s.all(), d.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*{{type .Type.V}}, 0, len(rows))
+	for _, row := range rows {
+		s.del(s.key(row))
+
+		cp := *row
+		result = append(result, &cp)
+	}
+
+	return result, nil
+}
+
+// Aggregate runs aggregate operations
+func (mr *MemoryRepository) Aggregate(ctx context.Context, a *Aggregator) error {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+	return mr.aggregate(ctx, mr.store, a)
+}
+
+// AggregateTx runs aggregate operations inside a transaction
+func (mr *MemoryRepository) AggregateTx(ctx context.Context, tx nero.Tx, a *Aggregator) error {
+	txx, err := mr.lockTx(tx)
+	if err != nil {
+		return err
+	}
+	defer txx.mu.Unlock()
+
+	return mr.aggregate(ctx, txx.store, a)
+}
+
+func (mr *MemoryRepository) aggregate(ctx context.Context, s *memoryStore, a *Aggregator) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+
+	v := reflect.ValueOf(a.v).Elem()
+	t := reflect.TypeOf(v.Interface()).Elem()
+	if t.NumField() != len(aggs.All()) {
+		return errors.New("aggregate columns and destination struct field count should match")
+	}
+
+	rows, err := mr.filter(ctx, s, s.all(), a.pfs)
+	if err != nil {
+		return err
+	}
+
+	// group the rows by the values of the group columns,
+	// without group columns all the rows are in one group
+	groups := [][]*{{type .Type.V}}{}
+	if len(a.groups) == 0 {
+		groups = append(groups, rows)
+	}
+	for _, row := range rows {
+		if len(a.groups) == 0 {
+			break
+		}
+
+		found := false
+		for i, group := range groups {
+			eq := true
+			for _, col := range a.groups {
+				eq, err = eval.Equal(mr.value(row, col.String()),
+					mr.value(group[0], col.String()))
+				if err != nil {
+					return err
+				}
+
+				if !eq {
+					break
+				}
+			}
+
+			if eq {
+				groups[i] = append(group, row)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			groups = append(groups, []*{{type .Type.V}}{row})
+		}
+	}
+
+	sorts := &sort.Sorts{}
+	for _, sf := range a.sfs {
+		sf(sorts)
+	}
+	stdsort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i]) == 0 || len(groups[j]) == 0 {
+			return false
+		}
+
+		less, lerr := mr.less(groups[i][0], groups[j][0], sorts.All())
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		return less
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		ve := reflect.New(t).Elem()
+		for i, agg := range aggs.All() {
+			vals := make([]interface{}, 0, len(group))
+			for _, row := range group {
+				vals = append(vals, mr.value(row, agg.Col))
+			}
+
+			res, err := eval.Aggregate(agg.Fn, vals)
+			if err != nil {
+				return errors.Wrapf(err, "column %q", agg.Col)
+			}
+
+			err = eval.Assign(ve.Field(i).Addr().Interface(), res)
+			if err != nil {
+				return errors.Wrapf(err, "column %q", agg.Col)
+			}
+		}
+
+		v.Set(reflect.Append(v, ve))
+	}
+
+	return nil
+}
+`
@@ -0,0 +1,17 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTemplate(t *testing.T) {
+	tmpl := NewMemoryTemplate().WithFilename("mem.go")
+
+	assert.Equal(t, "mem.go", tmpl.Filename())
+
+	_, err := ParseTemplate(tmpl.Content())
+	require.NoError(t, err)
+}
@@ -0,0 +1,557 @@
+package template
+
+import "github.com/sf9v/nero"
+
+// MockTemplate is the template for generating a mock repository
+type MockTemplate struct {
+	filename string
+}
+
+var _ nero.Templater = (*MockTemplate)(nil)
+
+// NewMockTemplate returns a new MockTemplate
+func NewMockTemplate() *MockTemplate {
+	return &MockTemplate{
+		filename: "mock.go",
+	}
+}
+
+// WithFilename overrides the default filename
+func (t *MockTemplate) WithFilename(filename string) *MockTemplate {
+	t.filename = filename
+	return t
+}
+
+// Filename returns the filename
+func (t *MockTemplate) Filename() string {
+	return t.filename
+}
+
+// Content returns the template content
+func (t *MockTemplate) Content() string {
+	return mockTmpl
+}
+
+const mockTmpl = `
+// Code generated by nero, DO NOT EDIT.
+package {{.Pkg}}
+
+import (
+	"context"
+	"sync"
+	"github.com/sf9v/nero"
+	{{range $import := .SchemaImports -}}
+		"{{$import}}"
+	{{end -}}
+	{{range $import := .ColumnImports -}}
+		"{{$import}}"
+	{{end -}}
+)
+
+// MockRepository is a mock implementation of the Repository interface.
+// It records the calls and returns the results of the <Method>Func
+// fields, zero values are returned if the field is not set.
+type MockRepository struct {
+	TxFunc                func(context.Context) (nero.Tx, error)
+	CreateFunc            func(context.Context, *Creator) ({{identType $}}, error)
+	CreateTxFunc          func(context.Context, nero.Tx, *Creator) ({{identType $}}, error)
+	CreateReturningFunc   func(context.Context, *Creator) (*{{type .Type.V}}, error)
+	CreateReturningTxFunc func(context.Context, nero.Tx, *Creator) (*{{type .Type.V}}, error)
+	CreateManyFunc        func(context.Context, ...*Creator) ([]{{identType $}}, error)
+	CreateManyTxFunc      func(context.Context, nero.Tx, ...*Creator) ([]{{identType $}}, error)
+	UpsertFunc            func(context.Context, *Upserter) ({{identType $}}, error)
+	UpsertTxFunc          func(context.Context, nero.Tx, *Upserter) ({{identType $}}, error)
+	UpsertManyFunc        func(context.Context, *Upserter) error
+	UpsertManyTxFunc      func(context.Context, nero.Tx, *Upserter) error
+	QueryFunc             func(context.Context, *Queryer) ([]*{{type .Type.V}}, error)
+	QueryTxFunc           func(context.Context, nero.Tx, *Queryer) ([]*{{type .Type.V}}, error)
+	QueryOneFunc          func(context.Context, *Queryer) (*{{type .Type.V}}, error)
+	QueryOneTxFunc        func(context.Context, nero.Tx, *Queryer) (*{{type .Type.V}}, error)
+	QueryPageFunc         func(context.Context, *Queryer) (*Page, error)
+	QueryPageTxFunc       func(context.Context, nero.Tx, *Queryer) (*Page, error)
+	CountFunc             func(context.Context, *Queryer) (int64, error)
+	CountTxFunc           func(context.Context, nero.Tx, *Queryer) (int64, error)
+	ExistsFunc            func(context.Context, *Queryer) (bool, error)
+	ExistsTxFunc          func(context.Context, nero.Tx, *Queryer) (bool, error)
+	UpdateFunc            func(context.Context, *Updater) (int64, error)
+	UpdateTxFunc          func(context.Context, nero.Tx, *Updater) (int64, error)
+	UpdateReturningFunc   func(context.Context, *Updater) ([]*{{type .Type.V}}, error)
+	UpdateReturningTxFunc func(context.Context, nero.Tx, *Updater) ([]*{{type .Type.V}}, error)
+	DeleteFunc            func(context.Context, *Deleter) (int64, error)
+	DeleteTxFunc          func(context.Context, nero.Tx, *Deleter) (int64, error)
+	DeleteReturningFunc   func(context.Context, *Deleter) ([]*{{type .Type.V}}, error)
+	DeleteReturningTxFunc func(context.Context, nero.Tx, *Deleter) ([]*{{type .Type.V}}, error)
+	AggregateFunc         func(context.Context, *Aggregator) error
+	AggregateTxFunc       func(context.Context, nero.Tx, *Aggregator) error
+	{{range $edge := .ManyToManyEdges -}}
+		Add{{$edge.StructField}}Func      func(context.Context, {{type $edge.Col.Type.V}}, ...{{type $edge.RefCol.Type.V}}) error
+		Add{{$edge.StructField}}TxFunc    func(context.Context, nero.Tx, {{type $edge.Col.Type.V}}, ...{{type $edge.RefCol.Type.V}}) error
+		Remove{{$edge.StructField}}Func   func(context.Context, {{type $edge.Col.Type.V}}, ...{{type $edge.RefCol.Type.V}}) error
+		Remove{{$edge.StructField}}TxFunc func(context.Context, nero.Tx, {{type $edge.Col.Type.V}}, ...{{type $edge.RefCol.Type.V}}) error
+		Set{{$edge.StructField}}Func      func(context.Context, {{type $edge.Col.Type.V}}, ...{{type $edge.RefCol.Type.V}}) error
+		Set{{$edge.StructField}}TxFunc    func(context.Context, nero.Tx, {{type $edge.Col.Type.V}}, ...{{type $edge.RefCol.Type.V}}) error
+	{{end -}}
+
+	mu    sync.Mutex
+	calls []*MockCall
+}
+
+var _ Repository = (*MockRepository)(nil)
+
+// MockCall is a recorded call to the MockRepository, only the
+// fields that are relevant to the method are set
+type MockCall struct {
+	Method     string
+	Tx         nero.Tx
+	Creators   []*Creator
+	Upserter   *Upserter
+	Queryer    *Queryer
+	Updater    *Updater
+	Deleter    *Deleter
+	Aggregator *Aggregator
+	{{if .ManyToManyEdges -}}
+		// Ident and RefIdents are the arguments of the association methods
+		Ident     interface{}
+		RefIdents []interface{}
+	{{end -}}
+}
+
+// NewMockRepository is a factory for MockRepository
+func NewMockRepository() *MockRepository {
+	return &MockRepository{}
+}
+
+// Calls returns the recorded calls of the method,
+// all the calls are returned if method is empty
+func (m *MockRepository) Calls(method string) []*MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := []*MockCall{}
+	for _, call := range m.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// Reset clears the recorded calls
+func (m *MockRepository) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *MockRepository) record(call *MockCall) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+}
+
+// MockTx is a mock transaction that records if it was committed or rolled back
+type MockTx struct {
+	mu         sync.Mutex
+	committed  bool
+	rolledBack bool
+}
+
+var _ nero.Tx = (*MockTx)(nil)
+
+// Commit commits the transaction
+func (tx *MockTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.committed = true
+	return nil
+}
+
+// Rollback rolls back the transaction
+func (tx *MockTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.rolledBack = true
+	return nil
+}
+
+// Committed returns true if the transaction was committed
+func (tx *MockTx) Committed() bool {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return tx.committed
+}
+
+// RolledBack returns true if the transaction was rolled back
+func (tx *MockTx) RolledBack() bool {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return tx.rolledBack
+}
+
+// Tx begins a new transaction, a *MockTx is returned if TxFunc is not set
+func (m *MockRepository) Tx(ctx context.Context) (nero.Tx, error) {
+	m.record(&MockCall{Method: "Tx"})
+	if m.TxFunc != nil {
+		return m.TxFunc(ctx)
+	}
+	return &MockTx{}, nil
+}
+
+// Create creates a new {{.Type.Name}}
+func (m *MockRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
+	m.record(&MockCall{Method: "Create", Creators: []*Creator{c}})
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, c)
+	}
+	return {{identZero $}}, nil
+}
+
+// CreateTx creates a new {{.Type.Name}} inside a transaction
+func (m *MockRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
+	m.record(&MockCall{Method: "CreateTx", Tx: tx, Creators: []*Creator{c}})
+	if m.CreateTxFunc != nil {
+		return m.CreateTxFunc(ctx, tx, c)
+	}
+	return {{identZero $}}, nil
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (m *MockRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "CreateReturning", Creators: []*Creator{c}})
+	if m.CreateReturningFunc != nil {
+		return m.CreateReturningFunc(ctx, c)
+	}
+	return nil, nil
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (m *MockRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "CreateReturningTx", Tx: tx, Creators: []*Creator{c}})
+	if m.CreateReturningTxFunc != nil {
+		return m.CreateReturningTxFunc(ctx, tx, c)
+	}
+	return nil, nil
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (m *MockRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	m.record(&MockCall{Method: "CreateMany", Creators: cs})
+	if m.CreateManyFunc != nil {
+		return m.CreateManyFunc(ctx, cs...)
+	}
+	return nil, nil
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (m *MockRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	m.record(&MockCall{Method: "CreateManyTx", Tx: tx, Creators: cs})
+	if m.CreateManyTxFunc != nil {
+		return m.CreateManyTxFunc(ctx, tx, cs...)
+	}
+	return nil, nil
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (m *MockRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	m.record(&MockCall{Method: "Upsert", Upserter: u})
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, u)
+	}
+	return {{identZero $}}, nil
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (m *MockRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	m.record(&MockCall{Method: "UpsertTx", Tx: tx, Upserter: u})
+	if m.UpsertTxFunc != nil {
+		return m.UpsertTxFunc(ctx, tx, u)
+	}
+	return {{identZero $}}, nil
+}
+
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (m *MockRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	m.record(&MockCall{Method: "UpsertMany", Upserter: u})
+	if m.UpsertManyFunc != nil {
+		return m.UpsertManyFunc(ctx, u)
+	}
+	return nil
+}
+
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (m *MockRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
+	m.record(&MockCall{Method: "UpsertManyTx", Tx: tx, Upserter: u})
+	if m.UpsertManyTxFunc != nil {
+		return m.UpsertManyTxFunc(ctx, tx, u)
+	}
+	return nil
+}
+
+// Query queries many {{.Type.Name}}
+func (m *MockRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "Query", Queryer: q})
+	if m.QueryFunc != nil {
+		return m.QueryFunc(ctx, q)
+	}
+	return nil, nil
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (m *MockRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "QueryTx", Tx: tx, Queryer: q})
+	if m.QueryTxFunc != nil {
+		return m.QueryTxFunc(ctx, tx, q)
+	}
+	return nil, nil
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (m *MockRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "QueryOne", Queryer: q})
+	if m.QueryOneFunc != nil {
+		return m.QueryOneFunc(ctx, q)
+	}
+	return nil, nil
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (m *MockRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "QueryOneTx", Tx: tx, Queryer: q})
+	if m.QueryOneTxFunc != nil {
+		return m.QueryOneTxFunc(ctx, tx, q)
+	}
+	return nil, nil
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (m *MockRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	m.record(&MockCall{Method: "QueryPage", Queryer: q})
+	if m.QueryPageFunc != nil {
+		return m.QueryPageFunc(ctx, q)
+	}
+	return nil, nil
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (m *MockRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	m.record(&MockCall{Method: "QueryPageTx", Tx: tx, Queryer: q})
+	if m.QueryPageTxFunc != nil {
+		return m.QueryPageTxFunc(ctx, tx, q)
+	}
+	return nil, nil
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query
+func (m *MockRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	m.record(&MockCall{Method: "Count", Queryer: q})
+	if m.CountFunc != nil {
+		return m.CountFunc(ctx, q)
+	}
+	return 0, nil
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (m *MockRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	m.record(&MockCall{Method: "CountTx", Tx: tx, Queryer: q})
+	if m.CountTxFunc != nil {
+		return m.CountTxFunc(ctx, tx, q)
+	}
+	return 0, nil
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (m *MockRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	m.record(&MockCall{Method: "Exists", Queryer: q})
+	if m.ExistsFunc != nil {
+		return m.ExistsFunc(ctx, q)
+	}
+	return false, nil
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (m *MockRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	m.record(&MockCall{Method: "ExistsTx", Tx: tx, Queryer: q})
+	if m.ExistsTxFunc != nil {
+		return m.ExistsTxFunc(ctx, tx, q)
+	}
+	return false, nil
+}
+
+// Update updates {{.Type.Name}}
+func (m *MockRepository) Update(ctx context.Context, u *Updater) (int64, error) {
+	m.record(&MockCall{Method: "Update", Updater: u})
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, u)
+	}
+	return 0, nil
+}
+
+// UpdateTx updates {{.Type.Name}} inside a transaction
+func (m *MockRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updater) (int64, error) {
+	m.record(&MockCall{Method: "UpdateTx", Tx: tx, Updater: u})
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+	if m.UpdateTxFunc != nil {
+		return m.UpdateTxFunc(ctx, tx, u)
+	}
+	return 0, nil
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (m *MockRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "UpdateReturning", Updater: u})
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+	if m.UpdateReturningFunc != nil {
+		return m.UpdateReturningFunc(ctx, u)
+	}
+	return nil, nil
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (m *MockRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "UpdateReturningTx", Tx: tx, Updater: u})
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+	if m.UpdateReturningTxFunc != nil {
+		return m.UpdateReturningTxFunc(ctx, tx, u)
+	}
+	return nil, nil
+}
+
+// Delete deletes {{.Type.Name}}
+func (m *MockRepository) Delete(ctx context.Context, d *Deleter) (int64, error) {
+	m.record(&MockCall{Method: "Delete", Deleter: d})
+	if err := d.Guard(); err != nil {
+		return 0, err
+	}
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, d)
+	}
+	return 0, nil
+}
+
+// DeleteTx deletes {{.Type.Name}} inside a transaction
+func (m *MockRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Deleter) (int64, error) {
+	m.record(&MockCall{Method: "DeleteTx", Tx: tx, Deleter: d})
+	if err := d.Guard(); err != nil {
+		return 0, err
+	}
+	if m.DeleteTxFunc != nil {
+		return m.DeleteTxFunc(ctx, tx, d)
+	}
+	return 0, nil
+}
+
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (m *MockRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "DeleteReturning", Deleter: d})
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+	if m.DeleteReturningFunc != nil {
+		return m.DeleteReturningFunc(ctx, d)
+	}
+	return nil, nil
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (m *MockRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	m.record(&MockCall{Method: "DeleteReturningTx", Tx: tx, Deleter: d})
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+	if m.DeleteReturningTxFunc != nil {
+		return m.DeleteReturningTxFunc(ctx, tx, d)
+	}
+	return nil, nil
+}
+
+// Aggregate runs aggregate operations
+func (m *MockRepository) Aggregate(ctx context.Context, a *Aggregator) error {
+	m.record(&MockCall{Method: "Aggregate", Aggregator: a})
+	if m.AggregateFunc != nil {
+		return m.AggregateFunc(ctx, a)
+	}
+	return nil
+}
+
+// AggregateTx runs aggregate operations inside a transaction
+func (m *MockRepository) AggregateTx(ctx context.Context, tx nero.Tx, a *Aggregator) error {
+	m.record(&MockCall{Method: "AggregateTx", Tx: tx, Aggregator: a})
+	if m.AggregateTxFunc != nil {
+		return m.AggregateTxFunc(ctx, tx, a)
+	}
+	return nil
+}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (m *MockRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	m.record(&MockCall{Method: "Add{{$edge.StructField}}", Ident: id, RefIdents: mockRefIdents{{$edge.StructField}}(refIDs)})
+	if m.Add{{$edge.StructField}}Func != nil {
+		return m.Add{{$edge.StructField}}Func(ctx, id, refIDs...)
+	}
+	return nil
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (m *MockRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	m.record(&MockCall{Method: "Add{{$edge.StructField}}Tx", Tx: tx, Ident: id, RefIdents: mockRefIdents{{$edge.StructField}}(refIDs)})
+	if m.Add{{$edge.StructField}}TxFunc != nil {
+		return m.Add{{$edge.StructField}}TxFunc(ctx, tx, id, refIDs...)
+	}
+	return nil
+}
+
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (m *MockRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	m.record(&MockCall{Method: "Remove{{$edge.StructField}}", Ident: id, RefIdents: mockRefIdents{{$edge.StructField}}(refIDs)})
+	if m.Remove{{$edge.StructField}}Func != nil {
+		return m.Remove{{$edge.StructField}}Func(ctx, id, refIDs...)
+	}
+	return nil
+}
+
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (m *MockRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	m.record(&MockCall{Method: "Remove{{$edge.StructField}}Tx", Tx: tx, Ident: id, RefIdents: mockRefIdents{{$edge.StructField}}(refIDs)})
+	if m.Remove{{$edge.StructField}}TxFunc != nil {
+		return m.Remove{{$edge.StructField}}TxFunc(ctx, tx, id, refIDs...)
+	}
+	return nil
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (m *MockRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	m.record(&MockCall{Method: "Set{{$edge.StructField}}", Ident: id, RefIdents: mockRefIdents{{$edge.StructField}}(refIDs)})
+	if m.Set{{$edge.StructField}}Func != nil {
+		return m.Set{{$edge.StructField}}Func(ctx, id, refIDs...)
+	}
+	return nil
+}
+
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (m *MockRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	m.record(&MockCall{Method: "Set{{$edge.StructField}}Tx", Tx: tx, Ident: id, RefIdents: mockRefIdents{{$edge.StructField}}(refIDs)})
+	if m.Set{{$edge.StructField}}TxFunc != nil {
+		return m.Set{{$edge.StructField}}TxFunc(ctx, tx, id, refIDs...)
+	}
+	return nil
+}
+
+func mockRefIdents{{$edge.StructField}}(refIDs []{{type $edge.RefCol.Type.V}}) []interface{} {
+	refIdents := make([]interface{}, 0, len(refIDs))
+	for _, refID := range refIDs {
+		refIdents = append(refIdents, refID)
+	}
+	return refIdents
+}
+{{end -}}
+`
@@ -0,0 +1,17 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockTemplate(t *testing.T) {
+	tmpl := NewMockTemplate().WithFilename("mk.go")
+
+	assert.Equal(t, "mk.go", tmpl.Filename())
+
+	_, err := ParseTemplate(tmpl.Content())
+	require.NoError(t, err)
+}
@@ -0,0 +1,1525 @@
+package template
+
+import "github.com/sf9v/nero"
+
+// MySQLTemplate is the template for generating a mysql repository
+type MySQLTemplate struct {
+	filename string
+}
+
+var _ nero.Templater = (*MySQLTemplate)(nil)
+
+// NewMySQLTemplate returns a new MySQLTemplate
+func NewMySQLTemplate() *MySQLTemplate {
+	return &MySQLTemplate{
+		filename: "mysql.go",
+	}
+}
+
+// WithFilename overrides the default filename
+func (t *MySQLTemplate) WithFilename(filename string) *MySQLTemplate {
+	t.filename = filename
+	return t
+}
+
+// Filename returns the filename
+func (t *MySQLTemplate) Filename() string {
+	return t.filename
+}
+
+// Content returns the template content
+func (t *MySQLTemplate) Content() string {
+	return mysqlTmpl +
+		predsBldrFuncs("my", "MySQLRepository", "`%s`", `"LOWER(QIDENT) LIKE LOWER(?) ESCAPE '\\\\'"`) +
+		likeFuncs("my", "MySQLRepository", "`%s`", `"QIDENT LIKE BINARY ? ESCAPE '\\\\'"`, false) +
+		textSearchFuncs("my", "MySQLRepository", false) + mysqlLockTmpl
+}
+
+const mysqlTmpl = `
+// Code generated by nero, DO NOT EDIT.
+package {{.Pkg}}
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"io"
+	"strings"
+	"log"
+	"os"
+	"github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/sort"
+	{{range $import := .SchemaImports -}}
+		"{{$import}}"
+	{{end -}}
+	{{range $import := .ColumnImports -}}
+		"{{$import}}"
+	{{end -}}
+)
+
+// MySQLRepository implements the Repository interface
+type MySQLRepository struct {
+	db  *sql.DB
+	logger nero.Logger
+	debug bool
+}
+
+var _ Repository = (*MySQLRepository)(nil)
+
+// NewMySQLRepository is a factory for MySQLRepository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db: db,
+	}
+}
+
+// Debug enables debug mode
+func (my *MySQLRepository) Debug() *MySQLRepository {
+	return &MySQLRepository{
+		db:  my.db,
+		debug: true,
+		logger: log.New(os.Stdout, "nero: ", 0),
+	}
+}
+
+// WithLogger overrides the default logger
+func (my *MySQLRepository) WithLogger(logger nero.Logger) *MySQLRepository {
+	my.logger = logger
+	return my
+}
+
+// Tx creates begins a new transaction
+func (my *MySQLRepository) Tx(ctx context.Context) (nero.Tx, error) {
+	return my.db.BeginTx(ctx, nil)
+}
+
+// Create creates a new {{.Type.Name}}
+func (my *MySQLRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
+	{{if .HasAutoIdent -}}
+		// LAST_INSERT_ID() is per-connection so the insert
+		// and the look-up must run in the same transaction
+		tx, err := my.db.BeginTx(ctx, nil)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		id, err := my.create(ctx, tx, c)
+		if err != nil {
+			return {{identZero $}}, rollback(tx, err)
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return id, nil
+	{{- else -}}
+		return my.create(ctx, my.db, c)
+	{{- end}}
+}
+
+// CreateTx creates a new {{.Type.Name}} inside a transaction
+func (my *MySQLRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.create(ctx, txx, c)
+}
+
+func (my *MySQLRepository) create(ctx context.Context, runner nero.SQLRunner, c *Creator) ({{identType $}}, error) {
+	columns, values := my.createValues(c)
+	qb := squirrel.Insert("` + bt + `{{.Collection}}` + bt + `").
+		Columns(columns...).
+		Values(values...).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	{{if .HasCompositeIdent -}}
+		_, err := qb.ExecContext(ctx)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		ident := Ident{
+			{{range $col := .Idents -}}
+				{{if ne $col.Auto true -}}
+					{{$col.Field}}: c.{{$col.Identifier}},
+				{{end -}}
+			{{end -}}
+		}
+		{{range $col := .Idents -}}
+			{{if $col.Auto -}}
+				// mysql allows only one auto-increment column per table
+				err = runner.QueryRowContext(ctx, "SELECT LAST_INSERT_ID()").
+					Scan(&ident.{{$col.Field}})
+				if err != nil {
+					return {{identZero $}}, err
+				}
+			{{end -}}
+		{{end}}
+		return ident, nil
+	{{- else if .Ident.Auto -}}
+		_, err := qb.ExecContext(ctx)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		var {{.Ident.Identifier}} {{identType $}}
+		err = runner.QueryRowContext(ctx, "SELECT LAST_INSERT_ID()").
+			Scan(&{{.Ident.Identifier}})
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return {{.Ident.Identifier}}, nil
+	{{- else -}}
+		_, err := qb.ExecContext(ctx)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return c.{{.Ident.Identifier}}, nil
+	{{- end}}
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (my *MySQLRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	// the insert and the look-ups must run in the same transaction
+	tx, err := my.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	{{lowerCamel .Type.Name}}, err := my.createReturning(ctx, tx, c)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return {{lowerCamel .Type.Name}}, nil
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (my *MySQLRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.createReturning(ctx, txx, c)
+}
+
+func (my *MySQLRepository) createReturning(ctx context.Context, runner nero.SQLRunner, c *Creator) (*{{type .Type.V}}, error) {
+	ident, err := my.create(ctx, runner, c)
+	if err != nil {
+		return nil, err
+	}
+
+	// mysql doesn't support 'RETURNING' so
+	// we look-up the created row using the identity
+	row := my.buildSelect(&Queryer{}).
+		Where(my.identEq(ident)).
+		RunWith(runner).
+		QueryRowContext(ctx)
+	return my.scanColumns(row, allColumns())
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (my *MySQLRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	{{if .HasAutoIdent -}}
+		// LAST_INSERT_ID() is per-connection so the inserts
+		// and the look-ups must run in the same transaction
+	{{- else -}}
+		if len(groupCreators(cs)) <= 1 {
+			return my.createMany(ctx, my.db, cs...)
+		}
+
+		// the groups are inserted in a single transaction
+		// so that nothing is inserted if one of them fails
+	{{- end}}
+	tx, err := my.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	idents, err := my.createMany(ctx, tx, cs...)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return idents, nil
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (my *MySQLRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.createMany(ctx, txx, cs...)
+}
+
+func (my *MySQLRepository) createMany(ctx context.Context, runner nero.SQLRunner, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	{{if .HasAutoIdent -}}
+		// the rows are inserted one by one since the ids of a multiple-row insert
+		// are not guaranteed to be consecutive e.g. with the interleaved
+		// auto-increment lock mode or an auto_increment_increment > 1
+		idents := make([]{{identType $}}, 0, len(cs))
+		for _, c := range cs {
+			ident, err := my.create(ctx, runner, c)
+			if err != nil {
+				return nil, err
+			}
+			idents = append(idents, ident)
+		}
+
+		return idents, nil
+	{{- else -}}
+		// the creators that set the same columns are inserted in a single statement
+		for _, group := range groupCreators(cs) {
+			columns, _ := my.createValues(group[0])
+			qb := squirrel.Insert("` + bt + `{{.Collection}}` + bt + `").Columns(columns...)
+			for _, c := range group {
+				_, values := my.createValues(c)
+				qb = qb.Values(values...)
+			}
+
+			qb = qb.PlaceholderFormat(squirrel.Question)
+			if my.debug {
+				sql, args, err := qb.ToSql()
+				my.logger.Printf("method: CreateMany, stmt: %q, args: %v, error: %v", sql, args, err)
+			}
+
+			_, err := qb.RunWith(runner).ExecContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		idents := make([]{{identType $}}, 0, len(cs))
+		for _, c := range cs {
+			{{if .HasCompositeIdent -}}
+				idents = append(idents, Ident{
+					{{range $col := .Idents -}}
+						{{$col.Field}}: c.{{$col.Identifier}},
+					{{end -}}
+				})
+			{{- else -}}
+				idents = append(idents, c.{{.Ident.Identifier}})
+			{{- end}}
+		}
+
+		return idents, nil
+	{{- end}}
+}
+
+// createValues returns the set columns and values of the creator
+func (my *MySQLRepository) createValues(c *Creator) ([]string, []interface{}) {
+	columns := []string{}
+	values := []interface{}{}
+	{{range $col := .Cols }}
+		{{if ne $col.Auto true}}
+			if c.IsSet(Column{{$col.Field}}) {
+				columns = append(columns, "` + bt + `{{$col.Name}}` + bt + `")
+				{{if $col.Nullable -}}
+					if c.IsNull(Column{{$col.Field}}) {
+						values = append(values, nil)
+					} else {
+				{{end -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					values = append(values, nero.JSON(c.{{$col.Identifier}}))
+				{{else -}}
+					values = append(values, c.{{$col.Identifier}})
+				{{end -}}
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
+			}
+		{{end}}
+	{{end}}
+
+	return columns, values
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (my *MySQLRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	return my.upsert(ctx, my.db, u)
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (my *MySQLRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.upsert(ctx, txx, u)
+}
+
+func (my *MySQLRepository) upsert(ctx context.Context, runner nero.SQLRunner, u *Upserter) ({{identType $}}, error) {
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	columns, values := my.createValues(u.Creators()[0])
+	qb := squirrel.Insert("` + bt + `{{.Collection}}` + bt + `").
+		Columns(columns...).
+		Values(values...).
+		Suffix(my.onDuplicateKey(u)).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.ExecContext(ctx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	// the inserted or updated row is looked-up using the conflict
+	// columns since the last insert id is not set on update
+	return my.conflictIdent(ctx, runner, u, columns, values)
+}
+
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (my *MySQLRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	return my.upsertMany(ctx, my.db, u)
+}
+
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (my *MySQLRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.upsertMany(ctx, txx, u)
+}
+
+func (my *MySQLRepository) upsertMany(ctx context.Context, runner nero.SQLRunner, u *Upserter) error {
+	if len(u.Creators()) == 0 {
+		return nil
+	}
+
+	if err := u.validate(false); err != nil {
+		return err
+	}
+
+	columns, _ := my.createValues(u.Creators()[0])
+	qb := squirrel.Insert("` + bt + `{{.Collection}}` + bt + `").Columns(columns...)
+	for _, c := range u.Creators() {
+		_, values := my.createValues(c)
+		qb = qb.Values(values...)
+	}
+
+	qb = qb.Suffix(my.onDuplicateKey(u)).
+		PlaceholderFormat(squirrel.Question)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: UpsertMany, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// onDuplicateKey builds the 'ON DUPLICATE KEY UPDATE' clause of the upsert,
+// mysql doesn't have a conflict target so any unique key can conflict
+func (my *MySQLRepository) onDuplicateKey(u *Upserter) string {
+	updates := []string{}
+	for _, col := range u.UpdateColumns() {
+		updates = append(updates, fmt.Sprintf("` + bt + `%s` + bt + ` = VALUES(` + bt + `%s` + bt + `)", col, col))
+	}
+
+	if len(updates) == 0 {
+		// a no-op update leaves the conflicting row as it is
+		col := u.ConflictColumns()[0]
+		updates = append(updates, fmt.Sprintf("` + bt + `%s` + bt + ` = ` + bt + `%s` + bt + `", col, col))
+	}
+
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+}
+
+// conflictIdent looks-up the identity of the conflicting row
+func (my *MySQLRepository) conflictIdent(ctx context.Context, runner nero.SQLRunner, u *Upserter, columns []string, values []interface{}) ({{identType $}}, error) {
+	eq := squirrel.Eq{}
+	for _, col := range u.ConflictColumns() {
+		name := "` + bt + `" + col.String() + "` + bt + `"
+		for i, column := range columns {
+			if column == name {
+				eq[name] = values[i]
+			}
+		}
+	}
+
+	qb := squirrel.Select({{range $i, $col := .Idents}}{{if $i}}, {{end}}"` + bt + `{{$col.Name}}` + bt + `"{{end}}).
+		From("` + bt + `{{.Collection}}` + bt + `").
+		Where(eq).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var ident {{identType $}}
+	err := qb.QueryRowContext(ctx).Scan(
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				&ident.{{$col.Field}},
+			{{end -}}
+		{{else -}}
+			&ident,
+		{{end -}}
+	)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return ident, nil
+}
+
+// Query queries many {{.Type.Name}}
+func (my *MySQLRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return my.query(ctx, my.db, q)
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (my *MySQLRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.query(ctx, txx, q)
+}
+
+func (my *MySQLRepository) query(ctx context.Context, runner nero.SQLRunner, q *Queryer) ([]*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := my.buildSelect(q)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Query, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := my.scanColumns(rows, q.columns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+	{{if .Edges}}
+	// close the rows before loading the edges using the same runner
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = my.loadEdges(ctx, runner, q, {{plural (lowerCamel .Type.Name)}})
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	if q.reversed {
+		reverseRows({{plural (lowerCamel .Type.Name)}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (my *MySQLRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return my.queryOne(ctx, my.db, q)
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (my *MySQLRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.queryOne(ctx, txx, q)
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (my *MySQLRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := my.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (my *MySQLRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := my.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (my *MySQLRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	if q.lock != nero.NoLock {
+		return 0, nero.ErrLockOutsideTx
+	}
+
+	return my.count(ctx, my.db, q)
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (my *MySQLRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.count(ctx, txx, q)
+}
+
+func (my *MySQLRepository) count(ctx context.Context, runner nero.SQLRunner, q *Queryer) (int64, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return 0, &nero.UnsupportedError{Op: "row locking in Count", Repository: "MySQLRepository"}
+	}
+
+	qb := squirrel.Select("COUNT(*)").
+		From("` + bt + `{{.Collection}}` + bt + `").
+		PlaceholderFormat(squirrel.Question)
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range my.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Count, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var count int64
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&count)
+	return count, err
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (my *MySQLRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	if q.lock != nero.NoLock {
+		return false, nero.ErrLockOutsideTx
+	}
+
+	return my.exists(ctx, my.db, q)
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (my *MySQLRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return false, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.exists(ctx, txx, q)
+}
+
+func (my *MySQLRepository) exists(ctx context.Context, runner nero.SQLRunner, q *Queryer) (bool, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return false, &nero.UnsupportedError{Op: "row locking in Exists", Repository: "MySQLRepository"}
+	}
+
+	sub := squirrel.Select("1").From("` + bt + `{{.Collection}}` + bt + `")
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range my.conds(pb.All()) {
+		sub = sub.Where(cond)
+	}
+	qb := squirrel.Select().
+		Column(squirrel.Expr("EXISTS(?)", sub)).
+		PlaceholderFormat(squirrel.Question)
+
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Exists, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var exists bool
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&exists)
+	return exists, err
+}
+
+func (my *MySQLRepository) queryOne(ctx context.Context, runner nero.SQLRunner, q *Queryer) (*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := my.buildSelect(q)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: QueryOne, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	{{lowerCamel .Type.Name}}, err := my.scanColumns(qb.RunWith(runner).QueryRowContext(ctx), q.columns())
+	if err != nil {
+		return {{zero .Type.V}}, err
+	}
+	{{if .Edges}}
+	err = my.loadEdges(ctx, runner, q, []*{{type .Type.V}}{ {{lowerCamel .Type.Name}} })
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	return {{lowerCamel .Type.Name}}, nil
+}
+
+// scanColumns scans a row of the columns into {{.Type.Name}}
+func (my *MySQLRepository) scanColumns(scanner interface{ Scan(...interface{}) error }, cols []Column) (*{{type .Type.V}}, error) {
+	var {{lowerCamel .Type.Name}} {{type .Type.V}}
+	dest := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		switch col {
+		{{range $col := .Cols -}}
+		case Column{{$col.Field}}:
+			{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+				dest = append(dest, nero.JSON(&{{lowerCamel $.Type.Name}}.{{$col.Field}}))
+			{{else -}}
+				dest = append(dest, &{{lowerCamel $.Type.Name}}.{{$col.Field}})
+			{{end -}}
+		{{end -}}
+		}
+	}
+
+	err := scanner.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &{{lowerCamel .Type.Name}}, nil
+}
+
+// scanAll scans the rows into {{.Type.Name}} and closes them
+func (my *MySQLRepository) scanAll(rows *sql.Rows) ([]*{{type .Type.V}}, error) {
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := my.scanColumns(rows, allColumns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, rows.Err()
+}
+{{if .Edges}}
+// loadEdges eager-loads the edges of the rows
+func (my *MySQLRepository) loadEdges(ctx context.Context, runner nero.SQLRunner, q *Queryer, rows []*{{type .Type.V}}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	{{range $edge := .Edges}}
+	if q.with{{$edge.StructField}} {
+		related, err := my.load{{$edge.StructField}}(ctx, runner, edge{{$edge.StructField}}Keys(rows))
+		if err != nil {
+			return errors.Wrap(err, "load {{$edge.Name}}")
+		}
+		setEdge{{$edge.StructField}}(rows, related)
+	}
+	{{end}}
+	return nil
+}
+{{range $edge := .Edges}}
+{{if $edge.IsManyToMany -}}
+// load{{$edge.StructField}} queries the associated rows of the "{{$edge.Name}}" edge by key
+func (my *MySQLRepository) load{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, keys []{{type $edge.Col.Type.V}}) (map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	qb := squirrel.Select(
+		"` + bt + `{{$edge.Join}}` + bt + `.` + bt + `{{$edge.JoinCol}}` + bt + `",
+		{{range $col := $edge.Ref.Cols -}}
+			"` + bt + `{{$edge.Ref.Collection}}` + bt + `.` + bt + `{{$col.Name}}` + bt + `",
+		{{end -}}
+	).
+		From("` + bt + `{{$edge.Ref.Collection}}` + bt + `").
+		Join("` + bt + `{{$edge.Join}}` + bt + ` ON ` + bt + `{{$edge.Join}}` + bt + `.` + bt + `{{$edge.JoinRefCol}}` + bt + ` = ` + bt + `{{$edge.Ref.Collection}}` + bt + `.` + bt + `{{$edge.RefCol.Name}}` + bt + `").
+		Where(squirrel.Eq{"` + bt + `{{$edge.Join}}` + bt + `.` + bt + `{{$edge.JoinCol}}` + bt + `": args}).
+		PlaceholderFormat(squirrel.Question)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var key {{type $edge.Col.Type.V}}
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			&key,
+			{{range $col := $edge.Ref.Cols -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					nero.JSON(&item.{{$col.Field}}),
+				{{else -}}
+					&item.{{$col.Field}},
+				{{end -}}
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related[key] = append(related[key], &item)
+	}
+
+	return related, nil
+}
+{{- else -}}
+// load{{$edge.StructField}} queries the related rows of the "{{$edge.Name}}" edge
+func (my *MySQLRepository) load{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, keys []{{type $edge.Col.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	qb := squirrel.Select(
+		{{range $col := $edge.Ref.Cols -}}
+			"` + bt + `{{$col.Name}}` + bt + `",
+		{{end -}}
+	).
+		From("` + bt + `{{$edge.Ref.Collection}}` + bt + `").
+		Where(squirrel.Eq{"` + bt + `{{$edge.RefCol.Name}}` + bt + `": args}).
+		PlaceholderFormat(squirrel.Question)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			{{range $col := $edge.Ref.Cols -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					nero.JSON(&item.{{$col.Field}}),
+				{{else -}}
+					&item.{{$col.Field}},
+				{{end -}}
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related = append(related, &item)
+	}
+
+	return related, nil
+}
+{{- end}}
+{{end}}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (my *MySQLRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return my.add{{$edge.StructField}}(ctx, my.db, id, refIDs...)
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (my *MySQLRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.add{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// add{{$edge.StructField}} inserts the join rows directly, the join collection
+// has no schema so there's no Creator for it
+func (my *MySQLRepository) add{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	qb := squirrel.Insert("` + bt + `{{$edge.Join}}` + bt + `").
+		Columns("` + bt + `{{$edge.JoinCol}}` + bt + `", "` + bt + `{{$edge.JoinRefCol}}` + bt + `").
+		PlaceholderFormat(squirrel.Question)
+	for _, refID := range refIDs {
+		qb = qb.Values(id, refID)
+	}
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Add{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (my *MySQLRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return my.remove{{$edge.StructField}}(ctx, my.db, id, refIDs...)
+}
+
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (my *MySQLRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.remove{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// remove{{$edge.StructField}} deletes the join rows directly since there's no
+// Deleter for the join collection, the delete is always filtered by the identity
+// and is skipped without refIDs, so unlike an unguarded Deleter it can't remove
+// the associations of the other rows
+func (my *MySQLRepository) remove{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	refArgs := make([]interface{}, 0, len(refIDs))
+	for _, refID := range refIDs {
+		refArgs = append(refArgs, refID)
+	}
+
+	qb := squirrel.Delete("` + bt + `{{$edge.Join}}` + bt + `").
+		Where(squirrel.Eq{"` + bt + `{{$edge.JoinCol}}` + bt + `": id, "` + bt + `{{$edge.JoinRefCol}}` + bt + `": refArgs}).
+		PlaceholderFormat(squirrel.Question)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Remove{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (my *MySQLRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	tx, err := my.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = my.set{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (my *MySQLRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.set{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// set{{$edge.StructField}} replaces the join rows of the identity, the delete
+// is filtered by the identity so only its associations are removed
+func (my *MySQLRepository) set{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	qb := squirrel.Delete("` + bt + `{{$edge.Join}}` + bt + `").
+		Where(squirrel.Eq{"` + bt + `{{$edge.JoinCol}}` + bt + `": id}).
+		PlaceholderFormat(squirrel.Question)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Set{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return my.add{{$edge.StructField}}(ctx, runner, id, refIDs...)
+}
+{{end}}
+{{end -}}
+
+func (my *MySQLRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
+	columns := []string{}
+	for _, col := range q.columns() {
+		columns = append(columns, "` + bt + `" + col.String() + "` + bt + `")
+	}
+	qb := squirrel.Select(columns...).
+		From("` + bt + `{{.Collection}}` + bt + `").
+		PlaceholderFormat(squirrel.Question)
+
+	pfs := q.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range my.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	sfs := q.sfs
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(my.rank(s))
+			continue
+		}
+		col := "` + bt + `" + s.Col + "` + bt + `"
+		switch s.Direction {
+		case sort.Asc:
+			qb = qb.OrderBy(col + " ASC")
+		case sort.Desc:
+			qb = qb.OrderBy(col + " DESC")
+		}
+	}
+
+	if q.limit > 0 {
+		qb = qb.Limit(uint64(q.limit))
+	}
+
+	if q.offset > 0 {
+		qb = qb.Offset(uint64(q.offset))
+	}
+
+	if q.lock != nero.NoLock {
+		qb = qb.SuffixExpr(my.lock(q))
+	}
+
+	return qb
+}
+
+// Update updates {{.Type.Name}}
+func (my *MySQLRepository) Update(ctx context.Context, u *Updater) (int64, error) {
+	return my.update(ctx, my.db, u)
+}
+
+// UpdateTx updates {{.Type.Name}} inside a transaction
+func (my *MySQLRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updater) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.update(ctx, txx, u)
+}
+
+func (my *MySQLRepository) update(ctx context.Context, runner nero.SQLRunner, u *Updater) (int64, error) {
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+
+	qb := my.buildUpdate(u)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (my *MySQLRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	tx, err := my.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := my.updateReturning(ctx, tx, u)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (my *MySQLRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.updateReturning(ctx, txx, u)
+}
+
+func (my *MySQLRepository) updateReturning(ctx context.Context, runner nero.SQLRunner, u *Updater) ([]*{{type .Type.V}}, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	// mysql doesn't support 'RETURNING' so we lock
+	// the matching rows before updating them
+	rows, err := my.buildSelect(&Queryer{pfs: u.pfs}).
+		Suffix("FOR UPDATE").
+		RunWith(runner).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := my.scanAll(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = my.update(ctx, runner, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if len({{plural (lowerCamel .Type.Name)}}) == 0 {
+		return {{plural (lowerCamel .Type.Name)}}, nil
+	}
+
+	where := squirrel.Or{}
+	for _, row := range {{plural (lowerCamel .Type.Name)}} {
+		{{range $col := .Idents -}}
+			{{if ne $col.Auto true -}}
+				for _, e := range u.exprs {
+					if e.col != Column{{$col.Field}} {
+						continue
+					}
+					// mysql applies the assignments in order
+					if err := e.apply(row, row); err != nil {
+						return nil, err
+					}
+				}
+				if u.IsSet(Column{{$col.Field}}) {
+					row.{{$col.Field}} = u.{{$col.Identifier}}
+				}
+			{{end -}}
+		{{end -}}
+		{{if .HasCompositeIdent -}}
+			where = append(where, my.identEq(Ident{
+				{{range $col := .Idents -}}
+					{{$col.Field}}: row.{{$col.Field}},
+				{{end -}}
+			}))
+		{{- else -}}
+			where = append(where, my.identEq(row.{{.Ident.Field}}))
+		{{- end}}
+	}
+
+	rows, err = my.buildSelect(&Queryer{}).
+		Where(where).
+		RunWith(runner).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return my.scanAll(rows)
+}
+
+// identEq returns the condition matching the identity
+func (my *MySQLRepository) identEq(ident {{identType $}}) squirrel.Eq {
+	return squirrel.Eq{
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				"` + bt + `{{$col.Name}}` + bt + `": ident.{{$col.Field}},
+			{{end -}}
+		{{else -}}
+			"` + bt + `{{.Ident.Name}}` + bt + `": ident,
+		{{end -}}
+	}
+}
+
+// buildUpdate builds the update statement
+func (my *MySQLRepository) buildUpdate(u *Updater) squirrel.UpdateBuilder {
+	qb := squirrel.Update("` + bt + `{{.Collection}}` + bt + `").
+		PlaceholderFormat(squirrel.Question)
+	// expressions go first since MySQL evaluates the assignments from left to right
+	for _, e := range u.exprs {
+		qb = qb.Set("` + bt + `" + e.col.String() + "` + bt + `", my.updateExpr(e))
+	}
+	{{range $col := .Cols}}
+		{{if ne $col.Auto true}}
+			if u.IsSet(Column{{$col.Field}}) {
+				{{if $col.Nullable -}}
+					if u.IsNull(Column{{$col.Field}}) {
+						qb = qb.Set("` + bt + `{{$col.Name}}` + bt + `", nil)
+					} else {
+				{{end -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					qb = qb.Set("` + bt + `{{$col.Name}}` + bt + `", nero.JSON(u.{{$col.Identifier}}))
+				{{else -}}
+					qb = qb.Set("` + bt + `{{$col.Name}}` + bt + `", u.{{$col.Identifier}})
+				{{end -}}
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
+			}
+		{{end}}
+	{{end}}
+
+	pfs := u.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range my.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
+// updateExpr builds the value of an update expression
+func (my *MySQLRepository) updateExpr(e *updateExpr) squirrel.Sqlizer {
+	switch e.op {
+	case updateIncr:
+		return squirrel.Expr("` + bt + `" + e.col.String() + "` + bt + ` + ?", e.arg)
+	case updateDecr:
+		return squirrel.Expr("` + bt + `" + e.col.String() + "` + bt + ` - ?", e.arg)
+	}
+	return squirrel.Expr("` + bt + `" + e.arg.(Column).String() + "` + bt + `")
+}
+
+// Delete deletes {{.Type.Name}}
+func (my *MySQLRepository) Delete(ctx context.Context, d *Deleter) (int64, error) {
+	return my.delete(ctx, my.db, d)
+}
+
+// Delete deletes {{.Type.Name}} inside a transaction
+func (my *MySQLRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Deleter) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.delete(ctx, txx, d)
+}
+
+func (my *MySQLRepository) delete(ctx context.Context, runner nero.SQLRunner, d *Deleter) (int64, error) {
+	if err := d.Guard(); err != nil {
+		return 0, err
+	}
+
+	qb := my.buildDelete(d)
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Delete, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
+
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (my *MySQLRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	tx, err := my.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := my.deleteReturning(ctx, tx, d)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (my *MySQLRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.deleteReturning(ctx, txx, d)
+}
+
+func (my *MySQLRepository) deleteReturning(ctx context.Context, runner nero.SQLRunner, d *Deleter) ([]*{{type .Type.V}}, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	// mysql doesn't support 'RETURNING' so we lock
+	// the matching rows before deleting them
+	rows, err := my.buildSelect(&Queryer{pfs: d.pfs}).
+		Suffix("FOR UPDATE").
+		RunWith(runner).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := my.scanAll(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = my.delete(ctx, runner, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// buildDelete builds the delete statement
+func (my *MySQLRepository) buildDelete(d *Deleter) squirrel.DeleteBuilder {
+	qb := squirrel.Delete("` + bt + `{{.Collection}}` + bt + `").
+		PlaceholderFormat(squirrel.Question)
+
+	pfs := d.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range my.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
+// Aggregate runs aggregate operations
+func (my *MySQLRepository) Aggregate(ctx context.Context, a *Aggregator) error {
+	return my.aggregate(ctx, my.db, a)
+}
+
+// Aggregate runs aggregate operations inside a transaction
+func (my *MySQLRepository) AggregateTx(ctx context.Context, tx nero.Tx, a *Aggregator) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return my.aggregate(ctx, txx, a)
+}
+
+func (my *MySQLRepository) aggregate(ctx context.Context, runner nero.SQLRunner, a *Aggregator) error {
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+	cols := []string{}
+	for _, agg := range aggs.All() {
+		col := agg.Col
+		qcol := "` + bt + `" + col + "` + bt + `"
+		switch agg.Fn {
+		case aggregate.Avg:
+			cols = append(cols, "AVG("+qcol+") avg_"+col)
+		case aggregate.Count:
+			cols = append(cols, "COUNT("+qcol+") count_"+col)
+		case aggregate.Max:
+			cols = append(cols, "MAX("+qcol+") max_"+col)
+		case aggregate.Min:
+			cols = append(cols, "MIN("+qcol+") min_"+col)
+		case aggregate.Sum:
+			cols = append(cols, "SUM("+qcol+") sum_"+col)
+		case aggregate.None:
+			cols = append(cols, qcol)
+		}
+	}
+
+	qb := squirrel.Select(cols...).From("` + bt + `{{.Collection}}` + bt + `").
+		PlaceholderFormat(squirrel.Question)
+
+	groups := []string{}
+	for _, group := range a.groups {
+		groups = append(groups, "` + bt + `" + group.String() + "` + bt + `")
+	}
+	qb = qb.GroupBy(groups...)
+
+	pfs := a.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range my.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	sfs := a.sfs
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(my.rank(s))
+			continue
+		}
+		col := "` + bt + `" + s.Col + "` + bt + `"
+		switch s.Direction {
+		case sort.Asc:
+			qb = qb.OrderBy(col + " ASC")
+		case sort.Desc:
+			qb = qb.OrderBy(col + " DESC")
+		}
+	}
+
+	if my.debug {
+		sql, args, err := qb.ToSql()
+		my.logger.Printf("method: Aggregate, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	v := reflect.ValueOf(a.v).Elem()
+	t := reflect.TypeOf(v.Interface()).Elem()
+	if t.NumField() != len(cols) {
+		return errors.New("aggregate columns and destination struct field count should match")
+	}
+
+	for rows.Next() {
+		ve := reflect.New(t).Elem()
+		dest := make([]interface{}, ve.NumField())
+		for i := 0; i < ve.NumField(); i++ {
+			dest[i] = ve.Field(i).Addr().Interface()
+		}
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.Append(v, ve))
+	}
+
+	return nil
+}
+`
+
+// bt is a backtick which can't be used inside raw string literals
+const bt = "`"
+
+const mysqlLockTmpl = `
+// lock renders the row locking clause of the query, the shared lock is
+// rendered as 'LOCK IN SHARE MODE' since mariadb doesn't support 'FOR SHARE'
+// and mysql doesn't support the wait modifiers with 'LOCK IN SHARE MODE'
+func (my *MySQLRepository) lock(q *Queryer) squirrel.Sqlizer {
+	switch q.lock {
+	case nero.ForUpdate:
+		clause := q.lock.String()
+		if q.wait != nero.Wait {
+			clause += " " + q.wait.String()
+		}
+		return squirrel.Expr(clause)
+	case nero.ForShare:
+		if q.wait != nero.Wait {
+			return &nero.UnsupportedError{Op: q.lock.String() + " " + q.wait.String(),
+				Repository: "MySQLRepository"}
+		}
+		return squirrel.Expr("LOCK IN SHARE MODE")
+	}
+
+	return &nero.UnsupportedError{Op: q.lock.String(), Repository: "MySQLRepository"}
+}
+`
@@ -0,0 +1,17 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLTemplate(t *testing.T) {
+	tmpl := NewMySQLTemplate().WithFilename("my.go")
+
+	assert.Equal(t, "my.go", tmpl.Filename())
+
+	_, err := ParseTemplate(tmpl.Content())
+	require.NoError(t, err)
+}
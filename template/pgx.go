@@ -0,0 +1,1480 @@
+package template
+
+import "github.com/sf9v/nero"
+
+// PgxTemplate is the template for generating a postgres repository using pgx
+type PgxTemplate struct {
+	filename string
+}
+
+var _ nero.Templater = (*PgxTemplate)(nil)
+
+// NewPgxTemplate returns a new PgxTemplate
+func NewPgxTemplate() *PgxTemplate {
+	return &PgxTemplate{
+		filename: "pgx.go",
+	}
+}
+
+// WithFilename overrides the default filename
+func (t *PgxTemplate) WithFilename(filename string) *PgxTemplate {
+	t.filename = filename
+	return t
+}
+
+// Filename returns the filename
+func (t *PgxTemplate) Filename() string {
+	return t.filename
+}
+
+// Content returns the template content
+func (t *PgxTemplate) Content() string {
+	return pgxTmpl + predsBldrFuncs("px", "PgxRepository", "%q", `"QIDENT ILIKE ? ESCAPE '\\'"`) +
+		likeFuncs("px", "PgxRepository", "%q", `"QIDENT LIKE ? ESCAPE '\\'"`, false) +
+		textSearchFuncs("px", "PgxRepository", true) +
+		lockFuncs("px", "PgxRepository", "nero.ForUpdate, nero.ForNoKeyUpdate, nero.ForShare")
+}
+
+const pgxTmpl = `
+// Code generated by nero, DO NOT EDIT.
+package {{.Pkg}}
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"log"
+	"os"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/sort"
+	{{range $import := .SchemaImports -}}
+		"{{$import}}"
+	{{end -}}
+	{{range $import := .ColumnImports -}}
+		"{{$import}}"
+	{{end -}}
+)
+
+// PgxRepository implements the Repository interface
+type PgxRepository struct {
+	pool  *pgxpool.Pool
+	logger nero.Logger
+	debug bool
+}
+
+var _ Repository = (*PgxRepository)(nil)
+
+// NewPgxRepository is a factory for PgxRepository
+func NewPgxRepository(pool *pgxpool.Pool) *PgxRepository {
+	return &PgxRepository{
+		pool: pool,
+	}
+}
+
+// Debug enables debug mode
+func (px *PgxRepository) Debug() *PgxRepository {
+	return &PgxRepository{
+		pool:  px.pool,
+		debug: true,
+		logger: log.New(os.Stdout, "nero: ", 0),
+	}
+}
+
+// WithLogger overrides the default logger
+func (px *PgxRepository) WithLogger(logger nero.Logger) *PgxRepository {
+	px.logger = logger
+	return px
+}
+
+// PgxTx is a pgx transaction
+type PgxTx struct {
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+var _ nero.Tx = (*PgxTx)(nil)
+
+// Commit commits the transaction
+func (t *PgxTx) Commit() error {
+	return t.tx.Commit(t.ctx)
+}
+
+// Rollback rolls back the transaction
+func (t *PgxTx) Rollback() error {
+	return t.tx.Rollback(t.ctx)
+}
+
+// pgxRunner is implemented by both *pgxpool.Pool and pgx.Tx
+type pgxRunner interface {
+	Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...interface{}) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...interface{}) pgx.Row
+	SendBatch(context.Context, *pgx.Batch) pgx.BatchResults
+}
+
+// pgxString scans a column into a string identity, pgx can't
+// scan an integer into a string e.g. a bigserial identity
+type pgxString struct {
+	s *string
+}
+
+// Scan implements sql.Scanner
+func (ps pgxString) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		*ps.s = v
+	case []byte:
+		*ps.s = string(v)
+	case int64:
+		*ps.s = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("cannot scan %T into a string identity", src)
+	}
+
+	return nil
+}
+
+// pgxNumber scans an aggregate into a numeric field, pgx can't scan
+// e.g. an integer into a float or a numeric into an integer
+type pgxNumber struct {
+	v reflect.Value
+}
+
+// Scan implements sql.Scanner
+func (pn pgxNumber) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into %s", src, pn.v.Type())
+	}
+
+	switch pn.v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, pn.v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		pn.v.SetFloat(f)
+		return nil
+	}
+
+	// the numerics are encoded with an exponent e.g. "12e2"
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	if err != nil {
+		return err
+	}
+	if !f.IsInt() {
+		return fmt.Errorf("cannot scan %s into %s", s, pn.v.Type())
+	}
+
+	switch pn.v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, acc := f.Int64()
+		if acc != big.Exact || pn.v.OverflowInt(i) {
+			return fmt.Errorf("cannot scan %s into %s", s, pn.v.Type())
+		}
+		pn.v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, acc := f.Uint64()
+		if acc != big.Exact || pn.v.OverflowUint(u) {
+			return fmt.Errorf("cannot scan %s into %s", s, pn.v.Type())
+		}
+		pn.v.SetUint(u)
+	}
+
+	return nil
+}
+
+// isPgxNumber returns true if the field is scanned with pgxNumber
+func isPgxNumber(v reflect.Value) bool {
+	if _, ok := v.Addr().Interface().(sql.Scanner); ok {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// Tx creates begins a new transaction
+func (px *PgxRepository) Tx(ctx context.Context) (nero.Tx, error) {
+	tx, err := px.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PgxTx{ctx: ctx, tx: tx}, nil
+}
+
+// Create creates a new {{.Type.Name}}
+func (px *PgxRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
+	return px.create(ctx, px.pool, c)
+}
+
+// CreateTx creates a new {{.Type.Name}} inside a transaction
+func (px *PgxRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.create(ctx, txx.tx, c)
+}
+
+func (px *PgxRepository) create(ctx context.Context, runner pgxRunner, c *Creator) ({{identType $}}, error) {
+	columns, values := px.createValues(c)
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING {{range $i, $col := .Idents}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").
+		PlaceholderFormat(squirrel.Dollar)
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	{{if .HasCompositeIdent -}}
+		var ident Ident
+		err = runner.QueryRow(ctx, stmt, args...).Scan(
+			{{range $col := .Idents -}}
+				{{if $col.IsStringIdent}}pgxString{(*string)(&ident.{{$col.Field}})}{{else}}&ident.{{$col.Field}}{{end}},
+			{{end -}}
+		)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return ident, nil
+	{{else -}}
+		var {{.Ident.Identifier}} {{identType $}}
+		err = runner.QueryRow(ctx, stmt, args...).Scan({{if $.Ident.IsStringIdent}}pgxString{(*string)(&{{.Ident.Identifier}})}{{else}}&{{.Ident.Identifier}}{{end}})
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return {{.Ident.Identifier}}, nil
+	{{end -}}
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (px *PgxRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	return px.createReturning(ctx, px.pool, c)
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (px *PgxRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.createReturning(ctx, txx.tx, c)
+}
+
+func (px *PgxRepository) createReturning(ctx context.Context, runner pgxRunner, c *Creator) (*{{type .Type.V}}, error) {
+	columns, values := px.createValues(c)
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING {{range $i, $col := .Cols}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").
+		PlaceholderFormat(squirrel.Dollar)
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: CreateReturning, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return px.scanColumns(runner.QueryRow(ctx, stmt, args...), allColumns())
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (px *PgxRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	return px.createMany(ctx, px.pool, cs...)
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (px *PgxRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.createMany(ctx, txx.tx, cs...)
+}
+
+func (px *PgxRepository) createMany(ctx context.Context, runner pgxRunner, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	// the inserts are sent in a single round-trip and are
+	// executed in an implicit transaction if runner is not a tx
+	batch := &pgx.Batch{}
+	for _, c := range cs {
+		columns, args := px.createValues(c)
+		stmt := px.insertStmt(columns, "RETURNING {{range $i, $col := .Idents}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}")
+		if px.debug {
+			px.logger.Printf("method: CreateMany, stmt: %q, args: %v, error: %v", stmt, args, nil)
+		}
+		batch.Queue(stmt, args...)
+	}
+
+	br := runner.SendBatch(ctx, batch)
+	idents := make([]{{identType $}}, 0, len(cs))
+	for range cs {
+		var ident {{identType $}}
+		err := br.QueryRow().Scan(
+			{{if .HasCompositeIdent -}}
+				{{range $col := .Idents -}}
+					{{if $col.IsStringIdent}}pgxString{(*string)(&ident.{{$col.Field}})}{{else}}&ident.{{$col.Field}}{{end}},
+				{{end -}}
+			{{else -}}
+				{{if $.Ident.IsStringIdent}}pgxString{(*string)(&ident)}{{else}}&ident{{end}},
+			{{end -}}
+		)
+		if err != nil {
+			br.Close()
+			return nil, err
+		}
+		idents = append(idents, ident)
+	}
+
+	return idents, br.Close()
+}
+
+// createValues returns the set columns and values of the creator
+func (px *PgxRepository) createValues(c *Creator) ([]string, []interface{}) {
+	columns := []string{}
+	values := []interface{}{}
+	{{range $col := .Cols }}
+		{{if ne $col.Auto true}}
+			if c.IsSet(Column{{$col.Field}}) {
+				columns = append(columns, "\"{{$col.Name}}\"")
+				{{if $col.Nullable -}}
+					if c.IsNull(Column{{$col.Field}}) {
+						values = append(values, nil)
+					} else {
+				{{end -}}
+				values = append(values, c.{{$col.Identifier}})
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
+			}
+		{{end}}
+	{{end}}
+
+	return columns, values
+}
+
+// insertStmt builds a single-row insert of the columns
+func (px *PgxRepository) insertStmt(columns []string, suffix string) string {
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return fmt.Sprintf("INSERT INTO \"{{.Collection}}\" (%s) VALUES (%s) %s",
+		strings.Join(columns, ","), strings.Join(placeholders, ","), suffix)
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (px *PgxRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	return px.upsert(ctx, px.pool, u)
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (px *PgxRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.upsert(ctx, txx.tx, u)
+}
+
+func (px *PgxRepository) upsert(ctx context.Context, runner pgxRunner, u *Upserter) ({{identType $}}, error) {
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	columns, values := px.createValues(u.Creators()[0])
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix(px.onConflict(u) + " RETURNING {{range $i, $col := .Idents}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").
+		PlaceholderFormat(squirrel.Dollar)
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	var ident {{identType $}}
+	err = runner.QueryRow(ctx, stmt, args...).Scan(
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				{{if $col.IsStringIdent}}pgxString{(*string)(&ident.{{$col.Field}})}{{else}}&ident.{{$col.Field}}{{end}},
+			{{end -}}
+		{{else -}}
+			{{if $.Ident.IsStringIdent}}pgxString{(*string)(&ident)}{{else}}&ident{{end}},
+		{{end -}}
+	)
+	if err == pgx.ErrNoRows {
+		// nothing is returned if the conflicting row was left as it is
+		return px.conflictIdent(ctx, runner, u, columns, values)
+	}
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return ident, nil
+}
+
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (px *PgxRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	return px.upsertMany(ctx, px.pool, u)
+}
+
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (px *PgxRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.upsertMany(ctx, txx.tx, u)
+}
+
+func (px *PgxRepository) upsertMany(ctx context.Context, runner pgxRunner, u *Upserter) error {
+	cs := u.Creators()
+	if len(cs) == 0 {
+		return nil
+	}
+
+	if err := u.validate(false); err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, c := range cs {
+		columns, args := px.createValues(c)
+		stmt := px.insertStmt(columns, px.onConflict(u))
+		if px.debug {
+			px.logger.Printf("method: UpsertMany, stmt: %q, args: %v, error: %v", stmt, args, nil)
+		}
+		batch.Queue(stmt, args...)
+	}
+
+	br := runner.SendBatch(ctx, batch)
+	for range cs {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return err
+		}
+	}
+
+	return br.Close()
+}
+
+// onConflict builds the 'ON CONFLICT' clause of the upsert
+func (px *PgxRepository) onConflict(u *Upserter) string {
+	target := []string{}
+	for _, col := range u.ConflictColumns() {
+		target = append(target, fmt.Sprintf("%q", col))
+	}
+
+	updates := []string{}
+	for _, col := range u.UpdateColumns() {
+		updates = append(updates, fmt.Sprintf("%q = EXCLUDED.%q", col, col))
+	}
+
+	clause := "ON CONFLICT (" + strings.Join(target, ", ") + ")"
+	if len(updates) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	return clause + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// conflictIdent looks-up the identity of the conflicting row
+func (px *PgxRepository) conflictIdent(ctx context.Context, runner pgxRunner, u *Upserter, columns []string, values []interface{}) ({{identType $}}, error) {
+	eq := squirrel.Eq{}
+	for _, col := range u.ConflictColumns() {
+		name := fmt.Sprintf("%q", col)
+		for i, column := range columns {
+			if column == name {
+				eq[name] = values[i]
+			}
+		}
+	}
+
+	stmt, args, err := squirrel.Select({{range $i, $col := .Idents}}{{if $i}}, {{end}}"\"{{$col.Name}}\""{{end}}).
+		From("\"{{.Collection}}\"").
+		Where(eq).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if px.debug {
+		px.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	var ident {{identType $}}
+	err = runner.QueryRow(ctx, stmt, args...).Scan(
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				{{if $col.IsStringIdent}}pgxString{(*string)(&ident.{{$col.Field}})}{{else}}&ident.{{$col.Field}}{{end}},
+			{{end -}}
+		{{else -}}
+			{{if $.Ident.IsStringIdent}}pgxString{(*string)(&ident)}{{else}}&ident{{end}},
+		{{end -}}
+	)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return ident, nil
+}
+
+// Query queries many {{.Type.Name}}
+func (px *PgxRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return px.query(ctx, px.pool, q)
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (px *PgxRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.query(ctx, txx.tx, q)
+}
+
+func (px *PgxRepository) query(ctx context.Context, runner pgxRunner, q *Queryer) ([]*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, args, err := px.buildSelect(q).ToSql()
+	if px.debug {
+		px.logger.Printf("method: Query, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runner.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := px.scanColumns(rows, q.columns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+	{{if .Edges}}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// release the connection before loading the edges using the same runner
+	rows.Close()
+
+	err = px.loadEdges(ctx, runner, q, {{plural (lowerCamel .Type.Name)}})
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	if q.reversed {
+		reverseRows({{plural (lowerCamel .Type.Name)}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, rows.Err()
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (px *PgxRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return px.queryOne(ctx, px.pool, q)
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (px *PgxRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.queryOne(ctx, txx.tx, q)
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (px *PgxRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := px.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (px *PgxRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := px.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (px *PgxRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	if q.lock != nero.NoLock {
+		return 0, nero.ErrLockOutsideTx
+	}
+
+	return px.count(ctx, px.pool, q)
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (px *PgxRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.count(ctx, txx.tx, q)
+}
+
+func (px *PgxRepository) count(ctx context.Context, runner pgxRunner, q *Queryer) (int64, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return 0, &nero.UnsupportedError{Op: "row locking in Count", Repository: "PgxRepository"}
+	}
+
+	qb := squirrel.Select("COUNT(*)").
+		From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range px.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Count, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = runner.QueryRow(ctx, stmt, args...).Scan(&count)
+	return count, err
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (px *PgxRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	if q.lock != nero.NoLock {
+		return false, nero.ErrLockOutsideTx
+	}
+
+	return px.exists(ctx, px.pool, q)
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (px *PgxRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return false, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.exists(ctx, txx.tx, q)
+}
+
+func (px *PgxRepository) exists(ctx context.Context, runner pgxRunner, q *Queryer) (bool, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return false, &nero.UnsupportedError{Op: "row locking in Exists", Repository: "PgxRepository"}
+	}
+
+	sub := squirrel.Select("1").From("\"{{.Collection}}\"")
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range px.conds(pb.All()) {
+		sub = sub.Where(cond)
+	}
+	qb := squirrel.Select().
+		Column(squirrel.Expr("EXISTS(?)", sub)).
+		PlaceholderFormat(squirrel.Dollar)
+
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Exists, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = runner.QueryRow(ctx, stmt, args...).Scan(&exists)
+	return exists, err
+}
+
+func (px *PgxRepository) queryOne(ctx context.Context, runner pgxRunner, q *Queryer) (*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, args, err := px.buildSelect(q).ToSql()
+	if px.debug {
+		px.logger.Printf("method: QueryOne, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	{{lowerCamel .Type.Name}}, err := px.scanColumns(runner.QueryRow(ctx, stmt, args...), q.columns())
+	if err != nil {
+		// keep the same error as the database/sql based repositories
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	{{if .Edges}}
+	err = px.loadEdges(ctx, runner, q, []*{{type .Type.V}}{ {{lowerCamel .Type.Name}} })
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	return {{lowerCamel .Type.Name}}, nil
+}
+
+// scanColumns scans a row of the columns into {{.Type.Name}}
+func (px *PgxRepository) scanColumns(row pgx.Row, cols []Column) (*{{type .Type.V}}, error) {
+	var {{lowerCamel .Type.Name}} {{type .Type.V}}
+	dest := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		switch col {
+		{{range $col := .Cols -}}
+		case Column{{$col.Field}}:
+			dest = append(dest, {{if $col.IsStringIdent}}pgxString{(*string)(&{{lowerCamel $.Type.Name}}.{{$col.Field}})}{{else}}&{{lowerCamel $.Type.Name}}.{{$col.Field}}{{end}})
+		{{end -}}
+		}
+	}
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &{{lowerCamel .Type.Name}}, nil
+}
+
+// scanAll scans the rows into {{.Type.Name}} and closes them
+func (px *PgxRepository) scanAll(rows pgx.Rows) ([]*{{type .Type.V}}, error) {
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := px.scanColumns(rows, allColumns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, rows.Err()
+}
+{{if .Edges}}
+// loadEdges eager-loads the edges of the rows
+func (px *PgxRepository) loadEdges(ctx context.Context, runner pgxRunner, q *Queryer, rows []*{{type .Type.V}}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	{{range $edge := .Edges}}
+	if q.with{{$edge.StructField}} {
+		related, err := px.load{{$edge.StructField}}(ctx, runner, edge{{$edge.StructField}}Keys(rows))
+		if err != nil {
+			return errors.Wrap(err, "load {{$edge.Name}}")
+		}
+		setEdge{{$edge.StructField}}(rows, related)
+	}
+	{{end}}
+	return nil
+}
+{{range $edge := .Edges}}
+{{if $edge.IsManyToMany -}}
+// load{{$edge.StructField}} queries the associated rows of the "{{$edge.Name}}" edge by key
+func (px *PgxRepository) load{{$edge.StructField}}(ctx context.Context, runner pgxRunner, keys []{{type $edge.Col.Type.V}}) (map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	stmt, args, err := squirrel.Select(
+		"\"{{$edge.Join}}\".\"{{$edge.JoinCol}}\"",
+		{{range $col := $edge.Ref.Cols -}}
+			"\"{{$edge.Ref.Collection}}\".\"{{$col.Name}}\"",
+		{{end -}}
+	).
+		From("\"{{$edge.Ref.Collection}}\"").
+		Join("\"{{$edge.Join}}\" ON \"{{$edge.Join}}\".\"{{$edge.JoinRefCol}}\" = \"{{$edge.Ref.Collection}}\".\"{{$edge.RefCol.Name}}\"").
+		Where(squirrel.Eq{"\"{{$edge.Join}}\".\"{{$edge.JoinCol}}\"": args}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if px.debug {
+		px.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runner.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var key {{type $edge.Col.Type.V}}
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			{{if $edge.Col.IsStringIdent}}pgxString{(*string)(&key)}{{else}}&key{{end}},
+			{{range $col := $edge.Ref.Cols -}}
+				{{if $col.IsStringIdent}}pgxString{(*string)(&item.{{$col.Field}})}{{else}}&item.{{$col.Field}}{{end}},
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related[key] = append(related[key], &item)
+	}
+
+	return related, rows.Err()
+}
+{{- else -}}
+// load{{$edge.StructField}} queries the related rows of the "{{$edge.Name}}" edge
+func (px *PgxRepository) load{{$edge.StructField}}(ctx context.Context, runner pgxRunner, keys []{{type $edge.Col.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	stmt, args, err := squirrel.Select(
+		{{range $col := $edge.Ref.Cols -}}
+			"\"{{$col.Name}}\"",
+		{{end -}}
+	).
+		From("\"{{$edge.Ref.Collection}}\"").
+		Where(squirrel.Eq{"\"{{$edge.RefCol.Name}}\"": args}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if px.debug {
+		px.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runner.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			{{range $col := $edge.Ref.Cols -}}
+				{{if $col.IsStringIdent}}pgxString{(*string)(&item.{{$col.Field}})}{{else}}&item.{{$col.Field}}{{end}},
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related = append(related, &item)
+	}
+
+	return related, rows.Err()
+}
+{{- end}}
+{{end}}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (px *PgxRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return px.add{{$edge.StructField}}(ctx, px.pool, id, refIDs...)
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (px *PgxRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.add{{$edge.StructField}}(ctx, txx.tx, id, refIDs...)
+}
+
+// add{{$edge.StructField}} inserts the join rows directly, the join collection
+// has no schema so there's no Creator for it
+func (px *PgxRepository) add{{$edge.StructField}}(ctx context.Context, runner pgxRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	qb := squirrel.Insert("\"{{$edge.Join}}\"").
+		Columns("\"{{$edge.JoinCol}}\"", "\"{{$edge.JoinRefCol}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, refID := range refIDs {
+		qb = qb.Values(id, refID)
+	}
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Add{{$edge.StructField}}, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = runner.Exec(ctx, stmt, args...)
+	return err
+}
+
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (px *PgxRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return px.remove{{$edge.StructField}}(ctx, px.pool, id, refIDs...)
+}
+
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (px *PgxRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.remove{{$edge.StructField}}(ctx, txx.tx, id, refIDs...)
+}
+
+// remove{{$edge.StructField}} deletes the join rows directly since there's no
+// Deleter for the join collection, the delete is always filtered by the identity
+// and is skipped without refIDs, so unlike an unguarded Deleter it can't remove
+// the associations of the other rows
+func (px *PgxRepository) remove{{$edge.StructField}}(ctx context.Context, runner pgxRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	refArgs := make([]interface{}, 0, len(refIDs))
+	for _, refID := range refIDs {
+		refArgs = append(refArgs, refID)
+	}
+
+	qb := squirrel.Delete("\"{{$edge.Join}}\"").
+		Where(squirrel.Eq{"\"{{$edge.JoinCol}}\"": id, "\"{{$edge.JoinRefCol}}\"": refArgs}).
+		PlaceholderFormat(squirrel.Dollar)
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Remove{{$edge.StructField}}, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = runner.Exec(ctx, stmt, args...)
+	return err
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (px *PgxRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	tx, err := px.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	txx := &PgxTx{ctx: ctx, tx: tx}
+	err = px.set{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	if err != nil {
+		return rollback(txx, err)
+	}
+
+	return txx.Commit()
+}
+
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (px *PgxRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.set{{$edge.StructField}}(ctx, txx.tx, id, refIDs...)
+}
+
+// set{{$edge.StructField}} replaces the join rows of the identity, the delete
+// is filtered by the identity so only its associations are removed
+func (px *PgxRepository) set{{$edge.StructField}}(ctx context.Context, runner pgxRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	qb := squirrel.Delete("\"{{$edge.Join}}\"").
+		Where(squirrel.Eq{"\"{{$edge.JoinCol}}\"": id}).
+		PlaceholderFormat(squirrel.Dollar)
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Set{{$edge.StructField}}, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = runner.Exec(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+
+	return px.add{{$edge.StructField}}(ctx, runner, id, refIDs...)
+}
+{{end}}
+{{end -}}
+
+func (px *PgxRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
+	columns := []string{}
+	for _, col := range q.columns() {
+		columns = append(columns, fmt.Sprintf("%q", col.String()))
+	}
+	qb := squirrel.Select(columns...).
+		From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+
+	pfs := q.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range px.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	sfs := q.sfs
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(px.rank(s))
+			continue
+		}
+		col := fmt.Sprintf("%q", s.Col)
+		switch s.Direction {
+		case sort.Asc:
+			qb = qb.OrderBy(col + " ASC")
+		case sort.Desc:
+			qb = qb.OrderBy(col + " DESC")
+		}
+	}
+
+	if q.limit > 0 {
+		qb = qb.Limit(uint64(q.limit))
+	}
+
+	if q.offset > 0 {
+		qb = qb.Offset(uint64(q.offset))
+	}
+
+	if q.lock != nero.NoLock {
+		qb = qb.SuffixExpr(px.lock(q))
+	}
+
+	return qb
+}
+
+// Update updates {{.Type.Name}}
+func (px *PgxRepository) Update(ctx context.Context, u *Updater) (int64, error) {
+	return px.update(ctx, px.pool, u)
+}
+
+// UpdateTx updates {{.Type.Name}} inside a transaction
+func (px *PgxRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updater) (int64, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.update(ctx, txx.tx, u)
+}
+
+func (px *PgxRepository) update(ctx context.Context, runner pgxRunner, u *Updater) (int64, error) {
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+
+	stmt, args, err := px.buildUpdate(u).ToSql()
+	if px.debug {
+		px.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := runner.Exec(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (px *PgxRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	return px.updateReturning(ctx, px.pool, u)
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (px *PgxRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.updateReturning(ctx, txx.tx, u)
+}
+
+func (px *PgxRepository) updateReturning(ctx context.Context, runner pgxRunner, u *Updater) ([]*{{type .Type.V}}, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	stmt, args, err := px.buildUpdate(u).Suffix("RETURNING {{range $i, $col := .Cols}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").ToSql()
+	if px.debug {
+		px.logger.Printf("method: UpdateReturning, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runner.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return px.scanAll(rows)
+}
+
+// buildUpdate builds the update statement
+func (px *PgxRepository) buildUpdate(u *Updater) squirrel.UpdateBuilder {
+	qb := squirrel.Update("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+	{{range $col := .Cols}}
+		{{if ne $col.Auto true}}
+			if u.IsSet(Column{{$col.Field}}) {
+				{{if $col.Nullable -}}
+					if u.IsNull(Column{{$col.Field}}) {
+						qb = qb.Set("\"{{$col.Name}}\"", nil)
+					} else {
+				{{end -}}
+				qb = qb.Set("\"{{$col.Name}}\"", u.{{$col.Identifier}})
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
+			}
+		{{end}}
+	{{end}}
+
+	for _, e := range u.exprs {
+		qb = qb.Set(fmt.Sprintf("%q", e.col.String()), px.updateExpr(e))
+	}
+
+	pfs := u.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range px.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
+// updateExpr builds the value of an update expression
+func (px *PgxRepository) updateExpr(e *updateExpr) squirrel.Sqlizer {
+	switch e.op {
+	case updateIncr:
+		return squirrel.Expr(fmt.Sprintf("%q + ?", e.col.String()), e.arg)
+	case updateDecr:
+		return squirrel.Expr(fmt.Sprintf("%q - ?", e.col.String()), e.arg)
+	}
+	return squirrel.Expr(fmt.Sprintf("%q", e.arg.(Column).String()))
+}
+
+// Delete deletes {{.Type.Name}}
+func (px *PgxRepository) Delete(ctx context.Context, d *Deleter) (int64, error) {
+	return px.delete(ctx, px.pool, d)
+}
+
+// Delete deletes {{.Type.Name}} inside a transaction
+func (px *PgxRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Deleter) (int64, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.delete(ctx, txx.tx, d)
+}
+
+func (px *PgxRepository) delete(ctx context.Context, runner pgxRunner, d *Deleter) (int64, error) {
+	if err := d.Guard(); err != nil {
+		return 0, err
+	}
+
+	stmt, args, err := px.buildDelete(d).ToSql()
+	if px.debug {
+		px.logger.Printf("method: Delete, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := runner.Exec(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (px *PgxRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	return px.deleteReturning(ctx, px.pool, d)
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (px *PgxRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.deleteReturning(ctx, txx.tx, d)
+}
+
+func (px *PgxRepository) deleteReturning(ctx context.Context, runner pgxRunner, d *Deleter) ([]*{{type .Type.V}}, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	stmt, args, err := px.buildDelete(d).Suffix("RETURNING {{range $i, $col := .Cols}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").ToSql()
+	if px.debug {
+		px.logger.Printf("method: DeleteReturning, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := runner.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return px.scanAll(rows)
+}
+
+// buildDelete builds the delete statement
+func (px *PgxRepository) buildDelete(d *Deleter) squirrel.DeleteBuilder {
+	qb := squirrel.Delete("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+
+	pfs := d.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range px.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
+// Aggregate runs aggregate operations
+func (px *PgxRepository) Aggregate(ctx context.Context, a *Aggregator) error {
+	return px.aggregate(ctx, px.pool, a)
+}
+
+// Aggregate runs aggregate operations inside a transaction
+func (px *PgxRepository) AggregateTx(ctx context.Context, tx nero.Tx, a *Aggregator) error {
+	txx, ok := tx.(*PgxTx)
+	if !ok {
+		return errors.New("expecting tx to be *PgxTx")
+	}
+
+	return px.aggregate(ctx, txx.tx, a)
+}
+
+func (px *PgxRepository) aggregate(ctx context.Context, runner pgxRunner, a *Aggregator) error {
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+	cols := []string{}
+	for _, agg := range aggs.All() {
+		col := agg.Col
+		qcol := fmt.Sprintf("%q", col)
+		switch agg.Fn {
+		case aggregate.Avg:
+			cols = append(cols, "AVG("+qcol+") avg_"+col)
+		case aggregate.Count:
+			cols = append(cols, "COUNT("+qcol+") count_"+col)
+		case aggregate.Max:
+			cols = append(cols, "MAX("+qcol+") max_"+col)
+		case aggregate.Min:
+			cols = append(cols, "MIN("+qcol+") min_"+col)
+		case aggregate.Sum:
+			cols = append(cols, "SUM("+qcol+") sum_"+col)
+		case aggregate.None:
+			cols = append(cols, qcol)
+		}
+	}
+
+	qb := squirrel.Select(cols...).From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+
+	groups := []string{}
+	for _, group := range a.groups {
+		groups = append(groups, fmt.Sprintf("%q", group.String()))
+	}
+	qb = qb.GroupBy(groups...)
+
+	pfs := a.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range px.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	sfs := a.sfs
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(px.rank(s))
+			continue
+		}
+		col := fmt.Sprintf("%q", s.Col)
+		switch s.Direction {
+		case sort.Asc:
+			qb = qb.OrderBy(col + " ASC")
+		case sort.Desc:
+			qb = qb.OrderBy(col + " DESC")
+		}
+	}
+
+	stmt, args, err := qb.ToSql()
+	if px.debug {
+		px.logger.Printf("method: Aggregate, stmt: %q, args: %v, error: %v", stmt, args, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(a.v).Elem()
+	t := reflect.TypeOf(v.Interface()).Elem()
+	if t.NumField() != len(cols) {
+		return errors.New("aggregate columns and destination struct field count should match")
+	}
+
+	rows, err := runner.Query(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ve := reflect.New(t).Elem()
+		dest := make([]interface{}, ve.NumField())
+		for i := 0; i < ve.NumField(); i++ {
+			// the aggregates are converted to the numeric fields
+			// e.g. the numeric average or the integer minimum
+			if isPgxNumber(ve.Field(i)) {
+				dest[i] = pgxNumber{ve.Field(i)}
+				continue
+			}
+			dest[i] = ve.Field(i).Addr().Interface()
+		}
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.Append(v, ve))
+	}
+
+	return rows.Err()
+}
+`
@@ -0,0 +1,17 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPgxTemplate(t *testing.T) {
+	tmpl := NewPgxTemplate().WithFilename("px.go")
+
+	assert.Equal(t, "px.go", tmpl.Filename())
+
+	_, err := ParseTemplate(tmpl.Content())
+	require.NoError(t, err)
+}
@@ -1,6 +1,10 @@
 package template
 
-import "github.com/sf9v/nero"
+import (
+	"strings"
+
+	"github.com/sf9v/nero"
+)
 
 // PostgresTemplate is the template for generating a postgres repository
 type PostgresTemplate struct {
@@ -29,7 +33,10 @@ func (t *PostgresTemplate) Filename() string {
 
 // Content returns the template content
 func (t *PostgresTemplate) Content() string {
-	return postgresTmpl
+	return postgresTmpl + predsBldrFuncs("pg", "PostgresRepository", "%q", `"QIDENT ILIKE ? ESCAPE '\\'"`) +
+		likeFuncs("pg", "PostgresRepository", "%q", `"QIDENT LIKE ? ESCAPE '\\'"`, false) +
+		textSearchFuncs("pg", "PostgresRepository", true) +
+		lockFuncs("pg", "PostgresRepository", "nero.ForUpdate, nero.ForNoKeyUpdate, nero.ForShare")
 }
 
 const postgresTmpl = `
@@ -97,209 +104,1003 @@ func (pg *PostgresRepository) Tx(ctx context.Context) (nero.Tx, error) {
 }
 
 // Create creates a new {{.Type.Name}}
-func (pg *PostgresRepository) Create(ctx context.Context, c *Creator) ({{type .Ident.Type.V}}, error) {
+func (pg *PostgresRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
 	return pg.create(ctx, pg.db, c)
 }
 
 // CreateTx creates a new {{.Type.Name}} inside a transaction
-func (pg *PostgresRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{type .Ident.Type.V}}, error) {
+func (pg *PostgresRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
 	txx, ok := tx.(*sql.Tx)
 	if !ok {
-		return {{zero .Ident.Type.V}}, errors.New("expecting tx to be *sql.Tx")
+		return {{identZero $}}, errors.New("expecting tx to be *sql.Tx")
 	}
 
 	return pg.create(ctx, txx, c)
 }
 
-func (pg *PostgresRepository) create(ctx context.Context, runner nero.SQLRunner, c *Creator) ({{type .Ident.Type.V}}, error) {
+func (pg *PostgresRepository) create(ctx context.Context, runner nero.SQLRunner, c *Creator) ({{identType $}}, error) {
+	columns, values := pg.createValues(c)
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING {{range $i, $col := .Idents}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").
+		PlaceholderFormat(squirrel.Dollar).
+		RunWith(runner)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	{{if .HasCompositeIdent -}}
+		var ident Ident
+		err := qb.QueryRowContext(ctx).Scan(
+			{{range $col := .Idents -}}
+				&ident.{{$col.Field}},
+			{{end -}}
+		)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return ident, nil
+	{{else -}}
+		var {{.Ident.Identifier}} {{identType $}}
+		err := qb.QueryRowContext(ctx).Scan(&{{.Ident.Identifier}})
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return {{.Ident.Identifier}}, nil
+	{{end -}}
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (pg *PostgresRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	return pg.createReturning(ctx, pg.db, c)
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (pg *PostgresRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.createReturning(ctx, txx, c)
+}
+
+func (pg *PostgresRepository) createReturning(ctx context.Context, runner nero.SQLRunner, c *Creator) (*{{type .Type.V}}, error) {
+	columns, values := pg.createValues(c)
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING {{range $i, $col := .Cols}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").
+		PlaceholderFormat(squirrel.Dollar).
+		RunWith(runner)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: CreateReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	return pg.scanColumns(qb.QueryRowContext(ctx), allColumns())
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (pg *PostgresRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	{{if .HasAutoIdent -}}
+		if len(cs) <= 1 {
+			return pg.createMany(ctx, pg.db, cs...)
+		}
+
+		// the rows are inserted in a single transaction
+		// so that nothing is inserted if one of them fails
+	{{- else -}}
+		if len(pg.createManyChunks(cs)) <= 1 {
+			return pg.createMany(ctx, pg.db, cs...)
+		}
+
+		// the chunks are inserted in a single transaction
+		// so that nothing is inserted if one of them fails
+	{{- end}}
+	tx, err := pg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	idents, err := pg.createMany(ctx, tx, cs...)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return idents, nil
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (pg *PostgresRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.createMany(ctx, txx, cs...)
+}
+
+func (pg *PostgresRepository) createMany(ctx context.Context, runner nero.SQLRunner, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	idents := make([]{{identType $}}, 0, len(cs))
+	{{if .HasAutoIdent -}}
+		// the rows are inserted one by one since the order of the rows
+		// returned by a multiple-row insert is not guaranteed, so the
+		// returned identities can't be matched to the creators
+		for _, c := range cs {
+			ident, err := pg.create(ctx, runner, c)
+			if err != nil {
+				return nil, err
+			}
+			idents = append(idents, ident)
+		}
+	{{- else -}}
+		chunks := pg.createManyChunks(cs)
+		for i, chunk := range chunks {
+			err := pg.createChunk(ctx, runner, chunk)
+			if err != nil {
+				if len(chunks) > 1 {
+					return nil, errors.Wrapf(err, "chunk %d of %d", i+1, len(chunks))
+				}
+				return nil, err
+			}
+		}
+
+		for _, c := range cs {
+			{{if .HasCompositeIdent -}}
+				idents = append(idents, Ident{
+					{{range $col := .Idents -}}
+						{{$col.Field}}: c.{{$col.Identifier}},
+					{{end -}}
+				})
+			{{- else -}}
+				idents = append(idents, c.{{.Ident.Identifier}})
+			{{- end}}
+		}
+	{{- end}}
+
+	return idents, nil
+}
+{{if not .HasAutoIdent}}
+// createManyChunks splits the creators into the groups that set the same columns,
+// and the groups so that the inserts don't exceed the bind parameters limit
+func (pg *PostgresRepository) createManyChunks(cs []*Creator) [][]*Creator {
+	chunks := [][]*Creator{}
+	for _, group := range groupCreators(cs) {
+		// postgres allows at most 65535 bind parameters in a statement
+		size := len(group)
+		if n := len(group[0].Columns()); n > 0 {
+			size = 65535 / n
+		}
+
+		for len(group) > size {
+			chunks = append(chunks, group[:size:size])
+			group = group[size:]
+		}
+		chunks = append(chunks, group)
+	}
+
+	return chunks
+}
+
+// createChunk inserts the creators that set the same columns in a single statement
+func (pg *PostgresRepository) createChunk(ctx context.Context, runner nero.SQLRunner, cs []*Creator) error {
+	columns, _ := pg.createValues(cs[0])
+	qb := squirrel.Insert("\"{{.Collection}}\"").Columns(columns...)
+	for _, c := range cs {
+		_, values := pg.createValues(c)
+		qb = qb.Values(values...)
+	}
+
+	qb = qb.PlaceholderFormat(squirrel.Dollar)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: CreateMany, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+{{end}}
+// createValues returns the set columns and values of the creator
+func (pg *PostgresRepository) createValues(c *Creator) ([]string, []interface{}) {
 	columns := []string{}
 	values := []interface{}{}
 	{{range $col := .Cols }}
 		{{if ne $col.Auto true}}
-			if c.{{$col.Identifier}} != {{zero $col.Type.V}} {
+			if c.IsSet(Column{{$col.Field}}) {
 				columns = append(columns, "\"{{$col.Name}}\"")
+				{{if $col.Nullable -}}
+					if c.IsNull(Column{{$col.Field}}) {
+						values = append(values, nil)
+					} else {
+				{{end -}}
 				{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
 					values = append(values, pq.Array(c.{{$col.Identifier}}))
 				{{else -}}
 					values = append(values, c.{{$col.Identifier}})
 				{{end -}}
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
 			}
 		{{end}}
 	{{end}}
 
+	return columns, values
+}
+
+// BulkLoad creates many {{.Type.Name}} using the COPY protocol
+func (pg *PostgresRepository) BulkLoad(ctx context.Context, cs ...*Creator) error {
+	err := pg.validateBulkLoad(cs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := pg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = pg.bulkLoad(ctx, tx, cs...)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+// BulkLoadTx creates many {{.Type.Name}} using the COPY protocol inside a transaction
+func (pg *PostgresRepository) BulkLoadTx(ctx context.Context, tx nero.Tx, cs ...*Creator) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	err := pg.validateBulkLoad(cs)
+	if err != nil {
+		return err
+	}
+
+	return pg.bulkLoad(ctx, txx, cs...)
+}
+
+// validateBulkLoad checks that every creator sets a column since the
+// columns of the COPY statement are the columns set by the creators
+func (pg *PostgresRepository) validateBulkLoad(cs []*Creator) error {
+	for i, c := range cs {
+		if len(c.Columns()) == 0 {
+			return errors.Errorf("creator %d of %d doesn't set any column", i+1, len(cs))
+		}
+	}
+
+	return nil
+}
+
+func (pg *PostgresRepository) bulkLoad(ctx context.Context, tx *sql.Tx, cs ...*Creator) error {
+	// the creators that set the same columns are copied together,
+	// the columns that are not set get their default values
+	for _, group := range groupCreators(cs) {
+		err := pg.copyIn(ctx, tx, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyIn copies the creators that set the same columns
+func (pg *PostgresRepository) copyIn(ctx context.Context, tx *sql.Tx, cs []*Creator) error {
+	columns := []string{}
+	for _, col := range cs[0].Columns() {
+		columns = append(columns, col.String())
+	}
+
+	stmt := pq.CopyIn("{{.Collection}}", columns...)
+	if pg.debug {
+		pg.logger.Printf("method: BulkLoad, stmt: %q, rows: %d", stmt, len(cs))
+	}
+
+	copyStmt, err := tx.PrepareContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	defer copyStmt.Close()
+
+	for _, c := range cs {
+		_, values := pg.createValues(c)
+		_, err = copyStmt.ExecContext(ctx, values...)
+		if err != nil {
+			return err
+		}
+	}
+
+	// flush the buffered rows
+	_, err = copyStmt.ExecContext(ctx)
+	return err
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (pg *PostgresRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	return pg.upsert(ctx, pg.db, u)
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (pg *PostgresRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.upsert(ctx, txx, u)
+}
+
+func (pg *PostgresRepository) upsert(ctx context.Context, runner nero.SQLRunner, u *Upserter) ({{identType $}}, error) {
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	columns, values := pg.createValues(u.Creators()[0])
 	qb := squirrel.Insert("\"{{.Collection}}\"").
 		Columns(columns...).
 		Values(values...).
-		Suffix("RETURNING \"{{.Ident.Name}}\"").
+		Suffix(pg.onConflict(u) + " RETURNING {{range $i, $col := .Idents}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}").
 		PlaceholderFormat(squirrel.Dollar).
 		RunWith(runner)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", sql, args, err)
+		pg.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
-	var {{.Ident.Identifier}} {{type .Ident.Type.V}}
-	err := qb.QueryRowContext(ctx).Scan(&{{.Ident.Identifier}})
+	var ident {{identType $}}
+	err := qb.QueryRowContext(ctx).Scan(
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				&ident.{{$col.Field}},
+			{{end -}}
+		{{else -}}
+			&ident,
+		{{end -}}
+	)
+	if err == sql.ErrNoRows {
+		// nothing is returned if the conflicting row was left as it is
+		return pg.conflictIdent(ctx, runner, u, columns, values)
+	}
 	if err != nil {
-		return {{zero .Ident.Type.V}}, err
+		return {{identZero $}}, err
 	}
 
-	return {{.Ident.Identifier}}, nil
+	return ident, nil
 }
 
-// CreateMany creates many {{.Type.Name}}
-func (pg *PostgresRepository) CreateMany(ctx context.Context, cs ...*Creator) error {
-	return pg.createMany(ctx, pg.db, cs...)
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (pg *PostgresRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	return pg.upsertMany(ctx, pg.db, u)
 }
 
-// CreateManyTx creates many {{.Type.Name}} inside a transaction
-func (pg *PostgresRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) error {
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (pg *PostgresRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
 	txx, ok := tx.(*sql.Tx)
 	if !ok {
 		return errors.New("expecting tx to be *sql.Tx")
 	}
 
-	return pg.createMany(ctx, txx, cs...)
+	return pg.upsertMany(ctx, txx, u)
 }
 
-func (pg *PostgresRepository) createMany(ctx context.Context, runner nero.SQLRunner, cs ...*Creator) error {
-	if len(cs) == 0 {
+func (pg *PostgresRepository) upsertMany(ctx context.Context, runner nero.SQLRunner, u *Upserter) error {
+	if len(u.Creators()) == 0 {
 		return nil
 	}
 
-	columns := []string{
+	if err := u.validate(false); err != nil {
+		return err
+	}
+
+	columns, _ := pg.createValues(u.Creators()[0])
+	qb := squirrel.Insert("\"{{.Collection}}\"").Columns(columns...)
+	for _, c := range u.Creators() {
+		_, values := pg.createValues(c)
+		qb = qb.Values(values...)
+	}
+
+	qb = qb.Suffix(pg.onConflict(u)).
+		PlaceholderFormat(squirrel.Dollar)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: UpsertMany, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// onConflict builds the 'ON CONFLICT' clause of the upsert
+func (pg *PostgresRepository) onConflict(u *Upserter) string {
+	target := []string{}
+	for _, col := range u.ConflictColumns() {
+		target = append(target, fmt.Sprintf("%q", col))
+	}
+
+	updates := []string{}
+	for _, col := range u.UpdateColumns() {
+		updates = append(updates, fmt.Sprintf("%q = EXCLUDED.%q", col, col))
+	}
+
+	clause := "ON CONFLICT (" + strings.Join(target, ", ") + ")"
+	if len(updates) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	return clause + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// conflictIdent looks-up the identity of the conflicting row
+func (pg *PostgresRepository) conflictIdent(ctx context.Context, runner nero.SQLRunner, u *Upserter, columns []string, values []interface{}) ({{identType $}}, error) {
+	eq := squirrel.Eq{}
+	for _, col := range u.ConflictColumns() {
+		name := fmt.Sprintf("%q", col)
+		for i, column := range columns {
+			if column == name {
+				eq[name] = values[i]
+			}
+		}
+	}
+
+	qb := squirrel.Select({{range $i, $col := .Idents}}{{if $i}}, {{end}}"\"{{$col.Name}}\""{{end}}).
+		From("\"{{.Collection}}\"").
+		Where(eq).
+		PlaceholderFormat(squirrel.Dollar).
+		RunWith(runner)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var ident {{identType $}}
+	err := qb.QueryRowContext(ctx).Scan(
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				&ident.{{$col.Field}},
+			{{end -}}
+		{{else -}}
+			&ident,
+		{{end -}}
+	)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return ident, nil
+}
+
+// Query queries many {{.Type.Name}}
+func (pg *PostgresRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return pg.query(ctx, pg.db, q)
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (pg *PostgresRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.query(ctx, txx, q)
+}
+
+func (pg *PostgresRepository) query(ctx context.Context, runner nero.SQLRunner, q *Queryer) ([]*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := pg.buildSelect(q)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Query, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := pg.scanColumns(rows, q.columns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+	{{if .Edges}}
+	// close the rows before loading the edges using the same runner
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = pg.loadEdges(ctx, runner, q, {{plural (lowerCamel .Type.Name)}})
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	if q.reversed {
+		reverseRows({{plural (lowerCamel .Type.Name)}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (pg *PostgresRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return pg.queryOne(ctx, pg.db, q)
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (pg *PostgresRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.queryOne(ctx, txx, q)
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (pg *PostgresRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := pg.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (pg *PostgresRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := pg.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (pg *PostgresRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	if q.lock != nero.NoLock {
+		return 0, nero.ErrLockOutsideTx
+	}
+
+	return pg.count(ctx, pg.db, q)
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (pg *PostgresRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.count(ctx, txx, q)
+}
+
+func (pg *PostgresRepository) count(ctx context.Context, runner nero.SQLRunner, q *Queryer) (int64, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return 0, &nero.UnsupportedError{Op: "row locking in Count", Repository: "PostgresRepository"}
+	}
+
+	qb := squirrel.Select("COUNT(*)").
+		From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Count, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var count int64
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&count)
+	return count, err
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (pg *PostgresRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	if q.lock != nero.NoLock {
+		return false, nero.ErrLockOutsideTx
+	}
+
+	return pg.exists(ctx, pg.db, q)
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (pg *PostgresRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return false, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.exists(ctx, txx, q)
+}
+
+func (pg *PostgresRepository) exists(ctx context.Context, runner nero.SQLRunner, q *Queryer) (bool, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return false, &nero.UnsupportedError{Op: "row locking in Exists", Repository: "PostgresRepository"}
+	}
+
+	sub := squirrel.Select("1").From("\"{{.Collection}}\"")
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range pg.conds(pb.All()) {
+		sub = sub.Where(cond)
+	}
+	qb := squirrel.Select().
+		Column(squirrel.Expr("EXISTS(?)", sub)).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Exists, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var exists bool
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&exists)
+	return exists, err
+}
+
+func (pg *PostgresRepository) queryOne(ctx context.Context, runner nero.SQLRunner, q *Queryer) (*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := pg.buildSelect(q)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: QueryOne, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	{{lowerCamel .Type.Name}}, err := pg.scanColumns(qb.RunWith(runner).QueryRowContext(ctx), q.columns())
+	if err != nil {
+		return {{zero .Type.V}}, err
+	}
+	{{if .Edges}}
+	err = pg.loadEdges(ctx, runner, q, []*{{type .Type.V}}{ {{lowerCamel .Type.Name}} })
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	return {{lowerCamel .Type.Name}}, nil
+}
+
+// scanColumns scans a row of the columns into {{.Type.Name}}
+func (pg *PostgresRepository) scanColumns(scanner interface{ Scan(...interface{}) error }, cols []Column) (*{{type .Type.V}}, error) {
+	var {{lowerCamel .Type.Name}} {{type .Type.V}}
+	dest := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		switch col {
 		{{range $col := .Cols -}}
-			{{if ne $col.Auto true -}}
-				"\"{{$col.Name}}\"",
+		case Column{{$col.Field}}:
+			{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
+				dest = append(dest, pq.Array(&{{lowerCamel $.Type.Name}}.{{$col.Field}}))
+			{{else -}}
+				dest = append(dest, &{{lowerCamel $.Type.Name}}.{{$col.Field}})
 			{{end -}}
 		{{end -}}
+		}
 	}
-	qb := squirrel.Insert("\"{{.Collection}}\"").Columns(columns...)
-	for _, c := range cs {
-		qb = qb.Values(
-			{{range $col := .Cols -}}
-				{{if ne $col.Auto true -}}
-					{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
-						pq.Array(c.{{$col.Identifier}}),
-					{{else -}}
-						c.{{$col.Identifier}},
-					{{end -}}
+
+	err := scanner.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &{{lowerCamel .Type.Name}}, nil
+}
+
+// scanAll scans the rows into {{.Type.Name}} and closes them
+func (pg *PostgresRepository) scanAll(rows *sql.Rows) ([]*{{type .Type.V}}, error) {
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := pg.scanColumns(rows, allColumns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, rows.Err()
+}
+{{if .Edges}}
+// loadEdges eager-loads the edges of the rows
+func (pg *PostgresRepository) loadEdges(ctx context.Context, runner nero.SQLRunner, q *Queryer, rows []*{{type .Type.V}}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	{{range $edge := .Edges}}
+	if q.with{{$edge.StructField}} {
+		related, err := pg.load{{$edge.StructField}}(ctx, runner, edge{{$edge.StructField}}Keys(rows))
+		if err != nil {
+			return errors.Wrap(err, "load {{$edge.Name}}")
+		}
+		setEdge{{$edge.StructField}}(rows, related)
+	}
+	{{end}}
+	return nil
+}
+{{range $edge := .Edges}}
+{{if $edge.IsManyToMany -}}
+// load{{$edge.StructField}} queries the associated rows of the "{{$edge.Name}}" edge by key
+func (pg *PostgresRepository) load{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, keys []{{type $edge.Col.Type.V}}) (map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	qb := squirrel.Select(
+		"\"{{$edge.Join}}\".\"{{$edge.JoinCol}}\"",
+		{{range $col := $edge.Ref.Cols -}}
+			"\"{{$edge.Ref.Collection}}\".\"{{$col.Name}}\"",
+		{{end -}}
+	).
+		From("\"{{$edge.Ref.Collection}}\"").
+		Join("\"{{$edge.Join}}\" ON \"{{$edge.Join}}\".\"{{$edge.JoinRefCol}}\" = \"{{$edge.Ref.Collection}}\".\"{{$edge.RefCol.Name}}\"").
+		Where(squirrel.Eq{"\"{{$edge.Join}}\".\"{{$edge.JoinCol}}\"": args}).
+		PlaceholderFormat(squirrel.Dollar)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var key {{type $edge.Col.Type.V}}
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			&key,
+			{{range $col := $edge.Ref.Cols -}}
+				{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
+					pq.Array(&item.{{$col.Field}}),
+				{{else -}}
+					&item.{{$col.Field}},
+				{{end -}}
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related[key] = append(related[key], &item)
+	}
+
+	return related, nil
+}
+{{- else -}}
+// load{{$edge.StructField}} queries the related rows of the "{{$edge.Name}}" edge
+func (pg *PostgresRepository) load{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, keys []{{type $edge.Col.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	qb := squirrel.Select(
+		{{range $col := $edge.Ref.Cols -}}
+			"\"{{$col.Name}}\"",
+		{{end -}}
+	).
+		From("\"{{$edge.Ref.Collection}}\"").
+		Where(squirrel.Eq{"\"{{$edge.RefCol.Name}}\"": args}).
+		PlaceholderFormat(squirrel.Dollar)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			{{range $col := $edge.Ref.Cols -}}
+				{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
+					pq.Array(&item.{{$col.Field}}),
+				{{else -}}
+					&item.{{$col.Field}},
 				{{end -}}
 			{{end -}}
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		related = append(related, &item)
+	}
+
+	return related, nil
+}
+{{- end}}
+{{end}}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (pg *PostgresRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return pg.add{{$edge.StructField}}(ctx, pg.db, id, refIDs...)
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (pg *PostgresRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.add{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// add{{$edge.StructField}} inserts the join rows directly, the join collection
+// has no schema so there's no Creator for it
+func (pg *PostgresRepository) add{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
 	}
 
-	qb = qb.Suffix("RETURNING \"{{.Ident.Name}}\"").
+	qb := squirrel.Insert("\"{{$edge.Join}}\"").
+		Columns("\"{{$edge.JoinCol}}\"", "\"{{$edge.JoinRefCol}}\"").
 		PlaceholderFormat(squirrel.Dollar)
+	for _, refID := range refIDs {
+		qb = qb.Values(id, refID)
+	}
 	if pg.debug {
 		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: CreateMany, stmt: %q, args: %v, error: %v", sql, args, err)
+		pg.logger.Printf("method: Add{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
 	_, err := qb.RunWith(runner).ExecContext(ctx)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return err
 }
 
-// Query queries many {{.Type.Name}}
-func (pg *PostgresRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
-	return pg.query(ctx, pg.db, q)
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (pg *PostgresRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return pg.remove{{$edge.StructField}}(ctx, pg.db, id, refIDs...)
 }
 
-// QueryTx queries many {{.Type.Name}} inside a transaction
-func (pg *PostgresRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (pg *PostgresRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
 	txx, ok := tx.(*sql.Tx)
 	if !ok {
-		return nil, errors.New("expecting tx to be *sql.Tx")
+		return errors.New("expecting tx to be *sql.Tx")
 	}
 
-	return pg.query(ctx, txx, q)
+	return pg.remove{{$edge.StructField}}(ctx, txx, id, refIDs...)
 }
 
-func (pg *PostgresRepository) query(ctx context.Context, runner nero.SQLRunner, q *Queryer) ([]*{{type .Type.V}}, error) {
-	qb := pg.buildSelect(q)	
+// remove{{$edge.StructField}} deletes the join rows directly since there's no
+// Deleter for the join collection, the delete is always filtered by the identity
+// and is skipped without refIDs, so unlike an unguarded Deleter it can't remove
+// the associations of the other rows
+func (pg *PostgresRepository) remove{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	refArgs := make([]interface{}, 0, len(refIDs))
+	for _, refID := range refIDs {
+		refArgs = append(refArgs, refID)
+	}
+
+	qb := squirrel.Delete("\"{{$edge.Join}}\"").
+		Where(squirrel.Eq{"\"{{$edge.JoinCol}}\"": id, "\"{{$edge.JoinRefCol}}\"": refArgs}).
+		PlaceholderFormat(squirrel.Dollar)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: Query, stmt: %q, args: %v, error: %v", sql, args, err)
+		pg.logger.Printf("method: Remove{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
-	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (pg *PostgresRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	tx, err := pg.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
-	for rows.Next() {
-		var {{lowerCamel .Type.Name}} {{type .Type.V}}
-		err = rows.Scan(
-			{{range $col := .Cols -}}
-				{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
-					pq.Array(&{{lowerCamel $.Type.Name}}.{{$col.Field}}),
-				{{else -}}
-					&{{lowerCamel $.Type.Name}}.{{$col.Field}},
-				{{end -}}
-			{{end -}}
-		)
-		if err != nil {
-			return nil, err
-		}
 
-		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, &{{lowerCamel .Type.Name}})
+	err = pg.set{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	if err != nil {
+		return rollback(tx, err)
 	}
 
-	return {{plural (lowerCamel .Type.Name)}}, nil
-}
-
-// QueryOne queries one {{.Type.Name}}
-func (pg *PostgresRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
-	return pg.queryOne(ctx, pg.db, q)
+	return tx.Commit()
 }
 
-// QueryOneTx queries one {{.Type.Name}} inside a transaction
-func (pg *PostgresRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (pg *PostgresRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
 	txx, ok := tx.(*sql.Tx)
 	if !ok {
-		return nil, errors.New("expecting tx to be *sql.Tx")
+		return errors.New("expecting tx to be *sql.Tx")
 	}
 
-	return pg.queryOne(ctx, txx, q)
+	return pg.set{{$edge.StructField}}(ctx, txx, id, refIDs...)
 }
 
-func (pg *PostgresRepository) queryOne(ctx context.Context, runner nero.SQLRunner, q *Queryer) (*{{type .Type.V}}, error) {
-	qb := pg.buildSelect(q)
+// set{{$edge.StructField}} replaces the join rows of the identity, the delete
+// is filtered by the identity so only its associations are removed
+func (pg *PostgresRepository) set{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	qb := squirrel.Delete("\"{{$edge.Join}}\"").
+		Where(squirrel.Eq{"\"{{$edge.JoinCol}}\"": id}).
+		PlaceholderFormat(squirrel.Dollar)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: QueryOne, stmt: %q, args: %v, error: %v", sql, args, err)
+		pg.logger.Printf("method: Set{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
-	var {{lowerCamel .Type.Name}} {{type .Type.V}}
-	err := qb.RunWith(runner).
-		QueryRowContext(ctx).
-		Scan(
-			{{range $col := .Cols -}}
-				{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
-					pq.Array(&{{lowerCamel $.Type.Name}}.{{$col.Field}}),
-				{{else -}}
-					&{{lowerCamel $.Type.Name}}.{{$col.Field}},
-				{{end -}}	
-			{{end -}}
-		)
+	_, err := qb.RunWith(runner).ExecContext(ctx)
 	if err != nil {
-		return {{zero .Type.V}}, err
+		return err
 	}
 
-	return &{{lowerCamel .Type.Name}}, nil
+	return pg.add{{$edge.StructField}}(ctx, runner, id, refIDs...)
 }
+{{end}}
+{{end -}}
 
 func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
-	columns := []string{
-		{{range $col := .Cols -}}
-			"\"{{$col.Name}}\"",
-		{{end -}}
+	columns := []string{}
+	for _, col := range q.columns() {
+		columns = append(columns, fmt.Sprintf("%q", col.String()))
 	}
 	qb := squirrel.Select(columns...).
 		From("\"{{.Collection}}\"").
@@ -310,7 +1111,9 @@ func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
 	for _, pf := range pfs {
 		pf(pb)
 	}
-	` + predsBldrBlock + `
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
 
 	sfs := q.sfs
 	sorts := &sort.Sorts{}
@@ -318,6 +1121,10 @@ func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
 		sf(sorts)
 	}
 	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(pg.rank(s))
+			continue
+		}
 		col := fmt.Sprintf("%q", s.Col)
 		switch s.Direction {
 		case sort.Asc:
@@ -335,6 +1142,10 @@ func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
 		qb = qb.Offset(uint64(q.offset))
 	}
 
+	if q.lock != nero.NoLock {
+		qb = qb.SuffixExpr(pg.lock(q))
+	}
+
 	return qb
 }
 
@@ -354,43 +1165,112 @@ func (pg *PostgresRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updat
 }
 
 func (pg *PostgresRepository) update(ctx context.Context, runner nero.SQLRunner, u *Updater) (int64, error) {
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+
+	qb := pg.buildUpdate(u)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (pg *PostgresRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	return pg.updateReturning(ctx, pg.db, u)
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (pg *PostgresRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.updateReturning(ctx, txx, u)
+}
+
+func (pg *PostgresRepository) updateReturning(ctx context.Context, runner nero.SQLRunner, u *Updater) ([]*{{type .Type.V}}, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	qb := pg.buildUpdate(u).Suffix("RETURNING {{range $i, $col := .Cols}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}")
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: UpdateReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pg.scanAll(rows)
+}
+
+// buildUpdate builds the update statement
+func (pg *PostgresRepository) buildUpdate(u *Updater) squirrel.UpdateBuilder {
 	qb := squirrel.Update("\"{{.Collection}}\"").
 		PlaceholderFormat(squirrel.Dollar)	
 	{{range $col := .Cols}}
 		{{if ne $col.Auto true}}
-			if u.{{$col.Identifier}} != {{zero $col.Type.V}} {
+			if u.IsSet(Column{{$col.Field}}) {
+				{{if $col.Nullable -}}
+					if u.IsNull(Column{{$col.Field}}) {
+						qb = qb.Set("\"{{$col.Name}}\"", nil)
+					} else {
+				{{end -}}
 				{{if and ($col.IsArray) (ne $col.IsValueScanner true) -}}
 					qb = qb.Set("\"{{$col.Name}}\"", pq.Array(u.{{$col.Identifier}}))
 				{{else -}}
 					qb = qb.Set("\"{{$col.Name}}\"", u.{{$col.Identifier}})
 				{{end -}}
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
 			}
 		{{end}}
 	{{end}}
 
+	for _, e := range u.exprs {
+		qb = qb.Set(fmt.Sprintf("%q", e.col.String()), pg.updateExpr(e))
+	}
+
 	pfs := u.pfs
 	pb := &comparison.Predicates{}
 	for _, pf := range pfs {
 		pf(pb)
 	}
-	` + predsBldrBlock + `
-
-	if pg.debug {
-		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", sql, args, err)
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
 	}
 
-	res, err := qb.RunWith(runner).ExecContext(ctx)
-	if err != nil {
-		return 0, err
-	}
+	return qb
+}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return 0, err
+// updateExpr builds the value of an update expression
+func (pg *PostgresRepository) updateExpr(e *updateExpr) squirrel.Sqlizer {
+	switch e.op {
+	case updateIncr:
+		return squirrel.Expr(fmt.Sprintf("%q + ?", e.col.String()), e.arg)
+	case updateDecr:
+		return squirrel.Expr(fmt.Sprintf("%q - ?", e.col.String()), e.arg)
 	}
-
-	return rowsAffected, nil
+	return squirrel.Expr(fmt.Sprintf("%q", e.arg.(Column).String()))
 }
 
 // Delete deletes {{.Type.Name}}
@@ -409,16 +1289,11 @@ func (pg *PostgresRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Delet
 }
 
 func (pg *PostgresRepository) delete(ctx context.Context, runner nero.SQLRunner, d *Deleter) (int64, error) {
-	qb := squirrel.Delete("\"{{.Collection}}\"").
-		PlaceholderFormat(squirrel.Dollar)
-
-	pfs := d.pfs
-	pb := &comparison.Predicates{}
-	for _, pf := range pfs {
-		pf(pb)
+	if err := d.Guard(); err != nil {
+		return 0, err
 	}
-	` + predsBldrBlock + `
 
+	qb := pg.buildDelete(d)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
 		pg.logger.Printf("method: Delete, stmt: %q, args: %v, error: %v", sql, args, err)
@@ -437,6 +1312,57 @@ func (pg *PostgresRepository) delete(ctx context.Context, runner nero.SQLRunner,
 	return rowsAffected, nil
 }
 
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (pg *PostgresRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	return pg.deleteReturning(ctx, pg.db, d)
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (pg *PostgresRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.deleteReturning(ctx, txx, d)
+}
+
+func (pg *PostgresRepository) deleteReturning(ctx context.Context, runner nero.SQLRunner, d *Deleter) ([]*{{type .Type.V}}, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	qb := pg.buildDelete(d).Suffix("RETURNING {{range $i, $col := .Cols}}{{if $i}}, {{end}}\"{{$col.Name}}\"{{end}}")
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: DeleteReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pg.scanAll(rows)
+}
+
+// buildDelete builds the delete statement
+func (pg *PostgresRepository) buildDelete(d *Deleter) squirrel.DeleteBuilder {
+	qb := squirrel.Delete("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Dollar)
+
+	pfs := d.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
 // Aggregate runs aggregate operations
 func (pg *PostgresRepository) Aggregate(ctx context.Context, a *Aggregator) error {
 	return pg.aggregate(ctx, pg.db, a)
@@ -491,7 +1417,9 @@ func (pg *PostgresRepository) aggregate(ctx context.Context, runner nero.SQLRunn
 	for _, pf := range pfs {
 		pf(pb)
 	}
-	` + predsBldrBlock + `
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
 
 	sfs := a.sfs
 	sorts := &sort.Sorts{}
@@ -499,6 +1427,10 @@ func (pg *PostgresRepository) aggregate(ctx context.Context, runner nero.SQLRunn
 		sf(sorts)
 	}
 	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(pg.rank(s))
+			continue
+		}
 		col := fmt.Sprintf("%q", s.Col)
 		switch s.Direction {
 		case sort.Asc:
@@ -544,70 +1476,234 @@ func (pg *PostgresRepository) aggregate(ctx context.Context, runner nero.SQLRunn
 }
 `
 
-const predsBldrBlock = `
-	for _, p := range pb.All() {
-		switch p.Op {
-		case comparison.Eq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q = %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q = ?", p.Col), p.Arg)
-			}
-		case comparison.NotEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <> %q", p.Col, col.String()))
-			} else {	
-				qb = qb.Where(fmt.Sprintf("%q <> ?", p.Col), p.Arg)
-			}
-		case comparison.Gt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q > %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q > ?", p.Col), p.Arg)
-			}
-		case comparison.GtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q >= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q >= ?", p.Col), p.Arg)
-			}
-		case comparison.Lt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q < %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q < ?", p.Col), p.Arg)
-			}
-		case comparison.LtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <= ?", p.Col), p.Arg)
-			}
-		case comparison.IsNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NULL", p.Col))
-		case comparison.IsNotNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NOT NULL", p.Col))
-		case comparison.In, comparison.NotIn:
-			args := p.Arg.([]interface{})
-			if len(args) == 0 {
-				continue
-			}
-			qms := []string{}
-			for range args {
-				qms = append(qms, "?")
-			}
-			fmtStr := "%q IN (%s)"
-			if p.Op == comparison.NotIn {
-				fmtStr = "%q NOT IN (%s)"
+// predsBldrFuncs returns the methods that build the sql conditions of
+// the predicates for the repository, identFmt is the format of a quoted
+// identifier and ilikeFmt is the format of the case-insensitive like
+// condition, QIDENT in ilikeFmt is replaced by identFmt
+func predsBldrFuncs(recv, repo, identFmt, ilikeFmt string) string {
+	tmpl := strings.NewReplacer("ILIKE_FMT", ilikeFmt).Replace(predsBldrTmpl)
+	return strings.NewReplacer("RECV", recv, "REPO", repo,
+		"QIDENT", identFmt).Replace(tmpl)
+}
+
+// likeFuncs returns the function that builds the case-sensitive like
+// condition of the repository, likeFmt is the format of the condition
+// with the backslash as the escape character, QIDENT in likeFmt is
+// replaced by identFmt. If glob is set, the pattern is converted to a
+// glob pattern for the back-ends where like is case-insensitive
+func likeFuncs(recv, repo, identFmt, likeFmt string, glob bool) string {
+	pattern := "pattern"
+	if glob {
+		pattern = "comparison.GlobPattern(pattern)"
+	}
+	tmpl := strings.NewReplacer("LIKE_FMT", likeFmt, "PATTERN", pattern).Replace(likeTmpl)
+	return strings.NewReplacer("RECV", recv, "REPO", repo,
+		"QIDENT", identFmt).Replace(tmpl)
+}
+
+const likeTmpl = `
+// like builds the case-sensitive condition of the string matching predicate
+func (RECV *REPO) like(p *comparison.Predicate) squirrel.Sqlizer {
+	pattern := comparison.LikePattern(p.Op, p.Arg.(string))
+	cond := squirrel.Expr(fmt.Sprintf(LIKE_FMT, p.Col), PATTERN)
+	if p.Op == comparison.NotLike {
+		return squirrel.Expr("NOT (?)", cond)
+	}
+	return cond
+}
+`
+
+// textSearchFuncs returns the full-text search functions of the
+// repository, they return an error if the back-end doesn't support it
+func textSearchFuncs(recv, repo string, supported bool) string {
+	tmpl := unsupportedTextSearchTmpl
+	if supported {
+		tmpl = textSearchTmpl
+	}
+	return strings.NewReplacer("RECV", recv, "REPO", repo).Replace(tmpl)
+}
+
+const textSearchTmpl = `
+// matches builds the full-text search condition of the predicate
+func (RECV *REPO) matches(p *comparison.Predicate) squirrel.Sqlizer {
+	ts := p.Arg.(*comparison.TextSearch)
+	return squirrel.Expr(fmt.Sprintf("%s @@ %s", RECV.tsvector(p.Col, ts), RECV.tsquery(ts)), ts.Query)
+}
+
+// rank builds the order by clause of the full-text search rank
+func (RECV *REPO) rank(s *sort.Sort) squirrel.Sqlizer {
+	dir := "ASC"
+	if s.Direction == sort.Desc {
+		dir = "DESC"
+	}
+	return squirrel.Expr(fmt.Sprintf("ts_rank(%s, %s) %s", RECV.tsvector(s.Col, s.Rank),
+		RECV.tsquery(s.Rank), dir), s.Rank.Query)
+}
+
+// tsvector renders the text search vector of the column, the configuration
+// is inlined so that it can use an index on the same expression
+func (RECV *REPO) tsvector(col string, ts *comparison.TextSearch) string {
+	return fmt.Sprintf("to_tsvector('%s', %q)", strings.ReplaceAll(ts.Config, "'", "''"), col)
+}
+
+// tsquery renders the text search query
+func (RECV *REPO) tsquery(ts *comparison.TextSearch) string {
+	return fmt.Sprintf("websearch_to_tsquery('%s', ?)", strings.ReplaceAll(ts.Config, "'", "''"))
+}
+`
+
+const unsupportedTextSearchTmpl = `
+// matches fails the query since full-text search is not supported
+func (RECV *REPO) matches(p *comparison.Predicate) squirrel.Sqlizer {
+	return &nero.UnsupportedError{Op: "full-text search", Repository: "REPO"}
+}
+
+// rank fails the query since full-text search is not supported
+func (RECV *REPO) rank(s *sort.Sort) squirrel.Sqlizer {
+	return &nero.UnsupportedError{Op: "full-text search", Repository: "REPO"}
+}
+`
+
+// lockFuncs returns the row locking function of the repository, strengths
+// are the supported lock strengths, none means that locking is not supported
+func lockFuncs(recv, repo, strengths string) string {
+	tmpl := unsupportedLockTmpl
+	if strengths != "" {
+		tmpl = lockTmpl
+	}
+	return strings.NewReplacer("RECV", recv, "REPO", repo,
+		"STRENGTHS", strengths).Replace(tmpl)
+}
+
+const lockTmpl = `
+// lock renders the row locking clause of the query
+func (RECV *REPO) lock(q *Queryer) squirrel.Sqlizer {
+	switch q.lock {
+	case STRENGTHS:
+	default:
+		return &nero.UnsupportedError{Op: q.lock.String(), Repository: "REPO"}
+	}
+
+	clause := q.lock.String()
+	if q.wait != nero.Wait {
+		clause += " " + q.wait.String()
+	}
+	return squirrel.Expr(clause)
+}
+`
+
+const unsupportedLockTmpl = `
+// lock fails the query since row locking is not supported
+func (RECV *REPO) lock(q *Queryer) squirrel.Sqlizer {
+	return &nero.UnsupportedError{Op: "row locking", Repository: "REPO"}
+}
+`
+
+const predsBldrTmpl = `
+// conds builds the conditions of the predicates
+func (RECV *REPO) conds(preds []*comparison.Predicate) []squirrel.Sqlizer {
+	conds := []squirrel.Sqlizer{}
+	for _, p := range preds {
+		if cond := RECV.cond(p); cond != nil {
+			conds = append(conds, cond)
+		}
+	}
+	return conds
+}
+
+// cond builds the condition of the predicate, nil means no condition
+func (RECV *REPO) cond(p *comparison.Predicate) squirrel.Sqlizer {
+	switch p.Op {
+	case comparison.And, comparison.Not:
+		conds := RECV.conds(p.Group())
+		if p.Op == comparison.Not {
+			return squirrel.Expr("NOT ?", squirrel.And(conds))
+		}
+		if len(conds) == 0 {
+			return nil
+		}
+		return squirrel.And(conds)
+	case comparison.Or:
+		or := squirrel.Or{}
+		for _, pred := range p.Group() {
+			cond := RECV.cond(pred)
+			// a nil condition e.g. an empty "in" matches everything
+			if cond == nil {
+				return nil
 			}
-			plchldr := strings.Join(qms, ",")
-			qb = qb.Where(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
+			or = append(or, cond)
+		}
+		return or
+	case comparison.Eq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("QIDENT = QIDENT", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("QIDENT = ?", p.Col), p.Arg)
+	case comparison.NotEq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("QIDENT <> QIDENT", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("QIDENT <> ?", p.Col), p.Arg)
+	case comparison.Gt:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("QIDENT > QIDENT", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("QIDENT > ?", p.Col), p.Arg)
+	case comparison.GtOrEq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("QIDENT >= QIDENT", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("QIDENT >= ?", p.Col), p.Arg)
+	case comparison.Lt:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("QIDENT < QIDENT", p.Col, col.String()))
 		}
+		return squirrel.Expr(fmt.Sprintf("QIDENT < ?", p.Col), p.Arg)
+	case comparison.LtOrEq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("QIDENT <= QIDENT", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("QIDENT <= ?", p.Col), p.Arg)
+	case comparison.Like, comparison.NotLike, comparison.HasPrefix,
+		comparison.HasSuffix, comparison.Contains:
+		return RECV.like(p)
+	case comparison.ILike:
+		return squirrel.Expr(fmt.Sprintf(ILIKE_FMT, p.Col), p.Arg)
+	case comparison.Matches:
+		return RECV.matches(p)
+	case comparison.Between, comparison.NotBetween:
+		fmtStr := "QIDENT BETWEEN ? AND ?"
+		if p.Op == comparison.NotBetween {
+			fmtStr = "QIDENT NOT BETWEEN ? AND ?"
+		}
+		return squirrel.Expr(fmt.Sprintf(fmtStr, p.Col), p.Arg.([]interface{})...)
+	case comparison.IsNull:
+		return squirrel.Expr(fmt.Sprintf("QIDENT IS NULL", p.Col))
+	case comparison.IsNotNull:
+		return squirrel.Expr(fmt.Sprintf("QIDENT IS NOT NULL", p.Col))
+	case comparison.In, comparison.NotIn:
+		args := p.Arg.([]interface{})
+		if len(args) == 0 {
+			return nil
+		}
+		qms := []string{}
+		for range args {
+			qms = append(qms, "?")
+		}
+		fmtStr := "QIDENT IN (%s)"
+		if p.Op == comparison.NotIn {
+			fmtStr = "QIDENT NOT IN (%s)"
+		}
+		plchldr := strings.Join(qms, ",")
+		return squirrel.Expr(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
+	case comparison.Exists, comparison.NotExists:
+		edge := p.Arg.(*comparison.Edge)
+		fmtStr := "EXISTS (SELECT 1 FROM QIDENT AS QIDENT WHERE QIDENT.QIDENT = QIDENT.QIDENT)"
+		if p.Op == comparison.NotExists {
+			fmtStr = "NOT " + fmtStr
+		}
+		return squirrel.Expr(fmt.Sprintf(fmtStr, edge.Collection, "edge", "edge", edge.Col, "{{.Collection}}", p.Col))
 	}
+	return nil
+}
 `
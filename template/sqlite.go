@@ -0,0 +1,1490 @@
+package template
+
+import "github.com/sf9v/nero"
+
+// SQLiteTemplate is the template for generating a sqlite repository
+type SQLiteTemplate struct {
+	filename string
+}
+
+var _ nero.Templater = (*SQLiteTemplate)(nil)
+
+// NewSQLiteTemplate returns a new SQLiteTemplate
+func NewSQLiteTemplate() *SQLiteTemplate {
+	return &SQLiteTemplate{
+		filename: "sqlite.go",
+	}
+}
+
+// WithFilename overrides the default filename
+func (t *SQLiteTemplate) WithFilename(filename string) *SQLiteTemplate {
+	t.filename = filename
+	return t
+}
+
+// Filename returns the filename
+func (t *SQLiteTemplate) Filename() string {
+	return t.filename
+}
+
+// Content returns the template content
+func (t *SQLiteTemplate) Content() string {
+	return sqliteTmpl + predsBldrFuncs("sl", "SQLiteRepository", "%q", `"LOWER(QIDENT) LIKE LOWER(?) ESCAPE '\\'"`) +
+		likeFuncs("sl", "SQLiteRepository", "%q", `"QIDENT GLOB ?"`, true) +
+		textSearchFuncs("sl", "SQLiteRepository", false) +
+		lockFuncs("sl", "SQLiteRepository", "")
+}
+
+const sqliteTmpl = `
+// Code generated by nero, DO NOT EDIT.
+package {{.Pkg}}
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"io"
+	"strings"
+	"log"
+	"os"
+	"github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/sort"
+	{{range $import := .SchemaImports -}}
+		"{{$import}}"
+	{{end -}}
+	{{range $import := .ColumnImports -}}
+		"{{$import}}"
+	{{end -}}
+)
+
+// SQLiteRepository implements the Repository interface
+type SQLiteRepository struct {
+	db  *sql.DB
+	logger nero.Logger
+	debug bool
+}
+
+var _ Repository = (*SQLiteRepository)(nil)
+
+// NewSQLiteRepository is a factory for SQLiteRepository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{
+		db: db,
+	}
+}
+
+// Debug enables debug mode
+func (sl *SQLiteRepository) Debug() *SQLiteRepository {
+	return &SQLiteRepository{
+		db:  sl.db,
+		debug: true,
+		logger: log.New(os.Stdout, "nero: ", 0),
+	}
+}
+
+// WithLogger overrides the default logger
+func (sl *SQLiteRepository) WithLogger(logger nero.Logger) *SQLiteRepository {
+	sl.logger = logger
+	return sl
+}
+
+// Tx creates begins a new transaction
+func (sl *SQLiteRepository) Tx(ctx context.Context) (nero.Tx, error) {
+	return sl.db.BeginTx(ctx, nil)
+}
+
+// Create creates a new {{.Type.Name}}
+func (sl *SQLiteRepository) Create(ctx context.Context, c *Creator) ({{identType $}}, error) {
+	return sl.create(ctx, sl.db, c)
+}
+
+// CreateTx creates a new {{.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creator) ({{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.create(ctx, txx, c)
+}
+
+func (sl *SQLiteRepository) create(ctx context.Context, runner nero.SQLRunner, c *Creator) ({{identType $}}, error) {
+	columns, values := sl.createValues(c)
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	{{if .HasCompositeIdent -}}
+		{{if .HasAutoIdent -}}
+			res, err := qb.ExecContext(ctx)
+			if err != nil {
+				return {{identZero $}}, err
+			}
+
+			// sqlite doesn't always support 'RETURNING' so
+			// we look-up the identity using the inserted rowid
+			rowID, err := res.LastInsertId()
+			if err != nil {
+				return {{identZero $}}, err
+			}
+
+			var ident Ident
+			err = squirrel.Select(
+				{{range $col := .Idents -}}
+					"\"{{$col.Name}}\"",
+				{{end -}}
+			).
+				From("\"{{.Collection}}\"").
+				Where("rowid = ?", rowID).
+				PlaceholderFormat(squirrel.Question).
+				RunWith(runner).
+				QueryRowContext(ctx).
+				Scan(
+					{{range $col := .Idents -}}
+						&ident.{{$col.Field}},
+					{{end -}}
+				)
+			if err != nil {
+				return {{identZero $}}, err
+			}
+
+			return ident, nil
+		{{- else -}}
+			_, err := qb.ExecContext(ctx)
+			if err != nil {
+				return {{identZero $}}, err
+			}
+
+			return Ident{
+				{{range $col := .Idents -}}
+					{{$col.Field}}: c.{{$col.Identifier}},
+				{{end -}}
+			}, nil
+		{{- end}}
+	{{- else if .Ident.Auto -}}
+		res, err := qb.ExecContext(ctx)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		// sqlite doesn't always support 'RETURNING' so
+		// we look-up the identity using the inserted rowid
+		rowID, err := res.LastInsertId()
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		var {{.Ident.Identifier}} {{identType $}}
+		err = squirrel.Select("\"{{.Ident.Name}}\"").
+			From("\"{{.Collection}}\"").
+			Where("rowid = ?", rowID).
+			PlaceholderFormat(squirrel.Question).
+			RunWith(runner).
+			QueryRowContext(ctx).
+			Scan(&{{.Ident.Identifier}})
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return {{.Ident.Identifier}}, nil
+	{{- else -}}
+		_, err := qb.ExecContext(ctx)
+		if err != nil {
+			return {{identZero $}}, err
+		}
+
+		return c.{{.Ident.Identifier}}, nil
+	{{- end}}
+}
+
+// CreateReturning creates a new {{.Type.Name}} and returns the created row
+func (sl *SQLiteRepository) CreateReturning(ctx context.Context, c *Creator) (*{{type .Type.V}}, error) {
+	return sl.createReturning(ctx, sl.db, c)
+}
+
+// CreateReturningTx creates a new {{.Type.Name}} inside a transaction and returns the created row
+func (sl *SQLiteRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.createReturning(ctx, txx, c)
+}
+
+func (sl *SQLiteRepository) createReturning(ctx context.Context, runner nero.SQLRunner, c *Creator) (*{{type .Type.V}}, error) {
+	columns, values := sl.createValues(c)
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: CreateReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite doesn't always support 'RETURNING' so
+	// we look-up the created row using the inserted rowid
+	rowID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	row := sl.buildSelect(&Queryer{}).
+		Where("rowid = ?", rowID).
+		RunWith(runner).
+		QueryRowContext(ctx)
+	return sl.scanColumns(row, allColumns())
+}
+
+// CreateMany creates many {{.Type.Name}}
+func (sl *SQLiteRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	// the creators are inserted in a single transaction
+	// so that nothing is inserted if one of them fails
+	tx, err := sl.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	idents, err := sl.createMany(ctx, tx, cs...)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return idents, nil
+}
+
+// CreateManyTx creates many {{.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]{{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.createMany(ctx, txx, cs...)
+}
+
+func (sl *SQLiteRepository) createMany(ctx context.Context, runner nero.SQLRunner, cs ...*Creator) ([]{{identType $}}, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	idents := make([]{{identType $}}, 0, len(cs))
+	{{if .HasAutoIdent -}}
+	// the creators are inserted one by one since the rowids of a multi-row
+	// insert are not necessarily consecutive e.g. an explicit rowid, so the
+	// identity of each row is looked-up using its own inserted rowid
+	for _, c := range cs {
+		ident, err := sl.create(ctx, runner, c)
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+	}
+	{{- else -}}
+	for _, group := range groupCreators(cs) {
+		err := sl.createGroup(ctx, runner, group)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range group {
+			{{if .HasCompositeIdent -}}
+				idents = append(idents, Ident{
+					{{range $col := .Idents -}}
+						{{$col.Field}}: c.{{$col.Identifier}},
+					{{end -}}
+				})
+			{{- else -}}
+				idents = append(idents, c.{{.Ident.Identifier}})
+			{{- end}}
+		}
+	}
+	{{- end}}
+
+	return idents, nil
+}
+{{if not .HasAutoIdent}}
+// createGroup inserts the creators that set the same columns in a single statement
+func (sl *SQLiteRepository) createGroup(ctx context.Context, runner nero.SQLRunner, cs []*Creator) error {
+	columns, _ := sl.createValues(cs[0])
+	qb := squirrel.Insert("\"{{.Collection}}\"").Columns(columns...)
+	for _, c := range cs {
+		_, values := sl.createValues(c)
+		qb = qb.Values(values...)
+	}
+
+	qb = qb.PlaceholderFormat(squirrel.Question)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: CreateMany, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+{{end}}
+// createValues returns the set columns and values of the creator
+func (sl *SQLiteRepository) createValues(c *Creator) ([]string, []interface{}) {
+	columns := []string{}
+	values := []interface{}{}
+	{{range $col := .Cols }}
+		{{if ne $col.Auto true}}
+			if c.IsSet(Column{{$col.Field}}) {
+				columns = append(columns, "\"{{$col.Name}}\"")
+				{{if $col.Nullable -}}
+					if c.IsNull(Column{{$col.Field}}) {
+						values = append(values, nil)
+					} else {
+				{{end -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					values = append(values, nero.JSON(c.{{$col.Identifier}}))
+				{{else -}}
+					values = append(values, c.{{$col.Identifier}})
+				{{end -}}
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
+			}
+		{{end}}
+	{{end}}
+
+	return columns, values
+}
+
+// Upsert creates a new {{.Type.Name}} or updates the conflicting one
+func (sl *SQLiteRepository) Upsert(ctx context.Context, u *Upserter) ({{identType $}}, error) {
+	return sl.upsert(ctx, sl.db, u)
+}
+
+// UpsertTx creates a new {{.Type.Name}} or updates the conflicting one inside a transaction
+func (sl *SQLiteRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) ({{identType $}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return {{identZero $}}, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.upsert(ctx, txx, u)
+}
+
+func (sl *SQLiteRepository) upsert(ctx context.Context, runner nero.SQLRunner, u *Upserter) ({{identType $}}, error) {
+	if err := u.validate(true); err != nil {
+		return {{identZero $}}, err
+	}
+
+	columns, values := sl.createValues(u.Creators()[0])
+	qb := squirrel.Insert("\"{{.Collection}}\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix(sl.onConflict(u)).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.ExecContext(ctx)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	// the inserted or updated row is looked-up using the conflict
+	// columns since the last insert id is not set on update
+	return sl.conflictIdent(ctx, runner, u, columns, values)
+}
+
+// UpsertMany creates many {{.Type.Name}} or updates the conflicting ones
+func (sl *SQLiteRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	return sl.upsertMany(ctx, sl.db, u)
+}
+
+// UpsertManyTx creates many {{.Type.Name}} or updates the conflicting ones inside a transaction
+func (sl *SQLiteRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.upsertMany(ctx, txx, u)
+}
+
+func (sl *SQLiteRepository) upsertMany(ctx context.Context, runner nero.SQLRunner, u *Upserter) error {
+	if len(u.Creators()) == 0 {
+		return nil
+	}
+
+	if err := u.validate(false); err != nil {
+		return err
+	}
+
+	columns, _ := sl.createValues(u.Creators()[0])
+	qb := squirrel.Insert("\"{{.Collection}}\"").Columns(columns...)
+	for _, c := range u.Creators() {
+		_, values := sl.createValues(c)
+		qb = qb.Values(values...)
+	}
+
+	qb = qb.Suffix(sl.onConflict(u)).
+		PlaceholderFormat(squirrel.Question)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: UpsertMany, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// onConflict builds the 'ON CONFLICT' clause of the upsert
+func (sl *SQLiteRepository) onConflict(u *Upserter) string {
+	target := []string{}
+	for _, col := range u.ConflictColumns() {
+		target = append(target, fmt.Sprintf("%q", col))
+	}
+
+	updates := []string{}
+	for _, col := range u.UpdateColumns() {
+		updates = append(updates, fmt.Sprintf("%q = excluded.%q", col, col))
+	}
+
+	clause := "ON CONFLICT (" + strings.Join(target, ", ") + ")"
+	if len(updates) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	return clause + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// conflictIdent looks-up the identity of the conflicting row
+func (sl *SQLiteRepository) conflictIdent(ctx context.Context, runner nero.SQLRunner, u *Upserter, columns []string, values []interface{}) ({{identType $}}, error) {
+	eq := squirrel.Eq{}
+	for _, col := range u.ConflictColumns() {
+		name := fmt.Sprintf("%q", col)
+		for i, column := range columns {
+			if column == name {
+				eq[name] = values[i]
+			}
+		}
+	}
+
+	qb := squirrel.Select({{range $i, $col := .Idents}}{{if $i}}, {{end}}"\"{{$col.Name}}\""{{end}}).
+		From("\"{{.Collection}}\"").
+		Where(eq).
+		PlaceholderFormat(squirrel.Question).
+		RunWith(runner)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var ident {{identType $}}
+	err := qb.QueryRowContext(ctx).Scan(
+		{{if .HasCompositeIdent -}}
+			{{range $col := .Idents -}}
+				&ident.{{$col.Field}},
+			{{end -}}
+		{{else -}}
+			&ident,
+		{{end -}}
+	)
+	if err != nil {
+		return {{identZero $}}, err
+	}
+
+	return ident, nil
+}
+
+// Query queries many {{.Type.Name}}
+func (sl *SQLiteRepository) Query(ctx context.Context, q *Queryer) ([]*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return sl.query(ctx, sl.db, q)
+}
+
+// QueryTx queries many {{.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Queryer) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.query(ctx, txx, q)
+}
+
+func (sl *SQLiteRepository) query(ctx context.Context, runner nero.SQLRunner, q *Queryer) ([]*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := sl.buildSelect(q)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Query, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := sl.scanColumns(rows, q.columns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+	{{if .Edges}}
+	// close the rows before loading the edges using the same runner
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sl.loadEdges(ctx, runner, q, {{plural (lowerCamel .Type.Name)}})
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	if q.reversed {
+		reverseRows({{plural (lowerCamel .Type.Name)}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// QueryOne queries one {{.Type.Name}}
+func (sl *SQLiteRepository) QueryOne(ctx context.Context, q *Queryer) (*{{type .Type.V}}, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
+	return sl.queryOne(ctx, sl.db, q)
+}
+
+// QueryOneTx queries one {{.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Queryer) (*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.queryOne(ctx, txx, q)
+}
+
+// QueryPage queries a page of {{.Type.Name}} along with the cursors of the adjacent pages
+func (sl *SQLiteRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := sl.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of {{.Type.Name}} inside a transaction along with the cursors of the adjacent pages
+func (sl *SQLiteRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := sl.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the {{.Type.Name}} rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (sl *SQLiteRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	if q.lock != nero.NoLock {
+		return 0, nero.ErrLockOutsideTx
+	}
+
+	return sl.count(ctx, sl.db, q)
+}
+
+// CountTx counts the {{.Type.Name}} rows that match the predicates of the query inside a transaction
+func (sl *SQLiteRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.count(ctx, txx, q)
+}
+
+func (sl *SQLiteRepository) count(ctx context.Context, runner nero.SQLRunner, q *Queryer) (int64, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return 0, &nero.UnsupportedError{Op: "row locking in Count", Repository: "SQLiteRepository"}
+	}
+
+	qb := squirrel.Select("COUNT(*)").
+		From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Question)
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Count, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var count int64
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&count)
+	return count, err
+}
+
+// Exists returns true if any of the {{.Type.Name}} rows matches the predicates of the query
+func (sl *SQLiteRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	if q.lock != nero.NoLock {
+		return false, nero.ErrLockOutsideTx
+	}
+
+	return sl.exists(ctx, sl.db, q)
+}
+
+// ExistsTx returns true if any of the {{.Type.Name}} rows matches the predicates of the query inside a transaction
+func (sl *SQLiteRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return false, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.exists(ctx, txx, q)
+}
+
+func (sl *SQLiteRepository) exists(ctx context.Context, runner nero.SQLRunner, q *Queryer) (bool, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return false, &nero.UnsupportedError{Op: "row locking in Exists", Repository: "SQLiteRepository"}
+	}
+
+	sub := squirrel.Select("1").From("\"{{.Collection}}\"")
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		sub = sub.Where(cond)
+	}
+	qb := squirrel.Select().
+		Column(squirrel.Expr("EXISTS(?)", sub)).
+		PlaceholderFormat(squirrel.Question)
+
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Exists, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var exists bool
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&exists)
+	return exists, err
+}
+
+func (sl *SQLiteRepository) queryOne(ctx context.Context, runner nero.SQLRunner, q *Queryer) (*{{type .Type.V}}, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := sl.buildSelect(q)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: QueryOne, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	{{lowerCamel .Type.Name}}, err := sl.scanColumns(qb.RunWith(runner).QueryRowContext(ctx), q.columns())
+	if err != nil {
+		return {{zero .Type.V}}, err
+	}
+	{{if .Edges}}
+	err = sl.loadEdges(ctx, runner, q, []*{{type .Type.V}}{ {{lowerCamel .Type.Name}} })
+	if err != nil {
+		return nil, err
+	}
+	{{end}}
+	return {{lowerCamel .Type.Name}}, nil
+}
+
+// scanColumns scans a row of the columns into {{.Type.Name}}
+func (sl *SQLiteRepository) scanColumns(scanner interface{ Scan(...interface{}) error }, cols []Column) (*{{type .Type.V}}, error) {
+	var {{lowerCamel .Type.Name}} {{type .Type.V}}
+	dest := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		switch col {
+		{{range $col := .Cols -}}
+		case Column{{$col.Field}}:
+			{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+				dest = append(dest, nero.JSON(&{{lowerCamel $.Type.Name}}.{{$col.Field}}))
+			{{else -}}
+				dest = append(dest, &{{lowerCamel $.Type.Name}}.{{$col.Field}})
+			{{end -}}
+		{{end -}}
+		}
+	}
+
+	err := scanner.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &{{lowerCamel .Type.Name}}, nil
+}
+
+// scanAll scans the rows into {{.Type.Name}} and closes them
+func (sl *SQLiteRepository) scanAll(rows *sql.Rows) ([]*{{type .Type.V}}, error) {
+	defer rows.Close()
+
+	{{plural (lowerCamel .Type.Name)}} := []*{{type .Type.V}}{}
+	for rows.Next() {
+		{{lowerCamel .Type.Name}}, err := sl.scanColumns(rows, allColumns())
+		if err != nil {
+			return nil, err
+		}
+
+		{{plural (lowerCamel .Type.Name)}} = append({{plural (lowerCamel .Type.Name)}}, {{lowerCamel .Type.Name}})
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, rows.Err()
+}
+{{if .Edges}}
+// loadEdges eager-loads the edges of the rows
+func (sl *SQLiteRepository) loadEdges(ctx context.Context, runner nero.SQLRunner, q *Queryer, rows []*{{type .Type.V}}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	{{range $edge := .Edges}}
+	if q.with{{$edge.StructField}} {
+		related, err := sl.load{{$edge.StructField}}(ctx, runner, edge{{$edge.StructField}}Keys(rows))
+		if err != nil {
+			return errors.Wrap(err, "load {{$edge.Name}}")
+		}
+		setEdge{{$edge.StructField}}(rows, related)
+	}
+	{{end}}
+	return nil
+}
+{{range $edge := .Edges}}
+{{if $edge.IsManyToMany -}}
+// load{{$edge.StructField}} queries the associated rows of the "{{$edge.Name}}" edge by key
+func (sl *SQLiteRepository) load{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, keys []{{type $edge.Col.Type.V}}) (map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	qb := squirrel.Select(
+		"\"{{$edge.Join}}\".\"{{$edge.JoinCol}}\"",
+		{{range $col := $edge.Ref.Cols -}}
+			"\"{{$edge.Ref.Collection}}\".\"{{$col.Name}}\"",
+		{{end -}}
+	).
+		From("\"{{$edge.Ref.Collection}}\"").
+		Join("\"{{$edge.Join}}\" ON \"{{$edge.Join}}\".\"{{$edge.JoinRefCol}}\" = \"{{$edge.Ref.Collection}}\".\"{{$edge.RefCol.Name}}\"").
+		Where(squirrel.Eq{"\"{{$edge.Join}}\".\"{{$edge.JoinCol}}\"": args}).
+		PlaceholderFormat(squirrel.Question)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := map[{{type $edge.Col.Type.V}}][]*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var key {{type $edge.Col.Type.V}}
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			&key,
+			{{range $col := $edge.Ref.Cols -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					nero.JSON(&item.{{$col.Field}}),
+				{{else -}}
+					&item.{{$col.Field}},
+				{{end -}}
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related[key] = append(related[key], &item)
+	}
+
+	return related, nil
+}
+{{- else -}}
+// load{{$edge.StructField}} queries the related rows of the "{{$edge.Name}}" edge
+func (sl *SQLiteRepository) load{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, keys []{{type $edge.Col.Type.V}}) ([]*{{type $edge.Ref.Type.V}}, error) {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	qb := squirrel.Select(
+		{{range $col := $edge.Ref.Cols -}}
+			"\"{{$col.Name}}\"",
+		{{end -}}
+	).
+		From("\"{{$edge.Ref.Collection}}\"").
+		Where(squirrel.Eq{"\"{{$edge.RefCol.Name}}\"": args}).
+		PlaceholderFormat(squirrel.Question)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: load{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []*{{type $edge.Ref.Type.V}}{}
+	for rows.Next() {
+		var item {{type $edge.Ref.Type.V}}
+		err = rows.Scan(
+			{{range $col := $edge.Ref.Cols -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					nero.JSON(&item.{{$col.Field}}),
+				{{else -}}
+					&item.{{$col.Field}},
+				{{end -}}
+			{{end -}}
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		related = append(related, &item)
+	}
+
+	return related, nil
+}
+{{- end}}
+{{end}}
+{{range $edge := .ManyToManyEdges}}
+// Add{{$edge.StructField}} adds the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (sl *SQLiteRepository) Add{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return sl.add{{$edge.StructField}}(ctx, sl.db, id, refIDs...)
+}
+
+// Add{{$edge.StructField}}Tx adds the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) Add{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.add{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// add{{$edge.StructField}} inserts the join rows directly, the join collection
+// has no schema so there's no Creator for it
+func (sl *SQLiteRepository) add{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	qb := squirrel.Insert("\"{{$edge.Join}}\"").
+		Columns("\"{{$edge.JoinCol}}\"", "\"{{$edge.JoinRefCol}}\"").
+		PlaceholderFormat(squirrel.Question)
+	for _, refID := range refIDs {
+		qb = qb.Values(id, refID)
+	}
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Add{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// Remove{{$edge.StructField}} removes the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (sl *SQLiteRepository) Remove{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	return sl.remove{{$edge.StructField}}(ctx, sl.db, id, refIDs...)
+}
+
+// Remove{{$edge.StructField}}Tx removes the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) Remove{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.remove{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// remove{{$edge.StructField}} deletes the join rows directly since there's no
+// Deleter for the join collection, the delete is always filtered by the identity
+// and is skipped without refIDs, so unlike an unguarded Deleter it can't remove
+// the associations of the other rows
+func (sl *SQLiteRepository) remove{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	if len(refIDs) == 0 {
+		return nil
+	}
+
+	refArgs := make([]interface{}, 0, len(refIDs))
+	for _, refID := range refIDs {
+		refArgs = append(refArgs, refID)
+	}
+
+	qb := squirrel.Delete("\"{{$edge.Join}}\"").
+		Where(squirrel.Eq{"\"{{$edge.JoinCol}}\"": id, "\"{{$edge.JoinRefCol}}\"": refArgs}).
+		PlaceholderFormat(squirrel.Question)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Remove{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// Set{{$edge.StructField}} replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}}
+func (sl *SQLiteRepository) Set{{$edge.StructField}}(ctx context.Context, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	tx, err := sl.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = sl.set{{$edge.StructField}}(ctx, tx, id, refIDs...)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+// Set{{$edge.StructField}}Tx replaces the "{{$edge.Name}}" associations of the {{$.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) Set{{$edge.StructField}}Tx(ctx context.Context, tx nero.Tx, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.set{{$edge.StructField}}(ctx, txx, id, refIDs...)
+}
+
+// set{{$edge.StructField}} replaces the join rows of the identity, the delete
+// is filtered by the identity so only its associations are removed
+func (sl *SQLiteRepository) set{{$edge.StructField}}(ctx context.Context, runner nero.SQLRunner, id {{type $edge.Col.Type.V}}, refIDs ...{{type $edge.RefCol.Type.V}}) error {
+	qb := squirrel.Delete("\"{{$edge.Join}}\"").
+		Where(squirrel.Eq{"\"{{$edge.JoinCol}}\"": id}).
+		PlaceholderFormat(squirrel.Question)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Set{{$edge.StructField}}, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	_, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return sl.add{{$edge.StructField}}(ctx, runner, id, refIDs...)
+}
+{{end}}
+{{end -}}
+
+func (sl *SQLiteRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
+	columns := []string{}
+	for _, col := range q.columns() {
+		columns = append(columns, fmt.Sprintf("%q", col.String()))
+	}
+	qb := squirrel.Select(columns...).
+		From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Question)
+
+	pfs := q.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	sfs := q.sfs
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(sl.rank(s))
+			continue
+		}
+		col := fmt.Sprintf("%q", s.Col)
+		switch s.Direction {
+		case sort.Asc:
+			qb = qb.OrderBy(col + " ASC")
+		case sort.Desc:
+			qb = qb.OrderBy(col + " DESC")
+		}
+	}
+
+	if q.limit > 0 {
+		qb = qb.Limit(uint64(q.limit))
+	}
+
+	if q.offset > 0 {
+		qb = qb.Offset(uint64(q.offset))
+	}
+
+	if q.lock != nero.NoLock {
+		qb = qb.SuffixExpr(sl.lock(q))
+	}
+
+	return qb
+}
+
+// Update updates {{.Type.Name}}
+func (sl *SQLiteRepository) Update(ctx context.Context, u *Updater) (int64, error) {
+	return sl.update(ctx, sl.db, u)
+}
+
+// UpdateTx updates {{.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updater) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.update(ctx, txx, u)
+}
+
+func (sl *SQLiteRepository) update(ctx context.Context, runner nero.SQLRunner, u *Updater) (int64, error) {
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+
+	qb := sl.buildUpdate(u)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
+
+// UpdateReturning updates {{.Type.Name}} and returns the updated rows
+func (sl *SQLiteRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*{{type .Type.V}}, error) {
+	tx, err := sl.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := sl.updateReturning(ctx, tx, u)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// UpdateReturningTx updates {{.Type.Name}} inside a transaction and returns the updated rows
+func (sl *SQLiteRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.updateReturning(ctx, txx, u)
+}
+
+func (sl *SQLiteRepository) updateReturning(ctx context.Context, runner nero.SQLRunner, u *Updater) ([]*{{type .Type.V}}, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	// sqlite doesn't always support 'RETURNING' so we look-up
+	// the rowids of the matching rows before updating them
+	rowIDs, err := sl.rowIDs(ctx, runner, u.pfs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = sl.update(ctx, runner, u)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sl.buildSelect(&Queryer{}).
+		Where(squirrel.Eq{"rowid": rowIDs}).
+		RunWith(runner).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sl.scanAll(rows)
+}
+
+// rowIDs returns the rowids of the rows matching the predicates
+func (sl *SQLiteRepository) rowIDs(ctx context.Context, runner nero.SQLRunner, pfs []PredFunc) ([]int64, error) {
+	qb := squirrel.Select("rowid").
+		From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Question)
+
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rowIDs := []int64{}
+	for rows.Next() {
+		var rowID int64
+		err = rows.Scan(&rowID)
+		if err != nil {
+			return nil, err
+		}
+		rowIDs = append(rowIDs, rowID)
+	}
+
+	return rowIDs, rows.Err()
+}
+
+// buildUpdate builds the update statement
+func (sl *SQLiteRepository) buildUpdate(u *Updater) squirrel.UpdateBuilder {
+	qb := squirrel.Update("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Question)
+	{{range $col := .Cols}}
+		{{if ne $col.Auto true}}
+			if u.IsSet(Column{{$col.Field}}) {
+				{{if $col.Nullable -}}
+					if u.IsNull(Column{{$col.Field}}) {
+						qb = qb.Set("\"{{$col.Name}}\"", nil)
+					} else {
+				{{end -}}
+				{{if and (or $col.IsArray $col.IsMap) (ne $col.IsValueScanner true) -}}
+					qb = qb.Set("\"{{$col.Name}}\"", nero.JSON(u.{{$col.Identifier}}))
+				{{else -}}
+					qb = qb.Set("\"{{$col.Name}}\"", u.{{$col.Identifier}})
+				{{end -}}
+				{{if $col.Nullable -}}
+					}
+				{{end -}}
+			}
+		{{end}}
+	{{end}}
+
+	for _, e := range u.exprs {
+		qb = qb.Set(fmt.Sprintf("%q", e.col.String()), sl.updateExpr(e))
+	}
+
+	pfs := u.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
+// updateExpr builds the value of an update expression
+func (sl *SQLiteRepository) updateExpr(e *updateExpr) squirrel.Sqlizer {
+	switch e.op {
+	case updateIncr:
+		return squirrel.Expr(fmt.Sprintf("%q + ?", e.col.String()), e.arg)
+	case updateDecr:
+		return squirrel.Expr(fmt.Sprintf("%q - ?", e.col.String()), e.arg)
+	}
+	return squirrel.Expr(fmt.Sprintf("%q", e.arg.(Column).String()))
+}
+
+// Delete deletes {{.Type.Name}}
+func (sl *SQLiteRepository) Delete(ctx context.Context, d *Deleter) (int64, error) {
+	return sl.delete(ctx, sl.db, d)
+}
+
+// Delete deletes {{.Type.Name}} inside a transaction
+func (sl *SQLiteRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Deleter) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.delete(ctx, txx, d)
+}
+
+func (sl *SQLiteRepository) delete(ctx context.Context, runner nero.SQLRunner, d *Deleter) (int64, error) {
+	if err := d.Guard(); err != nil {
+		return 0, err
+	}
+
+	qb := sl.buildDelete(d)
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Delete, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
+
+// DeleteReturning deletes {{.Type.Name}} and returns the deleted rows
+func (sl *SQLiteRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*{{type .Type.V}}, error) {
+	tx, err := sl.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := sl.deleteReturning(ctx, tx, d)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// DeleteReturningTx deletes {{.Type.Name}} inside a transaction and returns the deleted rows
+func (sl *SQLiteRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*{{type .Type.V}}, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.deleteReturning(ctx, txx, d)
+}
+
+func (sl *SQLiteRepository) deleteReturning(ctx context.Context, runner nero.SQLRunner, d *Deleter) ([]*{{type .Type.V}}, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	// sqlite doesn't always support 'RETURNING' so
+	// we query the matching rows before deleting them
+	rows, err := sl.buildSelect(&Queryer{pfs: d.pfs}).
+		RunWith(runner).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	{{plural (lowerCamel .Type.Name)}}, err := sl.scanAll(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = sl.delete(ctx, runner, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return {{plural (lowerCamel .Type.Name)}}, nil
+}
+
+// buildDelete builds the delete statement
+func (sl *SQLiteRepository) buildDelete(d *Deleter) squirrel.DeleteBuilder {
+	qb := squirrel.Delete("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Question)
+
+	pfs := d.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
+// Aggregate runs aggregate operations
+func (sl *SQLiteRepository) Aggregate(ctx context.Context, a *Aggregator) error {
+	return sl.aggregate(ctx, sl.db, a)
+}
+
+// Aggregate runs aggregate operations inside a transaction
+func (sl *SQLiteRepository) AggregateTx(ctx context.Context, tx nero.Tx, a *Aggregator) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return sl.aggregate(ctx, txx, a)
+}
+
+func (sl *SQLiteRepository) aggregate(ctx context.Context, runner nero.SQLRunner, a *Aggregator) error {
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+	cols := []string{}
+	for _, agg := range aggs.All() {
+		col := agg.Col
+		qcol := fmt.Sprintf("%q", col)
+		switch agg.Fn {
+		case aggregate.Avg:
+			cols = append(cols, "AVG("+qcol+") avg_"+col)
+		case aggregate.Count:
+			cols = append(cols, "COUNT("+qcol+") count_"+col)
+		case aggregate.Max:
+			cols = append(cols, "MAX("+qcol+") max_"+col)
+		case aggregate.Min:
+			cols = append(cols, "MIN("+qcol+") min_"+col)
+		case aggregate.Sum:
+			cols = append(cols, "SUM("+qcol+") sum_"+col)
+		case aggregate.None:
+			cols = append(cols, qcol)
+		}
+	}
+
+	qb := squirrel.Select(cols...).From("\"{{.Collection}}\"").
+		PlaceholderFormat(squirrel.Question)
+
+	groups := []string{}
+	for _, group := range a.groups {
+		groups = append(groups, fmt.Sprintf("%q", group.String()))
+	}
+	qb = qb.GroupBy(groups...)
+
+	pfs := a.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range sl.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	sfs := a.sfs
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(sl.rank(s))
+			continue
+		}
+		col := fmt.Sprintf("%q", s.Col)
+		switch s.Direction {
+		case sort.Asc:
+			qb = qb.OrderBy(col + " ASC")
+		case sort.Desc:
+			qb = qb.OrderBy(col + " DESC")
+		}
+	}
+
+	if sl.debug {
+		sql, args, err := qb.ToSql()
+		sl.logger.Printf("method: Aggregate, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	v := reflect.ValueOf(a.v).Elem()
+	t := reflect.TypeOf(v.Interface()).Elem()
+	if t.NumField() != len(cols) {
+		return errors.New("aggregate columns and destination struct field count should match")
+	}
+
+	for rows.Next() {
+		ve := reflect.New(t).Elem()
+		dest := make([]interface{}, ve.NumField())
+		for i := 0; i < ve.NumField(); i++ {
+			dest[i] = ve.Field(i).Addr().Interface()
+		}
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.Append(v, ve))
+	}
+
+	return nil
+}
+`
@@ -0,0 +1,17 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteTemplate(t *testing.T) {
+	tmpl := NewSQLiteTemplate().WithFilename("sl.go")
+
+	assert.Equal(t, "sl.go", tmpl.Filename())
+
+	_, err := ParseTemplate(tmpl.Content())
+	require.NoError(t, err)
+}
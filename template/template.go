@@ -13,6 +13,8 @@ func ParseTemplate(tmpl string) (*template.Template, error) {
 			"zero":       zeroFunc,
 			"plural":     pluralFunc,
 			"lowerCamel": lowerCamelFunc,
+			"identType":  identTypeFunc,
+			"identZero":  identZeroFunc,
 		}).
 		Parse(tmpl)
 
@@ -0,0 +1,37 @@
+package compositekey
+
+import (
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/template"
+)
+
+// Membership demonstrates the use of a composite identity
+type Membership struct {
+	OrgID  int64
+	UserID string
+	Role   string
+}
+
+// Schema implements nero.Schemaer
+func (m *Membership) Schema() *nero.Schema {
+	return &nero.Schema{
+		Pkg:        "membership",
+		Collection: "memberships",
+		Columns: []*nero.Column{
+			nero.NewColumn("org_id", m.OrgID).
+				StructField("OrgID").Ident(),
+			nero.NewColumn("user_id", m.UserID).
+				StructField("UserID").Ident(),
+			nero.NewColumn("role", m.Role),
+		},
+		Templates: []nero.Templater{
+			template.NewPostgresTemplate(),
+			template.NewSQLiteTemplate(),
+			template.NewMySQLTemplate(),
+			template.NewMemoryTemplate(),
+			template.NewPgxTemplate(),
+			template.NewBoltTemplate(),
+			template.NewMockTemplate(),
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package compositekey_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/sf9v/nero/gen"
+	"github.com/sf9v/nero/test/gen/compositekey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeKey(t *testing.T) {
+	files, err := gen.Generate(new(compositekey.Membership))
+	require.NoError(t, err)
+	assert.Len(t, files, 12)
+
+	// create base directory
+	basePath := path.Join("gen", "membership")
+	err = os.MkdirAll(basePath, os.ModePerm)
+	require.NoError(t, err)
+
+	for _, f := range files {
+		err = f.Render(basePath)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,139 @@
+package compositekey_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/sf9v/nero/test/gen/compositekey/gen/membership"
+)
+
+func TestMembershipRepository(t *testing.T) {
+	db, err := sql.Open("sqlite", path.Join(t.TempDir(), "nero.db"))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE memberships(
+		org_id INTEGER NOT NULL,
+		user_id VARCHAR(27) NOT NULL,
+		role VARCHAR(20) NOT NULL,
+		PRIMARY KEY (org_id, user_id)
+	)`)
+	require.NoError(t, err)
+
+	repos := map[string]membership.Repository{
+		"SQLite": membership.NewSQLiteRepository(db),
+		"Memory": membership.NewMemoryRepository(),
+	}
+
+	for name, repo := range repos {
+		repo := repo
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			ident, err := repo.Create(ctx, membership.NewCreator().
+				OrgID(1).UserID("alice").Role("admin"))
+			require.NoError(t, err)
+			assert.Equal(t, membership.Ident{OrgID: 1, UserID: "alice"}, ident)
+
+			idents, err := repo.CreateMany(ctx,
+				membership.NewCreator().OrgID(1).UserID("bob").Role("member"),
+				membership.NewCreator().OrgID(2).UserID("alice").Role("member"),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, []membership.Ident{
+				{OrgID: 1, UserID: "bob"},
+				{OrgID: 2, UserID: "alice"},
+			}, idents)
+
+			// duplicate identity
+			_, err = repo.Create(ctx, membership.NewCreator().
+				OrgID(1).UserID("alice").Role("member"))
+			assert.Error(t, err)
+
+			m, err := repo.QueryOne(ctx, membership.NewQueryer().
+				Where(membership.IdentEq(membership.Ident{OrgID: 2, UserID: "alice"})))
+			require.NoError(t, err)
+			assert.Equal(t, "member", m.Role)
+
+			rowsAffected, err := repo.Update(ctx, membership.NewUpdater().
+				Role("owner").Where(membership.IdentEq(ident)))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), rowsAffected)
+
+			m, err = repo.QueryOne(ctx, membership.NewQueryer().
+				Where(membership.IdentEq(ident)))
+			require.NoError(t, err)
+			assert.Equal(t, "owner", m.Role)
+
+			// the identity is the default conflict target
+			ident2, err := repo.Upsert(ctx, membership.NewUpserter(
+				membership.NewCreator().OrgID(1).UserID("bob").Role("owner")))
+			require.NoError(t, err)
+			assert.Equal(t, membership.Ident{OrgID: 1, UserID: "bob"}, ident2)
+
+			m, err = repo.QueryOne(ctx, membership.NewQueryer().
+				Where(membership.IdentEq(ident2)))
+			require.NoError(t, err)
+			assert.Equal(t, "owner", m.Role)
+
+			rowsAffected, err = repo.Delete(ctx, membership.NewDeleter().
+				Where(membership.IdentEq(ident)))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), rowsAffected)
+
+			ms, err := repo.Query(ctx, membership.NewQueryer())
+			require.NoError(t, err)
+			assert.Len(t, ms, 2)
+		})
+	}
+}
+
+func TestMembershipPostgresCreateManyChunks(t *testing.T) {
+	// 3 set columns per row allows 21845 rows per insert
+	cs := make([]*membership.Creator, 21846)
+	for i := range cs {
+		cs[i] = membership.NewCreator().OrgID(1).
+			UserID(fmt.Sprintf("user%d", i)).Role("member")
+	}
+
+	newRepo := func(t *testing.T) (*membership.PostgresRepository, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return membership.NewPostgresRepository(db), mock
+	}
+
+	t.Run("Ok", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "memberships"`).WillReturnResult(sqlmock.NewResult(0, 21845))
+		mock.ExpectExec(`INSERT INTO "memberships"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// the identities are set by the creators
+		idents, err := repo.CreateMany(context.Background(), cs...)
+		require.NoError(t, err)
+		require.Len(t, idents, 21846)
+		assert.Equal(t, membership.Ident{OrgID: 1, UserID: "user21845"}, idents[21845])
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "memberships"`).WillReturnResult(sqlmock.NewResult(0, 21845))
+		mock.ExpectExec(`INSERT INTO "memberships"`).WillReturnError(errors.New("insert error"))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateMany(context.Background(), cs...)
+		assert.EqualError(t, err, "chunk 2 of 2: insert error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
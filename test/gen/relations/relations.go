@@ -0,0 +1,98 @@
+package relations
+
+import (
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/template"
+)
+
+// Author demonstrates the use of a has-many edge
+type Author struct {
+	ID    int64
+	Name  string
+	Books []*Book
+}
+
+// Schema implements nero.Schemaer
+func (a *Author) Schema() *nero.Schema {
+	return &nero.Schema{
+		Pkg:        "author",
+		Collection: "authors",
+		Columns: []*nero.Column{
+			nero.NewColumn("id", a.ID).
+				StructField("ID").Ident().Auto(),
+			nero.NewColumn("name", a.Name),
+		},
+		Edges: []*nero.Edge{
+			nero.HasMany("books", new(Book), "author_id"),
+		},
+		Templates: templates(),
+	}
+}
+
+// Book demonstrates the use of a belongs-to edge
+type Book struct {
+	ID       int64
+	AuthorID int64
+	Title    string
+	Tags     []string
+	Author   *Author
+	Genres   []*Genre
+}
+
+// Schema implements nero.Schemaer
+func (b *Book) Schema() *nero.Schema {
+	return &nero.Schema{
+		Pkg:        "book",
+		Collection: "books",
+		Columns: []*nero.Column{
+			nero.NewColumn("id", b.ID).
+				StructField("ID").Ident().Auto(),
+			nero.NewColumn("author_id", b.AuthorID).
+				StructField("AuthorID"),
+			nero.NewColumn("title", b.Title).
+				FullText("english"),
+			nero.NewColumn("tags", b.Tags),
+		},
+		Edges: []*nero.Edge{
+			nero.BelongsTo("author", new(Author), "author_id"),
+			nero.ManyToMany("genres", new(Genre), "book_genres", "book_id", "genre_id"),
+		},
+		Templates: templates(),
+	}
+}
+
+// Genre demonstrates the use of a many-to-many edge
+type Genre struct {
+	ID    int64
+	Name  string
+	Books []*Book
+}
+
+// Schema implements nero.Schemaer
+func (g *Genre) Schema() *nero.Schema {
+	return &nero.Schema{
+		Pkg:        "genre",
+		Collection: "genres",
+		Columns: []*nero.Column{
+			nero.NewColumn("id", g.ID).
+				StructField("ID").Ident().Auto(),
+			nero.NewColumn("name", g.Name),
+		},
+		Edges: []*nero.Edge{
+			nero.ManyToMany("books", new(Book), "book_genres", "genre_id", "book_id"),
+		},
+		Templates: templates(),
+	}
+}
+
+func templates() []nero.Templater {
+	return []nero.Templater{
+		template.NewPostgresTemplate(),
+		template.NewSQLiteTemplate(),
+		template.NewMySQLTemplate(),
+		template.NewMemoryTemplate(),
+		template.NewPgxTemplate(),
+		template.NewBoltTemplate(),
+		template.NewMockTemplate(),
+	}
+}
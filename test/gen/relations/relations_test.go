@@ -0,0 +1,37 @@
+package relations_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/gen"
+	"github.com/sf9v/nero/test/gen/relations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelations(t *testing.T) {
+	schemas := map[string]nero.Schemaer{
+		"author": new(relations.Author),
+		"book":   new(relations.Book),
+		"genre":  new(relations.Genre),
+	}
+
+	for pkg, schema := range schemas {
+		files, err := gen.Generate(schema)
+		require.NoError(t, err)
+		assert.Len(t, files, 12)
+
+		// create base directory
+		basePath := path.Join("gen", pkg)
+		err = os.MkdirAll(basePath, os.ModePerm)
+		require.NoError(t, err)
+
+		for _, f := range files {
+			err = f.Render(basePath)
+			require.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package relations_test
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+	_ "modernc.org/sqlite"
+
+	"github.com/sf9v/nero/test/gen/relations"
+	"github.com/sf9v/nero/test/gen/relations/gen/author"
+	"github.com/sf9v/nero/test/gen/relations/gen/book"
+	"github.com/sf9v/nero/test/gen/relations/gen/genre"
+)
+
+func TestSQLiteEdges(t *testing.T) {
+	db, err := sql.Open("sqlite", path.Join(t.TempDir(), "nero.db"))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE authors(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(50) NOT NULL
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE books(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		author_id INTEGER NOT NULL,
+		title VARCHAR(50) NOT NULL,
+		tags TEXT
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE genres(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(50) NOT NULL
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE book_genres(
+		book_id INTEGER NOT NULL,
+		genre_id INTEGER NOT NULL,
+		PRIMARY KEY (book_id, genre_id)
+	)`)
+	require.NoError(t, err)
+
+	runEdgesTest(t, author.NewSQLiteRepository(db),
+		book.NewSQLiteRepository(db), genre.NewSQLiteRepository(db))
+}
+
+func TestMemoryEdges(t *testing.T) {
+	authorRepo := author.NewMemoryRepository()
+	bookRepo := book.NewMemoryRepository()
+	genreRepo := genre.NewMemoryRepository()
+	authorRepo.WithBooksLoader(func(ctx context.Context, keys []int64) ([]*relations.Book, error) {
+		return bookRepo.Query(ctx, book.NewQueryer().Where(book.AuthorIDIn(keys...)))
+	})
+	bookRepo.WithAuthorLoader(func(ctx context.Context, keys []int64) ([]*relations.Author, error) {
+		return authorRepo.Query(ctx, author.NewQueryer().Where(author.IDIn(keys...)))
+	})
+	bookRepo.WithGenresLoader(func(ctx context.Context, keys []int64) ([]*relations.Genre, error) {
+		return genreRepo.Query(ctx, genre.NewQueryer().Where(genre.IDIn(keys...)))
+	})
+
+	runEdgesTest(t, authorRepo, bookRepo, genreRepo)
+
+	// loader is required
+	repo := author.NewMemoryRepository()
+	_, err := repo.Create(context.Background(), author.NewCreator().Name("alice"))
+	require.NoError(t, err)
+	_, err = repo.Query(context.Background(), author.NewQueryer().WithBooks())
+	assert.Error(t, err)
+}
+
+func TestBoltEdges(t *testing.T) {
+	db, err := bbolt.Open(path.Join(t.TempDir(), "nero.db"), 0600, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the collections share the database but not the bucket
+	authorRepo := author.NewBoltRepository(db)
+	bookRepo := book.NewBoltRepository(db)
+	genreRepo := genre.NewBoltRepository(db)
+	authorRepo.WithBooksLoader(func(ctx context.Context, keys []int64) ([]*relations.Book, error) {
+		return bookRepo.Query(ctx, book.NewQueryer().Where(book.AuthorIDIn(keys...)))
+	})
+	bookRepo.WithAuthorLoader(func(ctx context.Context, keys []int64) ([]*relations.Author, error) {
+		return authorRepo.Query(ctx, author.NewQueryer().Where(author.IDIn(keys...)))
+	})
+	bookRepo.WithGenresLoader(func(ctx context.Context, keys []int64) ([]*relations.Genre, error) {
+		return genreRepo.Query(ctx, genre.NewQueryer().Where(genre.IDIn(keys...)))
+	})
+
+	runEdgesTest(t, authorRepo, bookRepo, genreRepo)
+}
+
+func runEdgesTest(t *testing.T, authorRepo author.Repository,
+	bookRepo book.Repository, genreRepo genre.Repository) {
+	ctx := context.Background()
+
+	aliceID, err := authorRepo.Create(ctx, author.NewCreator().Name("alice"))
+	require.NoError(t, err)
+	bobID, err := authorRepo.Create(ctx, author.NewCreator().Name("bob"))
+	require.NoError(t, err)
+
+	_, err = bookRepo.CreateMany(ctx,
+		book.NewCreator().AuthorID(aliceID).Title("book 1").Tags([]string{"a"}),
+		book.NewCreator().AuthorID(aliceID).Title("book 2"),
+	)
+	require.NoError(t, err)
+
+	t.Run("has-many", func(t *testing.T) {
+		authors, err := authorRepo.Query(ctx, author.NewQueryer().
+			WithBooks().Sort(author.Asc(author.ColumnID)))
+		require.NoError(t, err)
+		require.Len(t, authors, 2)
+		require.Len(t, authors[0].Books, 2)
+		assert.Equal(t, "book 1", authors[0].Books[0].Title)
+		assert.Equal(t, []string{"a"}, authors[0].Books[0].Tags)
+		assert.Empty(t, authors[1].Books)
+
+		a, err := authorRepo.QueryOne(ctx, author.NewQueryer().
+			Where(author.IDEq(aliceID)).WithBooks())
+		require.NoError(t, err)
+		assert.Len(t, a.Books, 2)
+
+		// not loaded
+		a, err = authorRepo.QueryOne(ctx, author.NewQueryer().
+			Where(author.IDEq(aliceID)))
+		require.NoError(t, err)
+		assert.Nil(t, a.Books)
+	})
+
+	t.Run("belongs-to", func(t *testing.T) {
+		books, err := bookRepo.Query(ctx, book.NewQueryer().WithAuthor())
+		require.NoError(t, err)
+		require.Len(t, books, 2)
+		for _, b := range books {
+			require.NotNil(t, b.Author)
+			assert.Equal(t, "alice", b.Author.Name)
+		}
+	})
+
+	t.Run("existence", func(t *testing.T) {
+		authors, err := authorRepo.Query(ctx, author.NewQueryer().
+			Where(author.HasBooks()))
+		require.NoError(t, err)
+		require.Len(t, authors, 1)
+		assert.Equal(t, aliceID, authors[0].ID)
+
+		authors, err = authorRepo.Query(ctx, author.NewQueryer().
+			Where(author.HasNoBooks()))
+		require.NoError(t, err)
+		require.Len(t, authors, 1)
+		assert.Equal(t, bobID, authors[0].ID)
+
+		rowsAffected, err := authorRepo.Delete(ctx, author.NewDeleter().
+			Where(author.HasNoBooks()))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), rowsAffected)
+
+		books, err := bookRepo.Query(ctx, book.NewQueryer().
+			Where(book.HasAuthor()))
+		require.NoError(t, err)
+		assert.Len(t, books, 2)
+	})
+
+	t.Run("many-to-many", func(t *testing.T) {
+		fantasyID, err := genreRepo.Create(ctx, genre.NewCreator().Name("fantasy"))
+		require.NoError(t, err)
+		horrorID, err := genreRepo.Create(ctx, genre.NewCreator().Name("horror"))
+		require.NoError(t, err)
+
+		books, err := bookRepo.Query(ctx, book.NewQueryer().
+			Sort(book.Asc(book.ColumnID)))
+		require.NoError(t, err)
+		require.Len(t, books, 2)
+		book1, book2 := books[0].ID, books[1].ID
+
+		require.NoError(t, bookRepo.AddGenres(ctx, book1, fantasyID, horrorID))
+		require.NoError(t, bookRepo.SetGenres(ctx, book2, horrorID))
+
+		// duplicate association
+		assert.Error(t, bookRepo.AddGenres(ctx, book1, fantasyID))
+
+		b, err := bookRepo.QueryOne(ctx, book.NewQueryer().
+			Where(book.IDEq(book1)).WithGenres())
+		require.NoError(t, err)
+		assert.Len(t, b.Genres, 2)
+
+		require.NoError(t, bookRepo.RemoveGenres(ctx, book1, horrorID))
+		b, err = bookRepo.QueryOne(ctx, book.NewQueryer().
+			Where(book.IDEq(book1)).WithGenres())
+		require.NoError(t, err)
+		require.Len(t, b.Genres, 1)
+		assert.Equal(t, "fantasy", b.Genres[0].Name)
+
+		// the join rows are always filtered by the identity,
+		// nothing is removed without the referenced identities
+		require.NoError(t, bookRepo.RemoveGenres(ctx, book1))
+		books, err = bookRepo.Query(ctx, book.NewQueryer().
+			Where(book.HasGenres()))
+		require.NoError(t, err)
+		assert.Len(t, books, 2)
+
+		// rollback
+		tx, err := bookRepo.Tx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, bookRepo.SetGenresTx(ctx, tx, book2))
+		require.NoError(t, tx.Rollback())
+
+		books, err = bookRepo.Query(ctx, book.NewQueryer().
+			Where(book.HasGenres()))
+		require.NoError(t, err)
+		assert.Len(t, books, 2)
+
+		// commit
+		tx, err = bookRepo.Tx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, bookRepo.SetGenresTx(ctx, tx, book2))
+		require.NoError(t, tx.Commit())
+
+		books, err = bookRepo.Query(ctx, book.NewQueryer().
+			Where(book.HasNoGenres()).WithGenres())
+		require.NoError(t, err)
+		require.Len(t, books, 1)
+		assert.Equal(t, book2, books[0].ID)
+		assert.Empty(t, books[0].Genres)
+	})
+}
@@ -0,0 +1,170 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/example"
+	"github.com/sf9v/nero/test/integration/repository"
+	"github.com/sf9v/nero/test/integration/user"
+)
+
+func TestBoltRepository(t *testing.T) {
+	newDB := func(t *testing.T) *bbolt.DB {
+		db, err := bbolt.Open(filepath.Join(t.TempDir(), "nero.db"), 0600, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	// regular methods
+	newRepoTestRunner(repository.NewBoltRepository(newDB(t)), repoTestOpts{schemaless: true})(t)
+
+	// tx methods
+	newRepoTestRunnerTx(repository.NewBoltRepository(newDB(t)), repoTestOpts{schemaless: true})(t)
+}
+
+func TestBoltRepositorySpecifics(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nero.db")
+	db, err := bbolt.Open(path, 0600, nil)
+	require.NoError(t, err)
+	defer func() {
+		db.Close()
+	}()
+
+	repo := repository.NewBoltRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+	kv := example.Map{"a": "b"}
+
+	newCreator := func(i int) *repository.Creator {
+		return repository.NewCreator().
+			UID(ksuid.New()).
+			Email(fmt.Sprintf("bolt_%d@gg.io", i)).
+			Name(fmt.Sprintf("bolt_%d", i)).
+			Age(18 + i).
+			Group(user.Human).
+			Kv(kv).
+			Tags([]string{"one", fmt.Sprint(i)})
+	}
+
+	t.Run("Encoding", func(t *testing.T) {
+		// the bucket is created on the first write
+		users, err := repo.Query(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		for i := 1; i <= 5; i++ {
+			id, err := repo.Create(ctx, newCreator(i).UpdatedAt(&now))
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprint(i), id)
+		}
+
+		usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+			Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		assert.Equal(t, "1", usr.ID)
+		assert.Equal(t, user.Human, usr.Group)
+		assert.Equal(t, kv, usr.Kv)
+		assert.Equal(t, []string{"one", "1"}, usr.Tags)
+		require.NotNil(t, usr.UpdatedAt)
+		assert.True(t, now.Equal(*usr.UpdatedAt))
+		assert.Nil(t, usr.CreatedAt)
+
+		// the slices are compared element by element, a prefix
+		// is less unlike with the postgres text encoding "{one}"
+		_, err = repo.Update(ctx, repository.NewUpdater().
+			Tags([]string{"one"}).Where(repository.IDEq("5")))
+		require.NoError(t, err)
+		users, err = repo.Query(ctx, repository.NewQueryer().
+			Sort(repository.Desc(repository.ColumnTags)))
+		require.NoError(t, err)
+		require.Len(t, users, 5)
+		assert.Equal(t, "4", users[0].ID)
+		assert.Equal(t, "5", users[4].ID)
+
+		// full-text search is not supported
+		_, err = repo.Query(ctx, repository.NewQueryer().
+			Where(repository.NameMatches("bolt")))
+		assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+	})
+
+	t.Run("Tx", func(t *testing.T) {
+		// rollback, the sequence is rolled back as well
+		tx, err := repo.Tx(ctx)
+		require.NoError(t, err)
+		id, err := repo.CreateTx(ctx, tx, newCreator(6))
+		require.NoError(t, err)
+		assert.Equal(t, "6", id)
+		_, err = repo.DeleteTx(ctx, tx, repository.NewDeleter().AllRows())
+		require.NoError(t, err)
+
+		// the read-only transactions run alongside the read-write
+		// transaction and don't see its uncommitted changes
+		count, err := repo.Count(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+
+		require.NoError(t, tx.Rollback())
+		assert.Equal(t, sql.ErrTxDone, tx.Rollback())
+		_, err = repo.QueryTx(ctx, tx, repository.NewQueryer())
+		assert.Equal(t, sql.ErrTxDone, err)
+
+		// the read-write transactions are exclusive,
+		// so the rows are already locked
+		_, err = repo.QueryOne(ctx, repository.NewQueryer().ForUpdate())
+		assert.Equal(t, nero.ErrLockOutsideTx, err)
+		tx, err = repo.Tx(ctx)
+		require.NoError(t, err)
+		users, err := repo.QueryTx(ctx, tx, repository.NewQueryer().ForUpdate().SkipLocked())
+		require.NoError(t, err)
+		assert.Len(t, users, 5)
+		require.NoError(t, tx.Rollback())
+
+		// the transaction is rolled back on commit if the context is done
+		cctx, cancel := context.WithCancel(ctx)
+		tx, err = repo.Tx(cctx)
+		require.NoError(t, err)
+		_, err = repo.CreateTx(cctx, tx, newCreator(6))
+		require.NoError(t, err)
+		cancel()
+		assert.Error(t, tx.Commit())
+		_, err = repo.Tx(cctx)
+		assert.Error(t, err)
+
+		count, err = repo.Count(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+
+		// wrong tx type
+		_, err = repo.QueryTx(ctx, &sql.Tx{}, repository.NewQueryer())
+		assert.Error(t, err)
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		require.NoError(t, db.Close())
+		db, err = bbolt.Open(path, 0600, nil)
+		require.NoError(t, err)
+		repo = repository.NewBoltRepository(db)
+
+		users, err := repo.Query(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Len(t, users, 5)
+
+		// the sequence is persisted, unlike the sql sequences
+		id, err := repo.Create(ctx, newCreator(6))
+		require.NoError(t, err)
+		assert.Equal(t, "6", id)
+	})
+}
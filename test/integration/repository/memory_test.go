@@ -0,0 +1,146 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/test/integration/repository"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	// regular methods
+	newRepoTestRunner(repository.NewMemoryRepository(), repoTestOpts{schemaless: true})(t)
+
+	// tx methods
+	newRepoTestRunnerTx(repository.NewMemoryRepository(), repoTestOpts{schemaless: true})(t)
+}
+
+func TestMemoryRepositorySpecifics(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+
+	newCreator := func(i int) *repository.Creator {
+		return repository.NewCreator().
+			UID(ksuid.New()).
+			Email(fmt.Sprintf("memory_%d@gg.io", i)).
+			Name(fmt.Sprintf("memory_%d", i)).
+			Age(18 + i).
+			Tags([]string{"one", fmt.Sprint(i)})
+	}
+
+	for i := 1; i <= 5; i++ {
+		id, err := repo.Create(ctx, newCreator(i))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprint(i), id)
+	}
+
+	t.Run("Copies", func(t *testing.T) {
+		// modifying the result doesn't modify the stored rows
+		users, err := repo.Query(ctx, repository.NewQueryer().Limit(1))
+		require.NoError(t, err)
+		users[0].Name = "modified"
+		usr, err := repo.QueryOne(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Equal(t, "memory_1", usr.Name)
+	})
+
+	t.Run("Slices", func(t *testing.T) {
+		// the slices are compared element by element
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			Sort(repository.Desc(repository.ColumnTags)))
+		require.NoError(t, err)
+		require.Len(t, users, 5)
+		assert.Equal(t, "5", users[0].ID)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := repo.Query(ctx, repository.NewQueryer().
+			Where(repository.NameMatches("memory")))
+		assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+		_, err = repo.Query(ctx, repository.NewQueryer().
+			Sort(repository.NameRank("memory")))
+		assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+	})
+
+	t.Run("Tx", func(t *testing.T) {
+		// rollback discards the snapshot
+		tx, err := repo.Tx(ctx)
+		require.NoError(t, err)
+		_, err = repo.DeleteTx(ctx, tx, repository.NewDeleter().AllRows())
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+		assert.Equal(t, sql.ErrTxDone, tx.Commit())
+		_, err = repo.QueryTx(ctx, tx, repository.NewQueryer())
+		assert.Equal(t, sql.ErrTxDone, err)
+
+		count, err := repo.Count(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+
+		// the row locks are no-op inside a transaction
+		_, err = repo.Query(ctx, repository.NewQueryer().ForUpdate())
+		assert.Equal(t, nero.ErrLockOutsideTx, err)
+		tx, err = repo.Tx(ctx)
+		require.NoError(t, err)
+		usr, err := repo.QueryOneTx(ctx, tx, repository.NewQueryer().ForUpdate().SkipLocked())
+		require.NoError(t, err)
+		assert.NotNil(t, usr)
+		require.NoError(t, tx.Rollback())
+
+		// commit
+		tx, err = repo.Tx(ctx)
+		require.NoError(t, err)
+		id, err := repo.CreateTx(ctx, tx, newCreator(6))
+		require.NoError(t, err)
+		_, err = repo.DeleteTx(ctx, tx, repository.NewDeleter().
+			Where(repository.IDEq("3")))
+		require.NoError(t, err)
+
+		// not visible outside the transaction
+		_, err = repo.QueryOne(ctx, repository.NewQueryer().
+			Where(repository.IDEq(id)))
+		assert.Equal(t, sql.ErrNoRows, err)
+
+		// changes outside the transaction are kept on commit
+		_, err = repo.Delete(ctx, repository.NewDeleter().
+			Where(repository.IDEq("4")))
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		users, err := repo.Query(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		ids := []string{}
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		assert.Equal(t, []string{"1", "2", "5", id}, ids)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 10; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := repo.Create(ctx, newCreator(i))
+				assert.NoError(t, err)
+				_, err = repo.Query(ctx, repository.NewQueryer())
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		count, err := repo.Count(ctx, repository.NewQueryer())
+		require.NoError(t, err)
+		assert.Equal(t, int64(14), count)
+	})
+}
@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
+	"github.com/sf9v/nero/sort"
+	"github.com/sf9v/nero/test/integration/repository"
+	"github.com/sf9v/nero/test/integration/user"
+)
+
+func TestMockRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Calls", func(t *testing.T) {
+		m := repository.NewMockRepository()
+		var repo repository.Repository = m
+
+		errUpdate := errors.New("update error")
+		m.UpdateFunc = func(ctx context.Context, u *repository.Updater) (int64, error) {
+			return 0, errUpdate
+		}
+		m.QueryOneFunc = func(ctx context.Context, q *repository.Queryer) (*user.User, error) {
+			return &user.User{ID: "1"}, nil
+		}
+
+		_, err := repo.Update(ctx, repository.NewUpdater().
+			Name("a").Where(repository.IDEq("1")))
+		assert.Equal(t, errUpdate, err)
+
+		usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+			Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		assert.Equal(t, "1", usr.ID)
+
+		// zero values are returned if the func is not set
+		rowsAffected, err := repo.Delete(ctx, repository.NewDeleter().AllRows())
+		assert.NoError(t, err)
+		assert.Zero(t, rowsAffected)
+		exists, err := repo.Exists(ctx, repository.NewQueryer())
+		assert.NoError(t, err)
+		assert.False(t, exists)
+
+		calls := m.Calls("Update")
+		require.Len(t, calls, 1)
+		assert.Equal(t, []*comparison.Predicate{
+			{Col: "id", Op: comparison.Eq, Arg: "1"},
+		}, calls[0].Updater.Predicates().All())
+
+		assert.Len(t, m.Calls(""), 4)
+		m.Reset()
+		assert.Len(t, m.Calls(""), 0)
+
+		// unfiltered deletes are refused before calling the func
+		m.DeleteFunc = func(ctx context.Context, d *repository.Deleter) (int64, error) {
+			return 1, nil
+		}
+		_, err = repo.Delete(ctx, repository.NewDeleter())
+		assert.IsType(t, &nero.UnfilteredError{}, err)
+		_, err = repo.Delete(ctx, repository.NewDeleter().
+			Where(repository.IDIn()))
+		assert.IsType(t, &nero.UnfilteredError{}, err)
+	})
+
+	t.Run("Tx", func(t *testing.T) {
+		m := repository.NewMockRepository()
+		tx, err := m.Tx(ctx)
+		require.NoError(t, err)
+
+		_, err = m.CreateTx(ctx, tx, repository.NewCreator().Name("a"))
+		require.NoError(t, err)
+		_, err = m.CreateManyTx(ctx, tx,
+			repository.NewCreator(), repository.NewCreator())
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		mtx := tx.(*repository.MockTx)
+		assert.True(t, mtx.Committed())
+		assert.False(t, mtx.RolledBack())
+
+		calls := m.Calls("CreateManyTx")
+		require.Len(t, calls, 1)
+		assert.Equal(t, tx, calls[0].Tx)
+		assert.Len(t, calls[0].Creators, 2)
+
+		m.TxFunc = func(context.Context) (nero.Tx, error) {
+			return nil, errors.New("tx error")
+		}
+		_, err = m.Tx(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestBuilders(t *testing.T) {
+	t.Run("Queryer", func(t *testing.T) {
+		q := repository.NewQueryer().
+			Where(repository.IDEq("1"), repository.UpdatedAtIsNull()).
+			Sort(repository.Desc(repository.ColumnAge)).
+			Limit(10).Offset(20)
+
+		assert.Equal(t, []*comparison.Predicate{
+			{Col: "id", Op: comparison.Eq, Arg: "1"},
+			{Col: "updated_at", Op: comparison.IsNull},
+		}, q.Predicates().All())
+		assert.Equal(t, []*sort.Sort{
+			{Col: "age", Direction: sort.Desc},
+		}, q.Sorts().All())
+
+		limit, offset := q.LimitOffset()
+		assert.Equal(t, uint(10), limit)
+		assert.Equal(t, uint(20), offset)
+
+		after, before := q.After("abc").Cursors()
+		assert.Equal(t, nero.Cursor("abc"), after)
+		assert.Empty(t, before)
+		after, before = q.Before("def").Cursors()
+		assert.Empty(t, after)
+		assert.Equal(t, nero.Cursor("def"), before)
+	})
+
+	t.Run("Deleter", func(t *testing.T) {
+		d := repository.NewDeleter().Where(repository.GroupIn(user.Human))
+		assert.Equal(t, []*comparison.Predicate{
+			{Col: "group", Op: comparison.In, Arg: []interface{}{user.Human}},
+		}, d.Predicates().All())
+	})
+
+	t.Run("Aggregator", func(t *testing.T) {
+		a := repository.NewAggregator(&[]struct{}{}).
+			Aggregate(repository.Count(repository.ColumnID),
+				repository.None(repository.ColumnGroup)).
+			Where(repository.AgeGt(18)).
+			Group(repository.ColumnGroup).
+			Sort(repository.Asc(repository.ColumnGroup))
+
+		assert.Equal(t, []*aggregate.Aggregate{
+			{Col: "id", Fn: aggregate.Count},
+			{Col: "group", Fn: aggregate.None},
+		}, a.Aggregates().All())
+		assert.Equal(t, []*comparison.Predicate{
+			{Col: "age", Op: comparison.Gt, Arg: 18},
+		}, a.Predicates().All())
+		assert.Equal(t, []*sort.Sort{
+			{Col: "group", Direction: sort.Asc},
+		}, a.Sorts().All())
+		assert.Equal(t, []repository.Column{repository.ColumnGroup}, a.Groups())
+	})
+}
@@ -0,0 +1,487 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/example"
+	"github.com/sf9v/nero/test/integration/repository"
+	"github.com/sf9v/nero/test/integration/user"
+)
+
+// TestMySQLRepository runs the integration tests against the database
+// specified in NERO_MYSQL_DSN e.g. "root:mysql@/nero?parseTime=true"
+func TestMySQLRepository(t *testing.T) {
+	dsn := os.Getenv("NERO_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("NERO_MYSQL_DSN is not set")
+	}
+
+	// regular methods
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	require.NoError(t, db.Ping())
+	require.NoError(t, createMySQLTable(db))
+
+	logger := log.New(os.Stderr, "nero test: ", 0)
+	repo := repository.NewMySQLRepository(db).Debug().WithLogger(logger)
+	newRepoTestRunner(repo, repoTestOpts{})(t)
+	require.NoError(t, dropTable(db))
+
+	// tx methods
+	require.NoError(t, createMySQLTable(db))
+	repo = repository.NewMySQLRepository(db).Debug().WithLogger(logger)
+	newRepoTestRunnerTx(repo, repoTestOpts{})(t)
+	require.NoError(t, dropTable(db))
+}
+
+func createMySQLTable(db *sql.DB) error {
+	_, err := db.Exec("CREATE TABLE users(" +
+		"id BIGINT AUTO_INCREMENT PRIMARY KEY," +
+		"uid VARCHAR(27) NOT NULL UNIQUE," +
+		"email VARCHAR(255) NOT NULL UNIQUE," +
+		"`name` VARCHAR(50) NOT NULL," +
+		"age INTEGER NOT NULL," +
+		"`group` VARCHAR(20) NOT NULL," +
+		"kv JSON NULL," +
+		"tags JSON NOT NULL," +
+		"updated_at TIMESTAMP NULL," +
+		"created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP" +
+		")")
+	return err
+}
+
+// TestMySQLRepositorySQL compares the statements sent to the database
+// with the expected (golden) MySQL statements
+func TestMySQLRepositorySQL(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func(t *testing.T) (*repository.MySQLRepository, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+			db.Close()
+		})
+		return repository.NewMySQLRepository(db), mock
+	}
+
+	cols := []string{"id", "uid", "email", "name", "age", "group", "kv", "tags", "updated_at", "created_at"}
+	selectStmt := "SELECT `id`, `uid`, `email`, `name`, `age`, `group`, `kv`, `tags`, `updated_at`, `created_at` FROM `users`"
+
+	t.Run("Create", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		uid := ksuid.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `users` (`uid`,`email`,`tags`) VALUES (?,?,?)").
+			WithArgs(uid.String(), "a@gg.io", `["one"]`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("SELECT LAST_INSERT_ID()").
+			WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(1))
+		mock.ExpectCommit()
+
+		id, err := repo.Create(ctx, repository.NewCreator().
+			UID(uid).Email("a@gg.io").Tags([]string{"one"}))
+		require.NoError(t, err)
+		assert.Equal(t, "1", id)
+	})
+
+	t.Run("CreateMany", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		// the ids of the rows are not consecutive
+		// e.g. with an auto_increment_increment of 2
+		for _, id := range []int{1, 3} {
+			mock.ExpectExec("INSERT INTO `users` (`kv`) VALUES (?)").
+				WillReturnResult(sqlmock.NewResult(int64(id), 1))
+			mock.ExpectQuery("SELECT LAST_INSERT_ID()").
+				WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(id))
+		}
+		mock.ExpectCommit()
+
+		ids, err := repo.CreateMany(ctx,
+			repository.NewCreator().Kv(example.Map{}),
+			repository.NewCreator().Kv(example.Map{}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "3"}, ids)
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec("INSERT INTO `users` (`email`,`name`) VALUES (?,?) "+
+			"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)").
+			WithArgs("a@gg.io", "a").
+			WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectQuery("SELECT `id` FROM `users` WHERE `email` = ?").
+			WithArgs("a@gg.io").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+		id, err := repo.Upsert(ctx, repository.NewUpserter(
+			repository.NewCreator().Email("a@gg.io").Name("a")).
+			OnConflict(repository.ColumnEmail))
+		require.NoError(t, err)
+		assert.Equal(t, "1", id)
+	})
+
+	t.Run("UpsertMany", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec("INSERT INTO `users` (`email`,`kv`) VALUES (?,?),(?,?) " +
+			"ON DUPLICATE KEY UPDATE `email` = `email`").
+			WillReturnResult(sqlmock.NewResult(2, 2))
+
+		err := repo.UpsertMany(ctx, repository.NewUpserter(
+			repository.NewCreator().Email("a@gg.io").Kv(example.Map{}),
+			repository.NewCreator().Email("b@gg.io").Kv(example.Map{})).
+			OnConflict(repository.ColumnEmail).
+			DoNothing())
+		require.NoError(t, err)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(selectStmt+
+			" WHERE `id` = ? AND `id` <> ? AND `age` > ? AND `age` >= ?"+
+			" AND `age` < ? AND `age` <= ? AND `updated_at` IS NULL"+
+			" AND `updated_at` IS NOT NULL AND `group` IN (?,?)"+
+			" AND `group` NOT IN (?)"+
+			" ORDER BY `id` ASC, `age` DESC LIMIT 10 OFFSET 20").
+			WithArgs("1", "2", 18, 18, 30, 30, "human", "charr", "norn").
+			WillReturnRows(sqlmock.NewRows(cols).
+				AddRow("1", ksuid.New().String(), "a@gg.io", "a", 18,
+					"human", []byte(`{"a":"b"}`), `["one","two"]`, nil, nil))
+
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			Where(
+				repository.IDEq("1"), repository.IDNotEq("2"),
+				repository.AgeGt(18), repository.AgeGtOrEq(18),
+				repository.AgeLt(30), repository.AgeLtOrEq(30),
+				repository.UpdatedAtIsNull(), repository.UpdatedAtIsNotNull(),
+				repository.GroupIn(user.Human, user.Charr),
+				repository.GroupNotIn(user.Norn),
+			).
+			Sort(
+				repository.Asc(repository.ColumnID),
+				repository.Desc(repository.ColumnAge),
+			).
+			Limit(10).Offset(20))
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, []string{"one", "two"}, users[0].Tags)
+		assert.Equal(t, example.Map{"a": "b"}, users[0].Kv)
+	})
+
+	t.Run("QueryOne", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(selectStmt + " WHERE `id` = ?").
+			WithArgs("1").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.QueryOne(ctx, repository.NewQueryer().
+			Where(repository.IDEq("1")))
+		assert.Equal(t, sql.ErrNoRows, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec("UPDATE `users` SET `name` = ?, `tags` = ? WHERE `id` = ?").
+			WithArgs("b", `["two"]`, "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		rowsAffected, err := repo.Update(ctx, repository.NewUpdater().
+			Name("b").Tags([]string{"two"}).
+			Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), rowsAffected)
+	})
+
+	t.Run("UpdateExpressions", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec("UPDATE `users` SET `age` = `age` + ?, `updated_at` = `created_at`, `name` = ? WHERE `id` = ?").
+			WithArgs(1, "b", "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		rowsAffected, err := repo.Update(ctx, repository.NewUpdater().
+			Name("b").AgeIncr(1).
+			UpdatedAtFromCol(repository.ColumnCreatedAt).
+			Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), rowsAffected)
+	})
+
+	t.Run("QueryGroups", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(selectStmt+" WHERE (`group` = ? OR (`age` > ? AND `age` < ?)) AND NOT (`id` = ?)").
+			WithArgs("human", 18, 30, "1").
+			WillReturnRows(sqlmock.NewRows(cols))
+
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			Where(
+				repository.Or(
+					repository.GroupEq(user.Human),
+					repository.And(repository.AgeGt(18), repository.AgeLt(30)),
+				),
+				repository.Not(repository.IDEq("1")),
+			))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("QueryPage", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		cursor, err := nero.NewCursor([]string{"age", "id"}, []interface{}{30, "5"})
+		require.NoError(t, err)
+
+		mock.ExpectQuery(selectStmt+" WHERE `group` = ? AND ((`age` < ?) OR (`age` = ? AND `id` > ?)) ORDER BY `age` DESC, `id` ASC LIMIT 2").
+			WithArgs("human", 30, 30, "5").
+			WillReturnRows(sqlmock.NewRows(cols))
+		mock.ExpectQuery(selectStmt+" WHERE ((`age` > ?) OR (`age` = ? AND `id` < ?)) ORDER BY `age` ASC, `id` DESC LIMIT 2").
+			WithArgs(30, 30, "5").
+			WillReturnRows(sqlmock.NewRows(cols))
+
+		page, err := repo.QueryPage(ctx, repository.NewQueryer().
+			Where(repository.GroupEq(user.Human)).
+			Sort(repository.Desc(repository.ColumnAge)).
+			Limit(2).After(cursor))
+		require.NoError(t, err)
+		assert.Empty(t, page.Rows)
+		assert.Empty(t, page.Next)
+
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			Sort(repository.Desc(repository.ColumnAge)).
+			Limit(2).Before(cursor))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("QuerySelect", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery("SELECT `id`, `name`, `email` FROM `users` WHERE `id` = ?").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+				AddRow("1", "John", "john@gg.io"))
+		mock.ExpectQuery("SELECT `tags`, `id` FROM `users` ORDER BY `id` ASC LIMIT 1").
+			WillReturnRows(sqlmock.NewRows([]string{"tags", "id"}).
+				AddRow(`["a","b"]`, "1"))
+
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			SelectSummary().Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "John", users[0].Name)
+		assert.Nil(t, users[0].Tags)
+
+		page, err := repo.QueryPage(ctx, repository.NewQueryer().
+			Select(repository.ColumnTags).Limit(1))
+		require.NoError(t, err)
+		require.Len(t, page.Rows, 1)
+		assert.Equal(t, []string{"a", "b"}, page.Rows[0].Tags)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE `group` = ?").
+			WithArgs("human").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM `users` WHERE `id` = ?)").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		count, err := repo.Count(ctx, repository.NewQueryer().
+			Where(repository.GroupEq(user.Human)))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		exists, err := repo.Exists(ctx, repository.NewQueryer().
+			Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("QueryLock", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectStmt + " WHERE `id` = ? LOCK IN SHARE MODE").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(cols))
+		mock.ExpectQuery(selectStmt + " WHERE `id` = ? FOR UPDATE SKIP LOCKED").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(cols))
+		mock.ExpectRollback()
+
+		tx, err := repo.Tx(ctx)
+		require.NoError(t, err)
+		users, err := repo.QueryTx(ctx, tx, repository.NewQueryer().
+			Where(repository.IDEq("1")).ForShare())
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		users, err = repo.QueryTx(ctx, tx, repository.NewQueryer().
+			Where(repository.IDEq("1")).ForUpdate().SkipLocked())
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		// mariadb doesn't support 'FOR SHARE' and mysql
+		// doesn't support 'LOCK IN SHARE MODE NOWAIT'
+		_, err = repo.QueryTx(ctx, tx, repository.NewQueryer().ForShare().NoWait())
+		assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+		_, err = repo.QueryTx(ctx, tx, repository.NewQueryer().ForNoKeyUpdate())
+		assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+		_, err = repo.ExistsTx(ctx, tx, repository.NewQueryer().ForUpdate())
+		assert.EqualError(t, err, "row locking in Exists is not supported by MySQLRepository")
+		require.NoError(t, tx.Rollback())
+
+		_, err = repo.Query(ctx, repository.NewQueryer().ForUpdate())
+		assert.Equal(t, nero.ErrLockOutsideTx, err)
+		_, err = repo.Count(ctx, repository.NewQueryer().ForShare())
+		assert.Equal(t, nero.ErrLockOutsideTx, err)
+	})
+
+	t.Run("QueryBetween", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(selectStmt+" WHERE `age` BETWEEN ? AND ? AND `id` NOT BETWEEN ? AND ?").
+			WithArgs(18, 30, "1", "9").
+			WillReturnRows(sqlmock.NewRows(cols))
+
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			Where(
+				repository.AgeBetween(18, 30),
+				repository.IDNotBetween("1", "9"),
+			))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("QueryLike", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(selectStmt+" WHERE `name` LIKE BINARY ? ESCAPE '\\\\' AND "+
+			"LOWER(`email`) LIKE LOWER(?) ESCAPE '\\\\' AND NOT (`name` LIKE BINARY ? ESCAPE '\\\\')").
+			WithArgs(`50\%\_off%`, "%@GG.IO", "A%").
+			WillReturnRows(sqlmock.NewRows(cols))
+
+		users, err := repo.Query(ctx, repository.NewQueryer().
+			Where(
+				repository.NameHasPrefix("50%_off"),
+				repository.EmailILike("%@GG.IO"),
+				repository.NameNotLike("A%"),
+			))
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectExec("DELETE FROM `users` WHERE `id` IN (?,?)").
+			WithArgs("1", "2").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		rowsAffected, err := repo.Delete(ctx, repository.NewDeleter().
+			Where(repository.IDIn("1", "2")))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), rowsAffected)
+	})
+
+	t.Run("CreateReturning", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		uid := ksuid.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `users` (`uid`,`email`) VALUES (?,?)").
+			WithArgs(uid.String(), "a@gg.io").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("SELECT LAST_INSERT_ID()").
+			WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(1))
+		mock.ExpectQuery(selectStmt + " WHERE `id` = ?").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(cols).
+				AddRow("1", uid.String(), "a@gg.io", "", 0,
+					"", nil, `[]`, nil, time.Now()))
+		mock.ExpectCommit()
+
+		usr, err := repo.CreateReturning(ctx, repository.NewCreator().
+			UID(uid).Email("a@gg.io"))
+		require.NoError(t, err)
+		assert.Equal(t, "1", usr.ID)
+		assert.NotNil(t, usr.CreatedAt)
+	})
+
+	t.Run("UpdateReturning", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		uid := ksuid.New().String()
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectStmt + " WHERE `age` > ? FOR UPDATE").
+			WithArgs(18).
+			WillReturnRows(sqlmock.NewRows(cols).
+				AddRow("1", uid, "a@gg.io", "a", 20, "human", nil, `[]`, nil, nil))
+		mock.ExpectExec("UPDATE `users` SET `name` = ? WHERE `age` > ?").
+			WithArgs("b", 18).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectStmt + " WHERE (`id` = ?)").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(cols).
+				AddRow("1", uid, "a@gg.io", "b", 20, "human", nil, `[]`, nil, nil))
+		mock.ExpectCommit()
+
+		usrs, err := repo.UpdateReturning(ctx, repository.NewUpdater().
+			Name("b").Where(repository.AgeGt(18)))
+		require.NoError(t, err)
+		require.Len(t, usrs, 1)
+		assert.Equal(t, "b", usrs[0].Name)
+	})
+
+	t.Run("DeleteReturning", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectStmt + " WHERE `id` = ? FOR UPDATE").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(cols).
+				AddRow("1", ksuid.New().String(), "a@gg.io", "a", 20, "human", nil, `[]`, nil, nil))
+		mock.ExpectExec("DELETE FROM `users` WHERE `id` = ?").
+			WithArgs("1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		usrs, err := repo.DeleteReturning(ctx, repository.NewDeleter().
+			Where(repository.IDEq("1")))
+		require.NoError(t, err)
+		require.Len(t, usrs, 1)
+		assert.Equal(t, "a", usrs[0].Name)
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery("SELECT AVG(`age`) avg_age, COUNT(`age`) count_age, `group`" +
+			" FROM `users` WHERE `age` > ? GROUP BY `group` ORDER BY `group` ASC").
+			WithArgs(18).
+			WillReturnRows(sqlmock.NewRows([]string{"avg_age", "count_age", "group"}).
+				AddRow(20.5, 2, "human"))
+
+		type aggt struct {
+			AvgAge   float64
+			CountAge int64
+			Group    string
+		}
+		agg := []aggt{}
+		err := repo.Aggregate(ctx, repository.NewAggregator(&agg).
+			Aggregate(
+				repository.Avg(repository.ColumnAge),
+				repository.Count(repository.ColumnAge),
+				repository.None(repository.ColumnGroup),
+			).
+			Where(repository.AgeGt(18)).
+			Group(repository.ColumnGroup).
+			Sort(repository.Asc(repository.ColumnGroup)))
+		require.NoError(t, err)
+		require.Len(t, agg, 1)
+		assert.Equal(t, aggt{AvgAge: 20.5, CountAge: 2, Group: "human"}, agg[0])
+	})
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
-	"github.com/segmentio/ksuid"
 	"github.com/sf9v/nero"
 	"github.com/sf9v/nero/aggregate"
 	"github.com/sf9v/nero/comparison"
@@ -73,130 +72,405 @@ func (pg *PostgresRepository) CreateTx(ctx context.Context, tx nero.Tx, c *Creat
 }
 
 func (pg *PostgresRepository) create(ctx context.Context, runner nero.SQLRunner, c *Creator) (string, error) {
+	columns, values := pg.createValues(c)
+	qb := squirrel.Insert("\"users\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING \"id\"").
+		PlaceholderFormat(squirrel.Dollar).
+		RunWith(runner)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var id string
+	err := qb.QueryRowContext(ctx).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// CreateReturning creates a new User and returns the created row
+func (pg *PostgresRepository) CreateReturning(ctx context.Context, c *Creator) (*user.User, error) {
+	return pg.createReturning(ctx, pg.db, c)
+}
+
+// CreateReturningTx creates a new User inside a transaction and returns the created row
+func (pg *PostgresRepository) CreateReturningTx(ctx context.Context, tx nero.Tx, c *Creator) (*user.User, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.createReturning(ctx, txx, c)
+}
+
+func (pg *PostgresRepository) createReturning(ctx context.Context, runner nero.SQLRunner, c *Creator) (*user.User, error) {
+	columns, values := pg.createValues(c)
+	qb := squirrel.Insert("\"users\"").
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING \"id\", \"uid\", \"email\", \"name\", \"age\", \"group\", \"kv\", \"tags\", \"updated_at\", \"created_at\"").
+		PlaceholderFormat(squirrel.Dollar).
+		RunWith(runner)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: CreateReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	return pg.scanColumns(qb.QueryRowContext(ctx), allColumns())
+}
+
+// CreateMany creates many User
+func (pg *PostgresRepository) CreateMany(ctx context.Context, cs ...*Creator) ([]string, error) {
+	if len(cs) <= 1 {
+		return pg.createMany(ctx, pg.db, cs...)
+	}
+
+	// the rows are inserted in a single transaction
+	// so that nothing is inserted if one of them fails
+	tx, err := pg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	idents, err := pg.createMany(ctx, tx, cs...)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return idents, nil
+}
+
+// CreateManyTx creates many User inside a transaction
+func (pg *PostgresRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) ([]string, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.createMany(ctx, txx, cs...)
+}
+
+func (pg *PostgresRepository) createMany(ctx context.Context, runner nero.SQLRunner, cs ...*Creator) ([]string, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	idents := make([]string, 0, len(cs))
+	// the rows are inserted one by one since the order of the rows
+	// returned by a multiple-row insert is not guaranteed, so the
+	// returned identities can't be matched to the creators
+	for _, c := range cs {
+		ident, err := pg.create(ctx, runner, c)
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+	}
+
+	return idents, nil
+}
+
+// createValues returns the set columns and values of the creator
+func (pg *PostgresRepository) createValues(c *Creator) ([]string, []interface{}) {
 	columns := []string{}
 	values := []interface{}{}
 
-	if c.uid != (ksuid.KSUID{}) {
+	if c.IsSet(ColumnUID) {
 		columns = append(columns, "\"uid\"")
 		values = append(values, c.uid)
 	}
 
-	if c.email != "" {
+	if c.IsSet(ColumnEmail) {
 		columns = append(columns, "\"email\"")
 		values = append(values, c.email)
 	}
 
-	if c.name != "" {
+	if c.IsSet(ColumnName) {
 		columns = append(columns, "\"name\"")
 		values = append(values, c.name)
 	}
 
-	if c.age != 0 {
+	if c.IsSet(ColumnAge) {
 		columns = append(columns, "\"age\"")
 		values = append(values, c.age)
 	}
 
-	if c.group != "" {
+	if c.IsSet(ColumnGroup) {
 		columns = append(columns, "\"group\"")
 		values = append(values, c.group)
 	}
 
-	if c.kv != nil {
+	if c.IsSet(ColumnKv) {
 		columns = append(columns, "\"kv\"")
 		values = append(values, c.kv)
 	}
 
-	if c.tags != nil {
+	if c.IsSet(ColumnTags) {
 		columns = append(columns, "\"tags\"")
 		values = append(values, pq.Array(c.tags))
 	}
 
-	if c.updatedAt != nil {
+	if c.IsSet(ColumnUpdatedAt) {
 		columns = append(columns, "\"updated_at\"")
-		values = append(values, c.updatedAt)
+		if c.IsNull(ColumnUpdatedAt) {
+			values = append(values, nil)
+		} else {
+			values = append(values, c.updatedAt)
+		}
+	}
+
+	return columns, values
+}
+
+// BulkLoad creates many User using the COPY protocol
+func (pg *PostgresRepository) BulkLoad(ctx context.Context, cs ...*Creator) error {
+	err := pg.validateBulkLoad(cs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := pg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = pg.bulkLoad(ctx, tx, cs...)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+// BulkLoadTx creates many User using the COPY protocol inside a transaction
+func (pg *PostgresRepository) BulkLoadTx(ctx context.Context, tx nero.Tx, cs ...*Creator) error {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return errors.New("expecting tx to be *sql.Tx")
+	}
+
+	err := pg.validateBulkLoad(cs)
+	if err != nil {
+		return err
+	}
+
+	return pg.bulkLoad(ctx, txx, cs...)
+}
+
+// validateBulkLoad checks that every creator sets a column since the
+// columns of the COPY statement are the columns set by the creators
+func (pg *PostgresRepository) validateBulkLoad(cs []*Creator) error {
+	for i, c := range cs {
+		if len(c.Columns()) == 0 {
+			return errors.Errorf("creator %d of %d doesn't set any column", i+1, len(cs))
+		}
+	}
+
+	return nil
+}
+
+func (pg *PostgresRepository) bulkLoad(ctx context.Context, tx *sql.Tx, cs ...*Creator) error {
+	// the creators that set the same columns are copied together,
+	// the columns that are not set get their default values
+	for _, group := range groupCreators(cs) {
+		err := pg.copyIn(ctx, tx, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyIn copies the creators that set the same columns
+func (pg *PostgresRepository) copyIn(ctx context.Context, tx *sql.Tx, cs []*Creator) error {
+	columns := []string{}
+	for _, col := range cs[0].Columns() {
+		columns = append(columns, col.String())
 	}
 
+	stmt := pq.CopyIn("users", columns...)
+	if pg.debug {
+		pg.logger.Printf("method: BulkLoad, stmt: %q, rows: %d", stmt, len(cs))
+	}
+
+	copyStmt, err := tx.PrepareContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	defer copyStmt.Close()
+
+	for _, c := range cs {
+		_, values := pg.createValues(c)
+		_, err = copyStmt.ExecContext(ctx, values...)
+		if err != nil {
+			return err
+		}
+	}
+
+	// flush the buffered rows
+	_, err = copyStmt.ExecContext(ctx)
+	return err
+}
+
+// Upsert creates a new User or updates the conflicting one
+func (pg *PostgresRepository) Upsert(ctx context.Context, u *Upserter) (string, error) {
+	return pg.upsert(ctx, pg.db, u)
+}
+
+// UpsertTx creates a new User or updates the conflicting one inside a transaction
+func (pg *PostgresRepository) UpsertTx(ctx context.Context, tx nero.Tx, u *Upserter) (string, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return "", errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.upsert(ctx, txx, u)
+}
+
+func (pg *PostgresRepository) upsert(ctx context.Context, runner nero.SQLRunner, u *Upserter) (string, error) {
+	if err := u.validate(true); err != nil {
+		return "", err
+	}
+
+	columns, values := pg.createValues(u.Creators()[0])
 	qb := squirrel.Insert("\"users\"").
 		Columns(columns...).
 		Values(values...).
-		Suffix("RETURNING \"id\"").
+		Suffix(pg.onConflict(u) + " RETURNING \"id\"").
 		PlaceholderFormat(squirrel.Dollar).
 		RunWith(runner)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: Create, stmt: %q, args: %v, error: %v", sql, args, err)
+		pg.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
-	var id string
-	err := qb.QueryRowContext(ctx).Scan(&id)
+	var ident string
+	err := qb.QueryRowContext(ctx).Scan(
+		&ident,
+	)
+	if err == sql.ErrNoRows {
+		// nothing is returned if the conflicting row was left as it is
+		return pg.conflictIdent(ctx, runner, u, columns, values)
+	}
 	if err != nil {
 		return "", err
 	}
 
-	return id, nil
+	return ident, nil
 }
 
-// CreateMany creates many User
-func (pg *PostgresRepository) CreateMany(ctx context.Context, cs ...*Creator) error {
-	return pg.createMany(ctx, pg.db, cs...)
+// UpsertMany creates many User or updates the conflicting ones
+func (pg *PostgresRepository) UpsertMany(ctx context.Context, u *Upserter) error {
+	return pg.upsertMany(ctx, pg.db, u)
 }
 
-// CreateManyTx creates many User inside a transaction
-func (pg *PostgresRepository) CreateManyTx(ctx context.Context, tx nero.Tx, cs ...*Creator) error {
+// UpsertManyTx creates many User or updates the conflicting ones inside a transaction
+func (pg *PostgresRepository) UpsertManyTx(ctx context.Context, tx nero.Tx, u *Upserter) error {
 	txx, ok := tx.(*sql.Tx)
 	if !ok {
 		return errors.New("expecting tx to be *sql.Tx")
 	}
 
-	return pg.createMany(ctx, txx, cs...)
+	return pg.upsertMany(ctx, txx, u)
 }
 
-func (pg *PostgresRepository) createMany(ctx context.Context, runner nero.SQLRunner, cs ...*Creator) error {
-	if len(cs) == 0 {
+func (pg *PostgresRepository) upsertMany(ctx context.Context, runner nero.SQLRunner, u *Upserter) error {
+	if len(u.Creators()) == 0 {
 		return nil
 	}
 
-	columns := []string{
-		"\"uid\"",
-		"\"email\"",
-		"\"name\"",
-		"\"age\"",
-		"\"group\"",
-		"\"kv\"",
-		"\"tags\"",
-		"\"updated_at\"",
+	if err := u.validate(false); err != nil {
+		return err
 	}
+
+	columns, _ := pg.createValues(u.Creators()[0])
 	qb := squirrel.Insert("\"users\"").Columns(columns...)
-	for _, c := range cs {
-		qb = qb.Values(
-			c.uid,
-			c.email,
-			c.name,
-			c.age,
-			c.group,
-			c.kv,
-			pq.Array(c.tags),
-			c.updatedAt,
-		)
-	}
-
-	qb = qb.Suffix("RETURNING \"id\"").
+	for _, c := range u.Creators() {
+		_, values := pg.createValues(c)
+		qb = qb.Values(values...)
+	}
+
+	qb = qb.Suffix(pg.onConflict(u)).
 		PlaceholderFormat(squirrel.Dollar)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: CreateMany, stmt: %q, args: %v, error: %v", sql, args, err)
+		pg.logger.Printf("method: UpsertMany, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
 	_, err := qb.RunWith(runner).ExecContext(ctx)
+	return err
+}
+
+// onConflict builds the 'ON CONFLICT' clause of the upsert
+func (pg *PostgresRepository) onConflict(u *Upserter) string {
+	target := []string{}
+	for _, col := range u.ConflictColumns() {
+		target = append(target, fmt.Sprintf("%q", col))
+	}
+
+	updates := []string{}
+	for _, col := range u.UpdateColumns() {
+		updates = append(updates, fmt.Sprintf("%q = EXCLUDED.%q", col, col))
+	}
+
+	clause := "ON CONFLICT (" + strings.Join(target, ", ") + ")"
+	if len(updates) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	return clause + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// conflictIdent looks-up the identity of the conflicting row
+func (pg *PostgresRepository) conflictIdent(ctx context.Context, runner nero.SQLRunner, u *Upserter, columns []string, values []interface{}) (string, error) {
+	eq := squirrel.Eq{}
+	for _, col := range u.ConflictColumns() {
+		name := fmt.Sprintf("%q", col)
+		for i, column := range columns {
+			if column == name {
+				eq[name] = values[i]
+			}
+		}
+	}
+
+	qb := squirrel.Select("\"id\"").
+		From("\"users\"").
+		Where(eq).
+		PlaceholderFormat(squirrel.Dollar).
+		RunWith(runner)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Upsert, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var ident string
+	err := qb.QueryRowContext(ctx).Scan(
+		&ident,
+	)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return ident, nil
 }
 
 // Query queries many User
 func (pg *PostgresRepository) Query(ctx context.Context, q *Queryer) ([]*user.User, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
 	return pg.query(ctx, pg.db, q)
 }
 
@@ -211,6 +485,11 @@ func (pg *PostgresRepository) QueryTx(ctx context.Context, tx nero.Tx, q *Querye
 }
 
 func (pg *PostgresRepository) query(ctx context.Context, runner nero.SQLRunner, q *Queryer) ([]*user.User, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
 	qb := pg.buildSelect(q)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
@@ -225,24 +504,16 @@ func (pg *PostgresRepository) query(ctx context.Context, runner nero.SQLRunner,
 
 	users := []*user.User{}
 	for rows.Next() {
-		var user user.User
-		err = rows.Scan(
-			&user.ID,
-			&user.UID,
-			&user.Email,
-			&user.Name,
-			&user.Age,
-			&user.Group,
-			&user.Kv,
-			pq.Array(&user.Tags),
-			&user.UpdatedAt,
-			&user.CreatedAt,
-		)
+		user, err := pg.scanColumns(rows, q.columns())
 		if err != nil {
 			return nil, err
 		}
 
-		users = append(users, &user)
+		users = append(users, user)
+	}
+
+	if q.reversed {
+		reverseRows(users)
 	}
 
 	return users, nil
@@ -250,6 +521,10 @@ func (pg *PostgresRepository) query(ctx context.Context, runner nero.SQLRunner,
 
 // QueryOne queries one User
 func (pg *PostgresRepository) QueryOne(ctx context.Context, q *Queryer) (*user.User, error) {
+	if q.lock != nero.NoLock {
+		return nil, nero.ErrLockOutsideTx
+	}
+
 	return pg.queryOne(ctx, pg.db, q)
 }
 
@@ -263,28 +538,170 @@ func (pg *PostgresRepository) QueryOneTx(ctx context.Context, tx nero.Tx, q *Que
 	return pg.queryOne(ctx, txx, q)
 }
 
+// QueryPage queries a page of User along with the cursors of the adjacent pages
+func (pg *PostgresRepository) QueryPage(ctx context.Context, q *Queryer) (*Page, error) {
+	rows, err := pg.Query(ctx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// QueryPageTx queries a page of User inside a transaction along with the cursors of the adjacent pages
+func (pg *PostgresRepository) QueryPageTx(ctx context.Context, tx nero.Tx, q *Queryer) (*Page, error) {
+	rows, err := pg.QueryTx(ctx, tx, q.page())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(q, rows)
+}
+
+// Count counts the User rows that match the predicates of the query,
+// the sorts, limit, offset and cursors are ignored
+func (pg *PostgresRepository) Count(ctx context.Context, q *Queryer) (int64, error) {
+	if q.lock != nero.NoLock {
+		return 0, nero.ErrLockOutsideTx
+	}
+
+	return pg.count(ctx, pg.db, q)
+}
+
+// CountTx counts the User rows that match the predicates of the query inside a transaction
+func (pg *PostgresRepository) CountTx(ctx context.Context, tx nero.Tx, q *Queryer) (int64, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.count(ctx, txx, q)
+}
+
+func (pg *PostgresRepository) count(ctx context.Context, runner nero.SQLRunner, q *Queryer) (int64, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return 0, &nero.UnsupportedError{Op: "row locking in Count", Repository: "PostgresRepository"}
+	}
+
+	qb := squirrel.Select("COUNT(*)").
+		From("\"users\"").
+		PlaceholderFormat(squirrel.Dollar)
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Count, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var count int64
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&count)
+	return count, err
+}
+
+// Exists returns true if any of the User rows matches the predicates of the query
+func (pg *PostgresRepository) Exists(ctx context.Context, q *Queryer) (bool, error) {
+	if q.lock != nero.NoLock {
+		return false, nero.ErrLockOutsideTx
+	}
+
+	return pg.exists(ctx, pg.db, q)
+}
+
+// ExistsTx returns true if any of the User rows matches the predicates of the query inside a transaction
+func (pg *PostgresRepository) ExistsTx(ctx context.Context, tx nero.Tx, q *Queryer) (bool, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return false, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.exists(ctx, txx, q)
+}
+
+func (pg *PostgresRepository) exists(ctx context.Context, runner nero.SQLRunner, q *Queryer) (bool, error) {
+	// the rows are not selected, so they can't be locked
+	if q.lock != nero.NoLock {
+		return false, &nero.UnsupportedError{Op: "row locking in Exists", Repository: "PostgresRepository"}
+	}
+
+	sub := squirrel.Select("1").From("\"users\"")
+	pb := &comparison.Predicates{}
+	for _, pf := range q.pfs {
+		pf(pb)
+	}
+	for _, cond := range pg.conds(pb.All()) {
+		sub = sub.Where(cond)
+	}
+	qb := squirrel.Select().
+		Column(squirrel.Expr("EXISTS(?)", sub)).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Exists, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	var exists bool
+	err := qb.RunWith(runner).QueryRowContext(ctx).Scan(&exists)
+	return exists, err
+}
+
 func (pg *PostgresRepository) queryOne(ctx context.Context, runner nero.SQLRunner, q *Queryer) (*user.User, error) {
+	q, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+
 	qb := pg.buildSelect(q)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
 		pg.logger.Printf("method: QueryOne, stmt: %q, args: %v, error: %v", sql, args, err)
 	}
 
+	user, err := pg.scanColumns(qb.RunWith(runner).QueryRowContext(ctx), q.columns())
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// scanColumns scans a row of the columns into User
+func (pg *PostgresRepository) scanColumns(scanner interface{ Scan(...interface{}) error }, cols []Column) (*user.User, error) {
 	var user user.User
-	err := qb.RunWith(runner).
-		QueryRowContext(ctx).
-		Scan(
-			&user.ID,
-			&user.UID,
-			&user.Email,
-			&user.Name,
-			&user.Age,
-			&user.Group,
-			&user.Kv,
-			pq.Array(&user.Tags),
-			&user.UpdatedAt,
-			&user.CreatedAt,
-		)
+	dest := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		switch col {
+		case ColumnID:
+			dest = append(dest, &user.ID)
+		case ColumnUID:
+			dest = append(dest, &user.UID)
+		case ColumnEmail:
+			dest = append(dest, &user.Email)
+		case ColumnName:
+			dest = append(dest, &user.Name)
+		case ColumnAge:
+			dest = append(dest, &user.Age)
+		case ColumnGroup:
+			dest = append(dest, &user.Group)
+		case ColumnKv:
+			dest = append(dest, &user.Kv)
+		case ColumnTags:
+			dest = append(dest, pq.Array(&user.Tags))
+		case ColumnUpdatedAt:
+			dest = append(dest, &user.UpdatedAt)
+		case ColumnCreatedAt:
+			dest = append(dest, &user.CreatedAt)
+		}
+	}
+
+	err := scanner.Scan(dest...)
 	if err != nil {
 		return nil, err
 	}
@@ -292,18 +709,26 @@ func (pg *PostgresRepository) queryOne(ctx context.Context, runner nero.SQLRunne
 	return &user, nil
 }
 
+// scanAll scans the rows into User and closes them
+func (pg *PostgresRepository) scanAll(rows *sql.Rows) ([]*user.User, error) {
+	defer rows.Close()
+
+	users := []*user.User{}
+	for rows.Next() {
+		user, err := pg.scanColumns(rows, allColumns())
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
 func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
-	columns := []string{
-		"\"id\"",
-		"\"uid\"",
-		"\"email\"",
-		"\"name\"",
-		"\"age\"",
-		"\"group\"",
-		"\"kv\"",
-		"\"tags\"",
-		"\"updated_at\"",
-		"\"created_at\"",
+	columns := []string{}
+	for _, col := range q.columns() {
+		columns = append(columns, fmt.Sprintf("%q", col.String()))
 	}
 	qb := squirrel.Select(columns...).
 		From("\"users\"").
@@ -314,71 +739,8 @@ func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
 	for _, pf := range pfs {
 		pf(pb)
 	}
-
-	for _, p := range pb.All() {
-		switch p.Op {
-		case comparison.Eq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q = %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q = ?", p.Col), p.Arg)
-			}
-		case comparison.NotEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <> %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <> ?", p.Col), p.Arg)
-			}
-		case comparison.Gt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q > %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q > ?", p.Col), p.Arg)
-			}
-		case comparison.GtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q >= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q >= ?", p.Col), p.Arg)
-			}
-		case comparison.Lt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q < %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q < ?", p.Col), p.Arg)
-			}
-		case comparison.LtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <= ?", p.Col), p.Arg)
-			}
-		case comparison.IsNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NULL", p.Col))
-		case comparison.IsNotNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NOT NULL", p.Col))
-		case comparison.In, comparison.NotIn:
-			args := p.Arg.([]interface{})
-			if len(args) == 0 {
-				continue
-			}
-			qms := []string{}
-			for range args {
-				qms = append(qms, "?")
-			}
-			fmtStr := "%q IN (%s)"
-			if p.Op == comparison.NotIn {
-				fmtStr = "%q NOT IN (%s)"
-			}
-			plchldr := strings.Join(qms, ",")
-			qb = qb.Where(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
-		}
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
 	}
 
 	sfs := q.sfs
@@ -387,6 +749,10 @@ func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
 		sf(sorts)
 	}
 	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(pg.rank(s))
+			continue
+		}
 		col := fmt.Sprintf("%q", s.Col)
 		switch s.Direction {
 		case sort.Asc:
@@ -404,6 +770,10 @@ func (pg *PostgresRepository) buildSelect(q *Queryer) squirrel.SelectBuilder {
 		qb = qb.Offset(uint64(q.offset))
 	}
 
+	if q.lock != nero.NoLock {
+		qb = qb.SuffixExpr(pg.lock(q))
+	}
+
 	return qb
 }
 
@@ -423,39 +793,106 @@ func (pg *PostgresRepository) UpdateTx(ctx context.Context, tx nero.Tx, u *Updat
 }
 
 func (pg *PostgresRepository) update(ctx context.Context, runner nero.SQLRunner, u *Updater) (int64, error) {
+	if err := u.Guard(); err != nil {
+		return 0, err
+	}
+
+	qb := pg.buildUpdate(u)
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	res, err := qb.RunWith(runner).ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
+
+// UpdateReturning updates User and returns the updated rows
+func (pg *PostgresRepository) UpdateReturning(ctx context.Context, u *Updater) ([]*user.User, error) {
+	return pg.updateReturning(ctx, pg.db, u)
+}
+
+// UpdateReturningTx updates User inside a transaction and returns the updated rows
+func (pg *PostgresRepository) UpdateReturningTx(ctx context.Context, tx nero.Tx, u *Updater) ([]*user.User, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.updateReturning(ctx, txx, u)
+}
+
+func (pg *PostgresRepository) updateReturning(ctx context.Context, runner nero.SQLRunner, u *Updater) ([]*user.User, error) {
+	if err := u.Guard(); err != nil {
+		return nil, err
+	}
+
+	qb := pg.buildUpdate(u).Suffix("RETURNING \"id\", \"uid\", \"email\", \"name\", \"age\", \"group\", \"kv\", \"tags\", \"updated_at\", \"created_at\"")
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: UpdateReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pg.scanAll(rows)
+}
+
+// buildUpdate builds the update statement
+func (pg *PostgresRepository) buildUpdate(u *Updater) squirrel.UpdateBuilder {
 	qb := squirrel.Update("\"users\"").
 		PlaceholderFormat(squirrel.Dollar)
 
-	if u.uid != (ksuid.KSUID{}) {
+	if u.IsSet(ColumnUID) {
 		qb = qb.Set("\"uid\"", u.uid)
 	}
 
-	if u.email != "" {
+	if u.IsSet(ColumnEmail) {
 		qb = qb.Set("\"email\"", u.email)
 	}
 
-	if u.name != "" {
+	if u.IsSet(ColumnName) {
 		qb = qb.Set("\"name\"", u.name)
 	}
 
-	if u.age != 0 {
+	if u.IsSet(ColumnAge) {
 		qb = qb.Set("\"age\"", u.age)
 	}
 
-	if u.group != "" {
+	if u.IsSet(ColumnGroup) {
 		qb = qb.Set("\"group\"", u.group)
 	}
 
-	if u.kv != nil {
+	if u.IsSet(ColumnKv) {
 		qb = qb.Set("\"kv\"", u.kv)
 	}
 
-	if u.tags != nil {
+	if u.IsSet(ColumnTags) {
 		qb = qb.Set("\"tags\"", pq.Array(u.tags))
 	}
 
-	if u.updatedAt != nil {
-		qb = qb.Set("\"updated_at\"", u.updatedAt)
+	if u.IsSet(ColumnUpdatedAt) {
+		if u.IsNull(ColumnUpdatedAt) {
+			qb = qb.Set("\"updated_at\"", nil)
+		} else {
+			qb = qb.Set("\"updated_at\"", u.updatedAt)
+		}
+	}
+
+	for _, e := range u.exprs {
+		qb = qb.Set(fmt.Sprintf("%q", e.col.String()), pg.updateExpr(e))
 	}
 
 	pfs := u.pfs
@@ -463,89 +900,22 @@ func (pg *PostgresRepository) update(ctx context.Context, runner nero.SQLRunner,
 	for _, pf := range pfs {
 		pf(pb)
 	}
-
-	for _, p := range pb.All() {
-		switch p.Op {
-		case comparison.Eq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q = %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q = ?", p.Col), p.Arg)
-			}
-		case comparison.NotEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <> %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <> ?", p.Col), p.Arg)
-			}
-		case comparison.Gt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q > %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q > ?", p.Col), p.Arg)
-			}
-		case comparison.GtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q >= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q >= ?", p.Col), p.Arg)
-			}
-		case comparison.Lt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q < %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q < ?", p.Col), p.Arg)
-			}
-		case comparison.LtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <= ?", p.Col), p.Arg)
-			}
-		case comparison.IsNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NULL", p.Col))
-		case comparison.IsNotNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NOT NULL", p.Col))
-		case comparison.In, comparison.NotIn:
-			args := p.Arg.([]interface{})
-			if len(args) == 0 {
-				continue
-			}
-			qms := []string{}
-			for range args {
-				qms = append(qms, "?")
-			}
-			fmtStr := "%q IN (%s)"
-			if p.Op == comparison.NotIn {
-				fmtStr = "%q NOT IN (%s)"
-			}
-			plchldr := strings.Join(qms, ",")
-			qb = qb.Where(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
-		}
-	}
-
-	if pg.debug {
-		sql, args, err := qb.ToSql()
-		pg.logger.Printf("method: Update, stmt: %q, args: %v, error: %v", sql, args, err)
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
 	}
 
-	res, err := qb.RunWith(runner).ExecContext(ctx)
-	if err != nil {
-		return 0, err
-	}
+	return qb
+}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return 0, err
+// updateExpr builds the value of an update expression
+func (pg *PostgresRepository) updateExpr(e *updateExpr) squirrel.Sqlizer {
+	switch e.op {
+	case updateIncr:
+		return squirrel.Expr(fmt.Sprintf("%q + ?", e.col.String()), e.arg)
+	case updateDecr:
+		return squirrel.Expr(fmt.Sprintf("%q - ?", e.col.String()), e.arg)
 	}
-
-	return rowsAffected, nil
+	return squirrel.Expr(fmt.Sprintf("%q", e.arg.(Column).String()))
 }
 
 // Delete deletes User
@@ -564,81 +934,11 @@ func (pg *PostgresRepository) DeleteTx(ctx context.Context, tx nero.Tx, d *Delet
 }
 
 func (pg *PostgresRepository) delete(ctx context.Context, runner nero.SQLRunner, d *Deleter) (int64, error) {
-	qb := squirrel.Delete("\"users\"").
-		PlaceholderFormat(squirrel.Dollar)
-
-	pfs := d.pfs
-	pb := &comparison.Predicates{}
-	for _, pf := range pfs {
-		pf(pb)
-	}
-
-	for _, p := range pb.All() {
-		switch p.Op {
-		case comparison.Eq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q = %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q = ?", p.Col), p.Arg)
-			}
-		case comparison.NotEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <> %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <> ?", p.Col), p.Arg)
-			}
-		case comparison.Gt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q > %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q > ?", p.Col), p.Arg)
-			}
-		case comparison.GtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q >= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q >= ?", p.Col), p.Arg)
-			}
-		case comparison.Lt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q < %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q < ?", p.Col), p.Arg)
-			}
-		case comparison.LtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <= ?", p.Col), p.Arg)
-			}
-		case comparison.IsNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NULL", p.Col))
-		case comparison.IsNotNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NOT NULL", p.Col))
-		case comparison.In, comparison.NotIn:
-			args := p.Arg.([]interface{})
-			if len(args) == 0 {
-				continue
-			}
-			qms := []string{}
-			for range args {
-				qms = append(qms, "?")
-			}
-			fmtStr := "%q IN (%s)"
-			if p.Op == comparison.NotIn {
-				fmtStr = "%q NOT IN (%s)"
-			}
-			plchldr := strings.Join(qms, ",")
-			qb = qb.Where(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
-		}
+	if err := d.Guard(); err != nil {
+		return 0, err
 	}
 
+	qb := pg.buildDelete(d)
 	if pg.debug {
 		sql, args, err := qb.ToSql()
 		pg.logger.Printf("method: Delete, stmt: %q, args: %v, error: %v", sql, args, err)
@@ -657,6 +957,57 @@ func (pg *PostgresRepository) delete(ctx context.Context, runner nero.SQLRunner,
 	return rowsAffected, nil
 }
 
+// DeleteReturning deletes User and returns the deleted rows
+func (pg *PostgresRepository) DeleteReturning(ctx context.Context, d *Deleter) ([]*user.User, error) {
+	return pg.deleteReturning(ctx, pg.db, d)
+}
+
+// DeleteReturningTx deletes User inside a transaction and returns the deleted rows
+func (pg *PostgresRepository) DeleteReturningTx(ctx context.Context, tx nero.Tx, d *Deleter) ([]*user.User, error) {
+	txx, ok := tx.(*sql.Tx)
+	if !ok {
+		return nil, errors.New("expecting tx to be *sql.Tx")
+	}
+
+	return pg.deleteReturning(ctx, txx, d)
+}
+
+func (pg *PostgresRepository) deleteReturning(ctx context.Context, runner nero.SQLRunner, d *Deleter) ([]*user.User, error) {
+	if err := d.Guard(); err != nil {
+		return nil, err
+	}
+
+	qb := pg.buildDelete(d).Suffix("RETURNING \"id\", \"uid\", \"email\", \"name\", \"age\", \"group\", \"kv\", \"tags\", \"updated_at\", \"created_at\"")
+	if pg.debug {
+		sql, args, err := qb.ToSql()
+		pg.logger.Printf("method: DeleteReturning, stmt: %q, args: %v, error: %v", sql, args, err)
+	}
+
+	rows, err := qb.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pg.scanAll(rows)
+}
+
+// buildDelete builds the delete statement
+func (pg *PostgresRepository) buildDelete(d *Deleter) squirrel.DeleteBuilder {
+	qb := squirrel.Delete("\"users\"").
+		PlaceholderFormat(squirrel.Dollar)
+
+	pfs := d.pfs
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
+	}
+
+	return qb
+}
+
 // Aggregate runs aggregate operations
 func (pg *PostgresRepository) Aggregate(ctx context.Context, a *Aggregator) error {
 	return pg.aggregate(ctx, pg.db, a)
@@ -711,71 +1062,8 @@ func (pg *PostgresRepository) aggregate(ctx context.Context, runner nero.SQLRunn
 	for _, pf := range pfs {
 		pf(pb)
 	}
-
-	for _, p := range pb.All() {
-		switch p.Op {
-		case comparison.Eq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q = %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q = ?", p.Col), p.Arg)
-			}
-		case comparison.NotEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <> %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <> ?", p.Col), p.Arg)
-			}
-		case comparison.Gt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q > %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q > ?", p.Col), p.Arg)
-			}
-		case comparison.GtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q >= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q >= ?", p.Col), p.Arg)
-			}
-		case comparison.Lt:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q < %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q < ?", p.Col), p.Arg)
-			}
-		case comparison.LtOrEq:
-			col, ok := p.Arg.(Column)
-			if ok {
-				qb = qb.Where(fmt.Sprintf("%q <= %q", p.Col, col.String()))
-			} else {
-				qb = qb.Where(fmt.Sprintf("%q <= ?", p.Col), p.Arg)
-			}
-		case comparison.IsNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NULL", p.Col))
-		case comparison.IsNotNull:
-			qb = qb.Where(fmt.Sprintf("%q IS NOT NULL", p.Col))
-		case comparison.In, comparison.NotIn:
-			args := p.Arg.([]interface{})
-			if len(args) == 0 {
-				continue
-			}
-			qms := []string{}
-			for range args {
-				qms = append(qms, "?")
-			}
-			fmtStr := "%q IN (%s)"
-			if p.Op == comparison.NotIn {
-				fmtStr = "%q NOT IN (%s)"
-			}
-			plchldr := strings.Join(qms, ",")
-			qb = qb.Where(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
-		}
+	for _, cond := range pg.conds(pb.All()) {
+		qb = qb.Where(cond)
 	}
 
 	sfs := a.sfs
@@ -784,6 +1072,10 @@ func (pg *PostgresRepository) aggregate(ctx context.Context, runner nero.SQLRunn
 		sf(sorts)
 	}
 	for _, s := range sorts.All() {
+		if s.Rank != nil {
+			qb = qb.OrderByClause(pg.rank(s))
+			continue
+		}
 		col := fmt.Sprintf("%q", s.Col)
 		switch s.Direction {
 		case sort.Asc:
@@ -827,3 +1119,162 @@ func (pg *PostgresRepository) aggregate(ctx context.Context, runner nero.SQLRunn
 
 	return nil
 }
+
+// conds builds the conditions of the predicates
+func (pg *PostgresRepository) conds(preds []*comparison.Predicate) []squirrel.Sqlizer {
+	conds := []squirrel.Sqlizer{}
+	for _, p := range preds {
+		if cond := pg.cond(p); cond != nil {
+			conds = append(conds, cond)
+		}
+	}
+	return conds
+}
+
+// cond builds the condition of the predicate, nil means no condition
+func (pg *PostgresRepository) cond(p *comparison.Predicate) squirrel.Sqlizer {
+	switch p.Op {
+	case comparison.And, comparison.Not:
+		conds := pg.conds(p.Group())
+		if p.Op == comparison.Not {
+			return squirrel.Expr("NOT ?", squirrel.And(conds))
+		}
+		if len(conds) == 0 {
+			return nil
+		}
+		return squirrel.And(conds)
+	case comparison.Or:
+		or := squirrel.Or{}
+		for _, pred := range p.Group() {
+			cond := pg.cond(pred)
+			// a nil condition e.g. an empty "in" matches everything
+			if cond == nil {
+				return nil
+			}
+			or = append(or, cond)
+		}
+		return or
+	case comparison.Eq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("%q = %q", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("%q = ?", p.Col), p.Arg)
+	case comparison.NotEq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("%q <> %q", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("%q <> ?", p.Col), p.Arg)
+	case comparison.Gt:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("%q > %q", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("%q > ?", p.Col), p.Arg)
+	case comparison.GtOrEq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("%q >= %q", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("%q >= ?", p.Col), p.Arg)
+	case comparison.Lt:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("%q < %q", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("%q < ?", p.Col), p.Arg)
+	case comparison.LtOrEq:
+		if col, ok := p.Arg.(Column); ok {
+			return squirrel.Expr(fmt.Sprintf("%q <= %q", p.Col, col.String()))
+		}
+		return squirrel.Expr(fmt.Sprintf("%q <= ?", p.Col), p.Arg)
+	case comparison.Like, comparison.NotLike, comparison.HasPrefix,
+		comparison.HasSuffix, comparison.Contains:
+		return pg.like(p)
+	case comparison.ILike:
+		return squirrel.Expr(fmt.Sprintf("%q ILIKE ? ESCAPE '\\'", p.Col), p.Arg)
+	case comparison.Matches:
+		return pg.matches(p)
+	case comparison.Between, comparison.NotBetween:
+		fmtStr := "%q BETWEEN ? AND ?"
+		if p.Op == comparison.NotBetween {
+			fmtStr = "%q NOT BETWEEN ? AND ?"
+		}
+		return squirrel.Expr(fmt.Sprintf(fmtStr, p.Col), p.Arg.([]interface{})...)
+	case comparison.IsNull:
+		return squirrel.Expr(fmt.Sprintf("%q IS NULL", p.Col))
+	case comparison.IsNotNull:
+		return squirrel.Expr(fmt.Sprintf("%q IS NOT NULL", p.Col))
+	case comparison.In, comparison.NotIn:
+		args := p.Arg.([]interface{})
+		if len(args) == 0 {
+			return nil
+		}
+		qms := []string{}
+		for range args {
+			qms = append(qms, "?")
+		}
+		fmtStr := "%q IN (%s)"
+		if p.Op == comparison.NotIn {
+			fmtStr = "%q NOT IN (%s)"
+		}
+		plchldr := strings.Join(qms, ",")
+		return squirrel.Expr(fmt.Sprintf(fmtStr, p.Col, plchldr), args...)
+	case comparison.Exists, comparison.NotExists:
+		edge := p.Arg.(*comparison.Edge)
+		fmtStr := "EXISTS (SELECT 1 FROM %q AS %q WHERE %q.%q = %q.%q)"
+		if p.Op == comparison.NotExists {
+			fmtStr = "NOT " + fmtStr
+		}
+		return squirrel.Expr(fmt.Sprintf(fmtStr, edge.Collection, "edge", "edge", edge.Col, "users", p.Col))
+	}
+	return nil
+}
+
+// like builds the case-sensitive condition of the string matching predicate
+func (pg *PostgresRepository) like(p *comparison.Predicate) squirrel.Sqlizer {
+	pattern := comparison.LikePattern(p.Op, p.Arg.(string))
+	cond := squirrel.Expr(fmt.Sprintf("%q LIKE ? ESCAPE '\\'", p.Col), pattern)
+	if p.Op == comparison.NotLike {
+		return squirrel.Expr("NOT (?)", cond)
+	}
+	return cond
+}
+
+// matches builds the full-text search condition of the predicate
+func (pg *PostgresRepository) matches(p *comparison.Predicate) squirrel.Sqlizer {
+	ts := p.Arg.(*comparison.TextSearch)
+	return squirrel.Expr(fmt.Sprintf("%s @@ %s", pg.tsvector(p.Col, ts), pg.tsquery(ts)), ts.Query)
+}
+
+// rank builds the order by clause of the full-text search rank
+func (pg *PostgresRepository) rank(s *sort.Sort) squirrel.Sqlizer {
+	dir := "ASC"
+	if s.Direction == sort.Desc {
+		dir = "DESC"
+	}
+	return squirrel.Expr(fmt.Sprintf("ts_rank(%s, %s) %s", pg.tsvector(s.Col, s.Rank),
+		pg.tsquery(s.Rank), dir), s.Rank.Query)
+}
+
+// tsvector renders the text search vector of the column, the configuration
+// is inlined so that it can use an index on the same expression
+func (pg *PostgresRepository) tsvector(col string, ts *comparison.TextSearch) string {
+	return fmt.Sprintf("to_tsvector('%s', %q)", strings.ReplaceAll(ts.Config, "'", "''"), col)
+}
+
+// tsquery renders the text search query
+func (pg *PostgresRepository) tsquery(ts *comparison.TextSearch) string {
+	return fmt.Sprintf("websearch_to_tsquery('%s', ?)", strings.ReplaceAll(ts.Config, "'", "''"))
+}
+
+// lock renders the row locking clause of the query
+func (pg *PostgresRepository) lock(q *Queryer) squirrel.Sqlizer {
+	switch q.lock {
+	case nero.ForUpdate, nero.ForNoKeyUpdate, nero.ForShare:
+	default:
+		return &nero.UnsupportedError{Op: q.lock.String(), Repository: "PostgresRepository"}
+	}
+
+	clause := q.lock.String()
+	if q.wait != nero.Wait {
+		clause += " " + q.wait.String()
+	}
+	return squirrel.Expr(clause)
+}
@@ -3,13 +3,16 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/segmentio/ksuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,18 +34,222 @@ func TestPostgreSQLRepository(t *testing.T) {
 
 	logger := log.New(os.Stderr, "nero test: ", 0)
 	repo := repository.NewPostgresRepository(db).Debug().WithLogger(logger)
-	newRepoTestRunner(repo)(t)
+	newRepoTestRunner(repo, repoTestOpts{})(t)
 	require.NoError(t, dropTable(db))
 
 	// tx methods
 	require.NoError(t, createTable(db))
 	repo = repository.NewPostgresRepository(db).Debug().WithLogger(logger)
-	newRepoTestRunnerTx(repo)(t)
+	newRepoTestRunnerTx(repo, repoTestOpts{txAbortsOnError: true})(t)
 	require.NoError(t, dropTable(db))
 }
 
-func createTable(db *sql.DB) error {
-	_, err := db.Exec(`CREATE TABLE users(
+func TestPostgresRepositoryCreateMany(t *testing.T) {
+	cs := []*repository.Creator{}
+	for i := 0; i < 3; i++ {
+		cs = append(cs, repository.NewCreator().UID(ksuid.New()).
+			Email(fmt.Sprintf("many%d@example.com", i)).Name("many"))
+	}
+
+	newRepo := func(t *testing.T) (*repository.PostgresRepository, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return repository.NewPostgresRepository(db), mock
+	}
+
+	t.Run("Ok", func(t *testing.T) {
+		// the rows are inserted one by one, so that the returned
+		// identities are matched to the creators
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		for _, id := range []int{7, 3, 9} {
+			mock.ExpectQuery(`INSERT INTO "users"`).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+		}
+		mock.ExpectCommit()
+
+		ids, err := repo.CreateMany(context.Background(), cs...)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"7", "3", "9"}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "users"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO "users"`).WillReturnError(errors.New("insert error"))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateMany(context.Background(), cs...)
+		assert.EqualError(t, err, "insert error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepositoryBulkLoad(t *testing.T) {
+	cs := []*repository.Creator{
+		repository.NewCreator().UID(ksuid.New()).Email("bulk1@example.com").Name("bulk1"),
+		repository.NewCreator().UID(ksuid.New()).Email("bulk2@example.com").Name("bulk2"),
+	}
+
+	const stmt = `COPY "users" ("uid", "email", "name") FROM STDIN`
+	newRepo := func(t *testing.T) (*repository.PostgresRepository, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return repository.NewPostgresRepository(db), mock
+	}
+
+	t.Run("Ok", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(stmt)
+		prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+		prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+		prep.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := repo.BulkLoad(context.Background(), cs...)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(stmt)
+		prep.ExpectExec().WillReturnError(errors.New("copy error"))
+		mock.ExpectRollback()
+
+		err := repo.BulkLoad(context.Background(), cs...)
+		assert.EqualError(t, err, "copy error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoColumns", func(t *testing.T) {
+		// the creators are validated before the copy is started
+		repo, mock := newRepo(t)
+		err := repo.BulkLoad(context.Background(), cs[0], repository.NewCreator())
+		assert.EqualError(t, err, "creator 2 of 2 doesn't set any column")
+
+		mock.ExpectBegin()
+		tx, err := repo.Tx(context.Background())
+		require.NoError(t, err)
+		err = repo.BulkLoadTx(context.Background(), tx, repository.NewCreator())
+		assert.EqualError(t, err, "creator 1 of 1 doesn't set any column")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepositoryFullTextSearch(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT "id", "uid", "email", "name", "age", "group", "kv", "tags", "updated_at", "created_at" FROM "users" `+
+		`WHERE to_tsvector('simple', "name") @@ websearch_to_tsquery('simple', $1) `+
+		`ORDER BY ts_rank(to_tsvector('simple', "name"), websearch_to_tsquery('simple', $2)) DESC, "id" ASC`).
+		WithArgs("human -charr", "human").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := repository.NewPostgresRepository(db)
+	users, err := repo.Query(context.Background(), repository.NewQueryer().
+		Where(repository.NameMatches("human -charr")).
+		Sort(repository.NameRank("human"), repository.Asc(repository.ColumnID)))
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepositoryLock(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id", "name" FROM "users" WHERE "id" = $1 FOR UPDATE SKIP LOCKED`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("1", "John"))
+	mock.ExpectQuery(`SELECT "id" FROM "users" ORDER BY "id" ASC LIMIT 1 FOR NO KEY UPDATE NOWAIT`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectQuery(`SELECT "id" FROM "users" LIMIT 1 FOR SHARE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectCommit()
+
+	repo := repository.NewPostgresRepository(db)
+	tx, err := repo.Tx(ctx)
+	require.NoError(t, err)
+
+	users, err := repo.QueryTx(ctx, tx, repository.NewQueryer().
+		Select(repository.ColumnID, repository.ColumnName).
+		Where(repository.IDEq("1")).ForUpdate().SkipLocked())
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+
+	page, err := repo.QueryPageTx(ctx, tx, repository.NewQueryer().
+		Select(repository.ColumnID).Limit(1).ForNoKeyUpdate().NoWait())
+	require.NoError(t, err)
+	require.Len(t, page.Rows, 1)
+
+	usr, err := repo.QueryOneTx(ctx, tx, repository.NewQueryer().
+		Select(repository.ColumnID).Limit(1).ForShare())
+	require.NoError(t, err)
+	assert.Equal(t, "1", usr.ID)
+
+	// the counted rows are not locked
+	_, err = repo.CountTx(ctx, tx, repository.NewQueryer().ForUpdate())
+	assert.EqualError(t, err, "row locking in Count is not supported by PostgresRepository")
+	_, err = repo.ExistsTx(ctx, tx, repository.NewQueryer().ForShare())
+	assert.EqualError(t, err, "row locking in Exists is not supported by PostgresRepository")
+	require.NoError(t, tx.Commit())
+
+	// outside a transaction
+	_, err = repo.Query(ctx, repository.NewQueryer().ForUpdate())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	_, err = repo.QueryOne(ctx, repository.NewQueryer().ForShare())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	_, err = repo.QueryPage(ctx, repository.NewQueryer().ForUpdate().NoWait())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	_, err = repo.Count(ctx, repository.NewQueryer().ForUpdate())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	_, err = repo.Exists(ctx, repository.NewQueryer().ForShare())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepositoryCount(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT(*) FROM "users" WHERE "group" = $1`).
+		WithArgs(user.Human).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT EXISTS(SELECT 1 FROM "users" WHERE "age" > $1)`).
+		WithArgs(30).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	ctx := context.Background()
+	repo := repository.NewPostgresRepository(db)
+	count, err := repo.Count(ctx, repository.NewQueryer().
+		Where(repository.GroupEq(user.Human)).
+		Sort(repository.Asc(repository.ColumnID)).Limit(1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	exists, err := repo.Exists(ctx, repository.NewQueryer().
+		Where(repository.AgeGt(30)))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// usersTable is the users table shared by the lib/pq and pgx suites
+const usersTable = `CREATE TABLE users(
 		id bigint GENERATED always AS IDENTITY PRIMARY KEY,
 		uid varchar(27) NOT NULL UNIQUE,
 		email VARCHAR(255) UNIQUE NOT NULL,
@@ -53,7 +260,10 @@ func createTable(db *sql.DB) error {
 		tags varchar(64)[] NOT NULL,
 		updated_at TIMESTAMP,
 		created_at TIMESTAMP DEFAULT now()
-	)`)
+	)`
+
+func createTable(db *sql.DB) error {
+	_, err := db.Exec(usersTable)
 	return err
 }
 
@@ -62,7 +272,18 @@ func dropTable(db *sql.DB) error {
 	return err
 }
 
-func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
+// repoTestOpts are the differences of a back-end from the SQL databases
+type repoTestOpts struct {
+	// txAbortsOnError tells whether the back-end aborts
+	// the transaction when a statement fails
+	txAbortsOnError bool
+	// schemaless tells whether the back-end doesn't enforce the users
+	// table i.e. the NOT NULL constraints and the column defaults are not
+	// applied and the string identities are not compared as numbers
+	schemaless bool
+}
+
+func newRepoTestRunner(repo repository.Repository, opts repoTestOpts) func(t *testing.T) {
 	return func(t *testing.T) {
 		var err error
 		ctx := context.Background()
@@ -70,6 +291,12 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 		uids := []ksuid.KSUID{}
 		kv := example.Map{"asdf": "ghjk", "qwert": "yuio", "zxcv": "bnml"}
 		tags := []string{"one", "two", "three"}
+		// name of the row with the greatest identity,
+		// "99" is greater than "100" when compared as strings
+		lastName := "charr_100_mm"
+		if opts.schemaless {
+			lastName = "norn_99_mm"
+		}
 		t.Run("Create", func(t *testing.T) {
 			t.Run("Ok", func(t *testing.T) {
 				now := time.Now()
@@ -107,9 +334,11 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 			})
 
 			t.Run("Error", func(t *testing.T) {
-				id, err := repo.Create(ctx, repository.NewCreator())
-				assert.Error(t, err)
-				assert.Zero(t, id)
+				if !opts.schemaless {
+					id, err := repo.Create(ctx, repository.NewCreator())
+					assert.Error(t, err)
+					assert.Zero(t, id)
+				}
 
 				cctx, cancel := context.WithCancel(ctx)
 				cancel()
@@ -142,25 +371,36 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 						Email(email).
 						Name(name).
 						Age(age).
+						Group(user.Group(group)).
 						Kv(kv).
 						Tags(tags)
 					crs = append(crs, cr)
 				}
 
-				err = repo.CreateMany(ctx, crs...)
+				ids, err := repo.CreateMany(ctx, crs...)
 				assert.NoError(t, err)
+				require.Len(t, ids, len(crs))
 
-				err = repo.CreateMany(ctx, []*repository.Creator{}...)
+				// the identities are in the order of the creators
+				usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+					Where(repository.IDEq(ids[1])))
+				assert.NoError(t, err)
+				assert.Equal(t, uids[len(uids)-len(crs)+1], usr.UID)
+
+				ids, err = repo.CreateMany(ctx, []*repository.Creator{}...)
 				assert.NoError(t, err)
+				assert.Empty(t, ids)
 			})
 
 			t.Run("Error", func(t *testing.T) {
-				err := repo.CreateMany(ctx, repository.NewCreator())
-				assert.Error(t, err)
+				if !opts.schemaless {
+					_, err := repo.CreateMany(ctx, repository.NewCreator())
+					assert.Error(t, err)
+				}
 
 				cctx, cancel := context.WithCancel(ctx)
 				cancel()
-				err = repo.CreateMany(cctx, repository.NewCreator())
+				_, err = repo.CreateMany(cctx, repository.NewCreator())
 				assert.Error(t, err)
 			})
 		})
@@ -175,7 +415,9 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 					assert.NotNil(t, u.Email)
 					assert.NotNil(t, u.Name)
 					assert.NotNil(t, u.UpdatedAt)
-					assert.NotNil(t, u.CreatedAt)
+					if !opts.schemaless {
+						assert.NotNil(t, u.CreatedAt)
+					}
 					assert.Len(t, u.Tags, 3)
 				}
 
@@ -187,7 +429,9 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 					assert.NotNil(t, u.Email)
 					assert.NotNil(t, u.Name)
 					assert.Nil(t, u.UpdatedAt)
-					assert.NotNil(t, u.CreatedAt)
+					if !opts.schemaless {
+						assert.NotNil(t, u.CreatedAt)
+					}
 					assert.Len(t, u.Tags, 3)
 				}
 
@@ -244,6 +488,147 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Len(t, users, 50)
 
+				// with grouped predicates
+				all, err := repo.Query(ctx, repository.NewQueryer())
+				require.NoError(t, err)
+				expect := 0
+				for _, u := range all {
+					if (u.Group == user.Human || u.Age > 25) && u.UpdatedAt != nil {
+						expect++
+					}
+				}
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(
+						repository.Or(repository.GroupEq(user.Human), repository.AgeGt(25)),
+						repository.Not(repository.UpdatedAtIsNull()),
+					),
+				)
+				assert.NoError(t, err)
+				assert.Len(t, users, expect)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Or(
+						repository.And(repository.IDEq("1"), repository.GroupNotIn()),
+						repository.IDEq("2"),
+					)),
+				)
+				assert.NoError(t, err)
+				assert.Len(t, users, 2)
+
+				// a negated predicate doesn't match the null columns
+				notNull, notNullNonHuman := 0, 0
+				for _, u := range all {
+					if u.UpdatedAt != nil {
+						notNull++
+						if u.Group != user.Human {
+							notNullNonHuman++
+						}
+					}
+				}
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Not(repository.UpdatedAtNotEqCol(repository.ColumnUpdatedAt))))
+				assert.NoError(t, err)
+				assert.Len(t, users, notNull)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Not(repository.UpdatedAtEqCol(repository.ColumnUpdatedAt))))
+				assert.NoError(t, err)
+				assert.Empty(t, users)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Not(repository.Or(
+						repository.UpdatedAtNotEqCol(repository.ColumnUpdatedAt),
+						repository.GroupEq(user.Human),
+					))),
+				)
+				assert.NoError(t, err)
+				assert.Len(t, users, notNullNonHuman)
+
+				// ranges
+				between := 0
+				for _, u := range all {
+					if u.Age >= 20 && u.Age <= 25 {
+						between++
+					}
+				}
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.AgeBetween(20, 25)))
+				assert.NoError(t, err)
+				assert.Len(t, users, between)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.AgeNotBetween(20, 25)))
+				assert.NoError(t, err)
+				assert.Len(t, users, len(all)-between)
+
+				// string matching
+				humans, humansMM := 0, 0
+				for _, u := range all {
+					if strings.HasPrefix(u.Name, "human") {
+						humans++
+						if strings.HasSuffix(u.Name, "_mm") {
+							humansMM++
+						}
+					}
+				}
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameLike("human%"), repository.NameHasSuffix("_mm")))
+				assert.NoError(t, err)
+				assert.Len(t, users, humansMM)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameILike("HUMAN%"), repository.NameContains("an_")))
+				assert.NoError(t, err)
+				assert.Len(t, users, humans)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameNotLike("human%"), repository.NameHasPrefix("charr_")))
+				assert.NoError(t, err)
+				assert.NotZero(t, len(users))
+				assert.Less(t, len(users), len(all)-humans)
+
+				// the wildcards are escaped
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameHasPrefix("_"), repository.NameContains("%")))
+				assert.NoError(t, err)
+				assert.Empty(t, users)
+
+				// the patterns are case-sensitive and escaped with a backslash
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Or(repository.NameLike("HUMAN%"), repository.NameHasPrefix("HUMAN"))))
+				assert.NoError(t, err)
+				assert.Empty(t, users)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameNotLike("HUMAN%")))
+				assert.NoError(t, err)
+				assert.Len(t, users, len(all))
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameLike(`huma\n\_%`)))
+				assert.NoError(t, err)
+				assert.Len(t, users, humans)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.NameILike(`HUMAN\_%`)))
+				assert.NoError(t, err)
+				assert.Len(t, users, humans)
+
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Or(
+						repository.NameLike(`human\%%`),
+						repository.NameLike("human*"),
+						repository.NameLike("[h]uman%"),
+					)))
+				assert.NoError(t, err)
+				assert.Empty(t, users)
+
+				// an empty "in" matches everything
+				users, err = repo.Query(ctx, repository.NewQueryer().
+					Where(repository.Or(repository.IDIn(), repository.IDEq("2"))))
+				assert.NoError(t, err)
+				assert.Len(t, users, len(all))
+
 				// with sort
 				// get last user
 				users, err = repo.Query(ctx, repository.NewQueryer().
@@ -254,7 +639,7 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 				)
 				assert.NoError(t, err)
 				require.NotZero(t, len(users))
-				assert.Equal(t, "charr_100_mm", users[0].Name)
+				assert.Equal(t, lastName, users[0].Name)
 
 				// with limit and offset
 				users, err = repo.Query(ctx, repository.NewQueryer().Limit(1).Offset(1))
@@ -291,6 +676,138 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 			})
 		})
 
+		t.Run("Count", func(t *testing.T) {
+			t.Run("Ok", func(t *testing.T) {
+				users, err := repo.Query(ctx, repository.NewQueryer())
+				require.NoError(t, err)
+				count, err := repo.Count(ctx, repository.NewQueryer().Limit(1))
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(users)), count)
+
+				humans, err := repo.Query(ctx, repository.NewQueryer().
+					Where(repository.GroupEq(user.Human)))
+				require.NoError(t, err)
+				count, err = repo.Count(ctx, repository.NewQueryer().
+					Where(repository.GroupEq(user.Human)))
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(humans)), count)
+
+				exists, err := repo.Exists(ctx, repository.NewQueryer().
+					Where(repository.IDEq("1")))
+				require.NoError(t, err)
+				assert.True(t, exists)
+
+				exists, err = repo.Exists(ctx, repository.NewQueryer().
+					Where(repository.IDEq("9999")))
+				require.NoError(t, err)
+				assert.False(t, exists)
+			})
+
+			t.Run("Error", func(t *testing.T) {
+				cctx, cancel := context.WithCancel(ctx)
+				cancel()
+				_, err := repo.Count(cctx, repository.NewQueryer())
+				assert.Error(t, err)
+				_, err = repo.Exists(cctx, repository.NewQueryer())
+				assert.Error(t, err)
+			})
+		})
+
+		t.Run("Select", func(t *testing.T) {
+			users, err := repo.Query(ctx, repository.NewQueryer().
+				SelectSummary().Sort(repository.Asc(repository.ColumnID)))
+			require.NoError(t, err)
+			require.NotEmpty(t, users)
+			for _, usr := range users {
+				assert.NotEmpty(t, usr.ID)
+				assert.NotEmpty(t, usr.Email)
+				assert.Empty(t, usr.Tags)
+				assert.Nil(t, usr.Kv)
+				assert.Nil(t, usr.CreatedAt)
+			}
+
+			summaries := repository.ToSummaries(users)
+			require.Len(t, summaries, len(users))
+			assert.Equal(t, users[0].ID, summaries[0].ID)
+			assert.Equal(t, users[0].Email, summaries[0].Email)
+
+			usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+				Select(repository.ColumnTags).Where(repository.IDEq("1")))
+			require.NoError(t, err)
+			assert.Empty(t, usr.ID)
+			assert.Len(t, usr.Tags, 3)
+
+			// the keys of the pagination are selected
+			page, err := repo.QueryPage(ctx, repository.NewQueryer().
+				Select(repository.ColumnName).Limit(2))
+			require.NoError(t, err)
+			require.Len(t, page.Rows, 2)
+			assert.NotEmpty(t, page.Rows[0].ID)
+			assert.NotEmpty(t, page.Next)
+		})
+
+		t.Run("QueryPage", func(t *testing.T) {
+			t.Run("Ok", func(t *testing.T) {
+				want, err := repo.Query(ctx, repository.NewQueryer().
+					Sort(repository.Desc(repository.ColumnAge), repository.Asc(repository.ColumnID)))
+				require.NoError(t, err)
+
+				// forward
+				pages := [][]*user.User{}
+				prevs := []nero.Cursor{}
+				got := []*user.User{}
+				q := repository.NewQueryer().Sort(repository.Desc(repository.ColumnAge)).Limit(7)
+				for {
+					page, err := repo.QueryPage(ctx, q)
+					require.NoError(t, err)
+					if len(page.Rows) == 0 {
+						assert.Empty(t, page.Next)
+						break
+					}
+					pages = append(pages, page.Rows)
+					prevs = append(prevs, page.Prev)
+					got = append(got, page.Rows...)
+					q = q.After(page.Next)
+				}
+				require.Len(t, got, len(want))
+				for i := range want {
+					assert.Equal(t, want[i].ID, got[i].ID)
+				}
+
+				// backward from the last page
+				require.True(t, len(pages) > 1)
+				before := pages[len(pages)-2]
+				page, err := repo.QueryPage(ctx, repository.NewQueryer().
+					Sort(repository.Desc(repository.ColumnAge)).Limit(7).Before(prevs[len(prevs)-1]))
+				require.NoError(t, err)
+				require.Len(t, page.Rows, len(before))
+				for i := range before {
+					assert.Equal(t, before[i].ID, page.Rows[i].ID)
+				}
+
+				// the closest row before the cursor
+				usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+					Sort(repository.Desc(repository.ColumnAge)).Before(prevs[len(prevs)-1]))
+				require.NoError(t, err)
+				assert.Equal(t, before[len(before)-1].ID, usr.ID)
+			})
+
+			t.Run("Error", func(t *testing.T) {
+				_, err := repo.QueryPage(ctx, repository.NewQueryer().After("invalid"))
+				assert.Error(t, err)
+
+				page, err := repo.QueryPage(ctx, repository.NewQueryer().Limit(1))
+				require.NoError(t, err)
+				// the sorts don't match the cursor
+				_, err = repo.QueryPage(ctx, repository.NewQueryer().
+					Sort(repository.Asc(repository.ColumnAge)).After(page.Next))
+				assert.Error(t, err)
+
+				_, err = repo.QueryPage(ctx, repository.NewQueryer().Sort(repository.NameRank("human")))
+				assert.Error(t, err)
+			})
+		})
+
 		t.Run("Aggregate", func(t *testing.T) {
 			t.Run("Ok", func(t *testing.T) {
 				type aggt struct {
@@ -372,10 +889,75 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 				assert.Len(t, usr.Tags, 1)
 			})
 
+			t.Run("Zero and NULL", func(t *testing.T) {
+				rowsAffected, err := repo.Update(ctx,
+					repository.NewUpdater().
+						Age(0).
+						SetNullUpdatedAt().
+						Where(repository.IDEq("1")),
+				)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), rowsAffected)
+
+				usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+					Where(repository.IDEq("1")))
+				assert.NoError(t, err)
+				assert.Equal(t, 0, usr.Age)
+				assert.Nil(t, usr.UpdatedAt)
+				assert.NotEmpty(t, usr.Name)
+			})
+
+			t.Run("Expressions", func(t *testing.T) {
+				rowsAffected, err := repo.Update(ctx,
+					repository.NewUpdater().
+						AgeIncr(10).
+						UpdatedAtFromCol(repository.ColumnCreatedAt).
+						Where(repository.IDEq("1")),
+				)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), rowsAffected)
+
+				_, err = repo.Update(ctx,
+					repository.NewUpdater().
+						AgeDecr(4).
+						Where(repository.IDEq("1")),
+				)
+				assert.NoError(t, err)
+
+				usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+					Where(repository.IDEq("1")))
+				require.NoError(t, err)
+				assert.Equal(t, 6, usr.Age)
+				if opts.schemaless {
+					// created_at is not set by a column default
+					assert.Nil(t, usr.UpdatedAt)
+				} else {
+					require.NotNil(t, usr.UpdatedAt)
+					assert.True(t, usr.UpdatedAt.Equal(*usr.CreatedAt))
+				}
+			})
+
 			t.Run("Error", func(t *testing.T) {
 				_, err = repo.Update(ctx, repository.NewUpdater())
 				assert.Error(t, err)
 
+				_, err = repo.Update(ctx, repository.NewUpdater().Name("a"))
+				assert.IsType(t, &nero.UnfilteredError{}, err)
+
+				// an empty "in" and an empty "and" don't filter the rows
+				_, err = repo.Update(ctx, repository.NewUpdater().Name("a").
+					Where(repository.IDIn()))
+				assert.IsType(t, &nero.UnfilteredError{}, err)
+				_, err = repo.Update(ctx, repository.NewUpdater().Name("a").
+					Where(repository.And()))
+				assert.IsType(t, &nero.UnfilteredError{}, err)
+
+				// a column can only be copied into a column of the same type
+				_, err = repo.Update(ctx, repository.NewUpdater().
+					UpdatedAtFromCol(repository.ColumnName).
+					Where(repository.IDEq("1")))
+				assert.IsType(t, &nero.ColumnTypeError{}, err)
+
 				cctx, cancel := context.WithCancel(ctx)
 				cancel()
 				_, err = repo.Update(cctx, repository.NewUpdater())
@@ -383,6 +965,151 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 			})
 		})
 
+		t.Run("Upsert", func(t *testing.T) {
+			t.Run("Ok", func(t *testing.T) {
+				email := "upsert@gg.io"
+				newCreator := func(name string, age int) *repository.Creator {
+					return repository.NewCreator().
+						UID(ksuid.New()).
+						Email(email).
+						Name(name).
+						Age(age).
+						Group(user.Human).
+						Tags(tags)
+				}
+
+				// insert
+				id, err := repo.Upsert(ctx, repository.NewUpserter(
+					newCreator("upsert", 20)).OnConflict(repository.ColumnEmail))
+				require.NoError(t, err)
+				assert.NotZero(t, id)
+
+				// update the set columns
+				id2, err := repo.Upsert(ctx, repository.NewUpserter(
+					newCreator("upserted", 21)).OnConflict(repository.ColumnEmail))
+				require.NoError(t, err)
+				assert.Equal(t, id, id2)
+
+				// update only the age
+				id2, err = repo.Upsert(ctx, repository.NewUpserter(
+					newCreator("ignored", 22)).
+					OnConflict(repository.ColumnEmail).
+					Update(repository.ColumnAge))
+				require.NoError(t, err)
+				assert.Equal(t, id, id2)
+
+				// do nothing
+				id2, err = repo.Upsert(ctx, repository.NewUpserter(
+					newCreator("ignored", 23)).
+					OnConflict(repository.ColumnEmail).
+					DoNothing())
+				require.NoError(t, err)
+				assert.Equal(t, id, id2)
+
+				usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+					Where(repository.EmailEq(email)))
+				require.NoError(t, err)
+				assert.Equal(t, "upserted", usr.Name)
+				assert.Equal(t, 22, usr.Age)
+
+				// many
+				err = repo.UpsertMany(ctx, repository.NewUpserter(
+					newCreator("upserted many", 24),
+				).OnConflict(repository.ColumnEmail).
+					Update(repository.ColumnName))
+				require.NoError(t, err)
+
+				usr, err = repo.QueryOne(ctx, repository.NewQueryer().
+					Where(repository.EmailEq(email)))
+				require.NoError(t, err)
+				assert.Equal(t, "upserted many", usr.Name)
+				assert.Equal(t, 22, usr.Age)
+
+				rowsAffected, err := repo.Delete(ctx, repository.NewDeleter().
+					Where(repository.EmailEq(email)))
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), rowsAffected)
+			})
+
+			t.Run("Error", func(t *testing.T) {
+				// the conflict column is not set
+				_, err := repo.Upsert(ctx, repository.NewUpserter(
+					repository.NewCreator().Name("upsert")).
+					OnConflict(repository.ColumnEmail))
+				assert.Error(t, err)
+
+				_, err = repo.Upsert(ctx, repository.NewUpserter())
+				assert.Error(t, err)
+
+				// the auto-filled identity is not a default conflict target
+				c := repository.NewCreator().UID(ksuid.New()).
+					Email("upsert@gg.io").Name("upsert").Tags(tags)
+				_, err = repo.Upsert(ctx, repository.NewUpserter(c))
+				assert.EqualError(t, err, "conflict target required, use OnConflict to set it")
+				err = repo.UpsertMany(ctx, repository.NewUpserter(c))
+				assert.EqualError(t, err, "conflict target required, use OnConflict to set it")
+
+				assert.NoError(t, repo.UpsertMany(ctx, repository.NewUpserter()))
+			})
+		})
+
+		t.Run("Returning", func(t *testing.T) {
+			t.Run("Ok", func(t *testing.T) {
+				email := "returning@gg.io"
+				usr, err := repo.CreateReturning(ctx, repository.NewCreator().
+					UID(ksuid.New()).
+					Email(email).
+					Name("returning").
+					Age(30).
+					Group(user.Human).
+					Tags(tags))
+				require.NoError(t, err)
+				assert.NotZero(t, usr.ID)
+				assert.Equal(t, email, usr.Email)
+				assert.Equal(t, tags, usr.Tags)
+				// populated by the column default
+				if !opts.schemaless {
+					assert.NotNil(t, usr.CreatedAt)
+				}
+
+				usrs, err := repo.UpdateReturning(ctx, repository.NewUpdater().
+					Age(31).
+					Where(repository.EmailEq(email)))
+				require.NoError(t, err)
+				require.Len(t, usrs, 1)
+				assert.Equal(t, usr.ID, usrs[0].ID)
+				assert.Equal(t, "returning", usrs[0].Name)
+				assert.Equal(t, 31, usrs[0].Age)
+
+				usrs, err = repo.DeleteReturning(ctx, repository.NewDeleter().
+					Where(repository.EmailEq(email)))
+				require.NoError(t, err)
+				require.Len(t, usrs, 1)
+				assert.Equal(t, usr.ID, usrs[0].ID)
+				assert.Equal(t, 31, usrs[0].Age)
+
+				usrs, err = repo.DeleteReturning(ctx, repository.NewDeleter().
+					Where(repository.EmailEq(email)))
+				require.NoError(t, err)
+				assert.Empty(t, usrs)
+			})
+
+			t.Run("Error", func(t *testing.T) {
+				if !opts.schemaless {
+					_, err := repo.CreateReturning(ctx, repository.NewCreator())
+					assert.Error(t, err)
+				}
+
+				_, err := repo.UpdateReturning(ctx, repository.NewUpdater())
+				assert.Error(t, err)
+
+				cctx, cancel := context.WithCancel(ctx)
+				cancel()
+				_, err = repo.DeleteReturning(cctx, repository.NewDeleter().AllRows())
+				assert.Error(t, err)
+			})
+		})
+
 		t.Run("Delete", func(t *testing.T) {
 			t.Run("Ok", func(t *testing.T) {
 				preds := []repository.PredFunc{
@@ -401,8 +1128,18 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 				assert.Error(t, err, sql.ErrNoRows)
 				assert.Nil(t, usr)
 
+				// refused without predicates
+				_, err = repo.Delete(ctx, repository.NewDeleter())
+				assert.IsType(t, &nero.UnfilteredError{}, err)
+				_, err = repo.Delete(ctx, repository.NewDeleter().
+					Where(repository.IDIn()))
+				assert.IsType(t, &nero.UnfilteredError{}, err)
+				_, err = repo.Delete(ctx, repository.NewDeleter().
+					Where(repository.And(repository.IDNotIn())))
+				assert.IsType(t, &nero.UnfilteredError{}, err)
+
 				// delete all
-				rowsAffected, err = repo.Delete(ctx, repository.NewDeleter())
+				rowsAffected, err = repo.Delete(ctx, repository.NewDeleter().AllRows())
 				assert.NoError(t, err)
 				assert.Equal(t, int64(99), rowsAffected)
 			})
@@ -410,14 +1147,15 @@ func newRepoTestRunner(repo repository.Repository) func(t *testing.T) {
 			t.Run("Error", func(t *testing.T) {
 				cctx, cancel := context.WithCancel(ctx)
 				cancel()
-				_, err = repo.Delete(cctx, repository.NewDeleter())
+				_, err = repo.Delete(cctx, repository.NewDeleter().AllRows())
 				assert.Error(t, err)
 			})
 		})
 	}
 }
 
-func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
+// newRepoTestRunnerTx runs the tx methods test suite
+func newRepoTestRunnerTx(repo repository.Repository, opts repoTestOpts) func(t *testing.T) {
 	return func(t *testing.T) {
 		var err error
 		ctx := context.Background()
@@ -426,9 +1164,22 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 			assert.NoError(t, err)
 			return tx
 		}
+		endFailedTx := func(t *testing.T, tx nero.Tx) {
+			if opts.txAbortsOnError {
+				assert.Error(t, tx.Commit())
+				return
+			}
+			assert.NoError(t, tx.Rollback())
+		}
 		uids := []ksuid.KSUID{}
 		kv := example.Map{"asdf": "ghjk", "qwert": "yuio", "zxcv": "bnml"}
 		tags := []string{"one", "two", "three"}
+		// name of the row with the greatest identity,
+		// "99" is greater than "100" when compared as strings
+		lastName := "charr_100_mm"
+		if opts.schemaless {
+			lastName = "norn_99_mm"
+		}
 		t.Run("CreateTx", func(t *testing.T) {
 			t.Run("Ok", func(t *testing.T) {
 				now := time.Now()
@@ -464,14 +1215,16 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 			})
 
 			t.Run("Error", func(t *testing.T) {
-				tx := newTx(ctx, t)
-				id, err := repo.CreateTx(ctx, tx, repository.NewCreator())
-				assert.Error(t, err)
-				assert.Zero(t, id)
-				assert.Error(t, tx.Commit())
+				if !opts.schemaless {
+					tx := newTx(ctx, t)
+					id, err := repo.CreateTx(ctx, tx, repository.NewCreator())
+					assert.Error(t, err)
+					assert.Zero(t, id)
+					endFailedTx(t, tx)
+				}
 
 				cctx, cancel := context.WithCancel(ctx)
-				tx = newTx(cctx, t)
+				tx := newTx(cctx, t)
 				cancel()
 				_, err = repo.CreateTx(cctx, tx, repository.NewCreator())
 				assert.Error(t, err)
@@ -503,31 +1256,35 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 						Email(email).
 						Name(name).
 						Age(age).
+						Group(user.Group(group)).
 						Kv(kv).
 						Tags(tags)
 					crs = append(crs, cr)
 				}
 				tx := newTx(ctx, t)
-				err = repo.CreateManyTx(ctx, tx, crs...)
+				ids, err := repo.CreateManyTx(ctx, tx, crs...)
 				assert.NoError(t, err)
+				assert.Len(t, ids, len(crs))
 				assert.NoError(t, tx.Commit())
 
 				tx = newTx(ctx, t)
-				err = repo.CreateManyTx(ctx, tx, []*repository.Creator{}...)
+				_, err = repo.CreateManyTx(ctx, tx, []*repository.Creator{}...)
 				assert.NoError(t, err)
 				assert.NoError(t, tx.Commit())
 			})
 
 			t.Run("Error", func(t *testing.T) {
-				tx := newTx(ctx, t)
-				err := repo.CreateManyTx(ctx, tx, repository.NewCreator())
-				assert.Error(t, err)
-				assert.Error(t, tx.Commit())
+				if !opts.schemaless {
+					tx := newTx(ctx, t)
+					_, err := repo.CreateManyTx(ctx, tx, repository.NewCreator())
+					assert.Error(t, err)
+					endFailedTx(t, tx)
+				}
 
 				cctx, cancel := context.WithCancel(ctx)
-				tx = newTx(cctx, t)
+				tx := newTx(cctx, t)
 				cancel()
-				err = repo.CreateManyTx(cctx, tx, repository.NewCreator())
+				_, err = repo.CreateManyTx(cctx, tx, repository.NewCreator())
 				assert.Error(t, err)
 				assert.Error(t, tx.Commit())
 			})
@@ -544,7 +1301,9 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 					assert.NotNil(t, u.Email)
 					assert.NotNil(t, u.Name)
 					assert.NotNil(t, u.UpdatedAt)
-					assert.NotNil(t, u.CreatedAt)
+					if !opts.schemaless {
+						assert.NotNil(t, u.CreatedAt)
+					}
 					assert.Len(t, u.Tags, 3)
 				}
 				assert.NoError(t, tx.Commit())
@@ -558,7 +1317,9 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 					assert.NotNil(t, u.Email)
 					assert.NotNil(t, u.Name)
 					assert.Nil(t, u.UpdatedAt)
-					assert.NotNil(t, u.CreatedAt)
+					if !opts.schemaless {
+						assert.NotNil(t, u.CreatedAt)
+					}
 					assert.Len(t, u.Tags, 3)
 				}
 				assert.NoError(t, tx.Commit())
@@ -633,7 +1394,7 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 				)
 				assert.NoError(t, err)
 				require.NotZero(t, len(users))
-				assert.Equal(t, "charr_100_mm", users[0].Name)
+				assert.Equal(t, lastName, users[0].Name)
 				assert.NoError(t, tx.Commit())
 
 				// with limit and offset
@@ -681,6 +1442,33 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 			})
 		})
 
+		t.Run("CountTx", func(t *testing.T) {
+			t.Run("Ok", func(t *testing.T) {
+				tx := newTx(ctx, t)
+				count, err := repo.CountTx(ctx, tx, repository.NewQueryer().
+					Where(repository.IDEq("1")))
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+
+				exists, err := repo.ExistsTx(ctx, tx, repository.NewQueryer().
+					Where(repository.IDEq("9999")))
+				assert.NoError(t, err)
+				assert.False(t, exists)
+				assert.NoError(t, tx.Commit())
+			})
+
+			t.Run("Error", func(t *testing.T) {
+				cctx, cancel := context.WithCancel(ctx)
+				tx := newTx(cctx, t)
+				cancel()
+				_, err = repo.CountTx(cctx, tx, repository.NewQueryer())
+				assert.Error(t, err)
+				_, err = repo.ExistsTx(cctx, tx, repository.NewQueryer())
+				assert.Error(t, err)
+				assert.Error(t, tx.Commit())
+			})
+		})
+
 		t.Run("AggregateTx", func(t *testing.T) {
 			t.Run("Ok", func(t *testing.T) {
 				type aggt struct {
@@ -807,7 +1595,7 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 
 				// delete all
 				tx = newTx(ctx, t)
-				rowsAffected, err = repo.Delete(ctx, repository.NewDeleter())
+				rowsAffected, err = repo.DeleteTx(ctx, tx, repository.NewDeleter().AllRows())
 				assert.NoError(t, err)
 				assert.Equal(t, int64(99), rowsAffected)
 				assert.NoError(t, tx.Commit())
@@ -815,10 +1603,11 @@ func newRepoTestRunnerTx(repo repository.Repository) func(t *testing.T) {
 
 			t.Run("Error", func(t *testing.T) {
 				cctx, cancel := context.WithCancel(ctx)
-				tx := newTx(ctx, t)
+				tx := newTx(cctx, t)
 				cancel()
-				_, err = repo.DeleteTx(cctx, tx, repository.NewDeleter())
+				_, err = repo.DeleteTx(cctx, tx, repository.NewDeleter().AllRows())
 				assert.Error(t, err)
+				assert.Error(t, tx.Commit())
 			})
 		})
 	}
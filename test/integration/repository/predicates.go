@@ -110,6 +110,94 @@ func IDNotIn(ids ...string) PredFunc {
 	}
 }
 
+// IDLike is a "like" operator on "id" column
+func IDLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.Like,
+			Arg: pattern,
+		})
+	}
+}
+
+// IDNotLike is a "not like" operator on "id" column
+func IDNotLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.NotLike,
+			Arg: pattern,
+		})
+	}
+}
+
+// IDILike is a "case-insensitive like" operator on "id" column
+func IDILike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.ILike,
+			Arg: pattern,
+		})
+	}
+}
+
+// IDHasPrefix is a "has prefix" operator on "id" column
+func IDHasPrefix(prefix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.HasPrefix,
+			Arg: prefix,
+		})
+	}
+}
+
+// IDHasSuffix is a "has suffix" operator on "id" column
+func IDHasSuffix(suffix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.HasSuffix,
+			Arg: suffix,
+		})
+	}
+}
+
+// IDContains is a "contains" operator on "id" column
+func IDContains(substr string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.Contains,
+			Arg: substr,
+		})
+	}
+}
+
+// IDBetween is a "between" operator on "id" column, the bounds are inclusive
+func IDBetween(lo, hi string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// IDNotBetween is a "not between" operator on "id" column, the bounds are inclusive
+func IDNotBetween(lo, hi string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "id",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
 // UIDEq is a "equal" operator on "uid" column
 func UIDEq(uid ksuid.KSUID) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -306,6 +394,94 @@ func EmailNotIn(emails ...string) PredFunc {
 	}
 }
 
+// EmailLike is a "like" operator on "email" column
+func EmailLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.Like,
+			Arg: pattern,
+		})
+	}
+}
+
+// EmailNotLike is a "not like" operator on "email" column
+func EmailNotLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.NotLike,
+			Arg: pattern,
+		})
+	}
+}
+
+// EmailILike is a "case-insensitive like" operator on "email" column
+func EmailILike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.ILike,
+			Arg: pattern,
+		})
+	}
+}
+
+// EmailHasPrefix is a "has prefix" operator on "email" column
+func EmailHasPrefix(prefix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.HasPrefix,
+			Arg: prefix,
+		})
+	}
+}
+
+// EmailHasSuffix is a "has suffix" operator on "email" column
+func EmailHasSuffix(suffix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.HasSuffix,
+			Arg: suffix,
+		})
+	}
+}
+
+// EmailContains is a "contains" operator on "email" column
+func EmailContains(substr string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.Contains,
+			Arg: substr,
+		})
+	}
+}
+
+// EmailBetween is a "between" operator on "email" column, the bounds are inclusive
+func EmailBetween(lo, hi string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// EmailNotBetween is a "not between" operator on "email" column, the bounds are inclusive
+func EmailNotBetween(lo, hi string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "email",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
 // NameEq is a "equal" operator on "name" column
 func NameEq(name string) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -404,6 +580,108 @@ func NameNotIn(names ...string) PredFunc {
 	}
 }
 
+// NameLike is a "like" operator on "name" column
+func NameLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.Like,
+			Arg: pattern,
+		})
+	}
+}
+
+// NameNotLike is a "not like" operator on "name" column
+func NameNotLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.NotLike,
+			Arg: pattern,
+		})
+	}
+}
+
+// NameILike is a "case-insensitive like" operator on "name" column
+func NameILike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.ILike,
+			Arg: pattern,
+		})
+	}
+}
+
+// NameHasPrefix is a "has prefix" operator on "name" column
+func NameHasPrefix(prefix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.HasPrefix,
+			Arg: prefix,
+		})
+	}
+}
+
+// NameHasSuffix is a "has suffix" operator on "name" column
+func NameHasSuffix(suffix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.HasSuffix,
+			Arg: suffix,
+		})
+	}
+}
+
+// NameContains is a "contains" operator on "name" column
+func NameContains(substr string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.Contains,
+			Arg: substr,
+		})
+	}
+}
+
+// NameBetween is a "between" operator on "name" column, the bounds are inclusive
+func NameBetween(lo, hi string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// NameNotBetween is a "not between" operator on "name" column, the bounds are inclusive
+func NameNotBetween(lo, hi string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// NameMatches is a full-text search on "name" column
+func NameMatches(query string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "name",
+			Op:  comparison.Matches,
+			Arg: &comparison.TextSearch{
+				Config: "simple",
+				Query:  query,
+			},
+		})
+	}
+}
+
 // AgeEq is a "equal" operator on "age" column
 func AgeEq(age int) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -502,6 +780,28 @@ func AgeNotIn(ages ...int) PredFunc {
 	}
 }
 
+// AgeBetween is a "between" operator on "age" column, the bounds are inclusive
+func AgeBetween(lo, hi int) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "age",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// AgeNotBetween is a "not between" operator on "age" column, the bounds are inclusive
+func AgeNotBetween(lo, hi int) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "age",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
 // GroupEq is a "equal" operator on "group" column
 func GroupEq(group user.Group) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -600,6 +900,94 @@ func GroupNotIn(groups ...user.Group) PredFunc {
 	}
 }
 
+// GroupLike is a "like" operator on "group" column
+func GroupLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.Like,
+			Arg: pattern,
+		})
+	}
+}
+
+// GroupNotLike is a "not like" operator on "group" column
+func GroupNotLike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.NotLike,
+			Arg: pattern,
+		})
+	}
+}
+
+// GroupILike is a "case-insensitive like" operator on "group" column
+func GroupILike(pattern string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.ILike,
+			Arg: pattern,
+		})
+	}
+}
+
+// GroupHasPrefix is a "has prefix" operator on "group" column
+func GroupHasPrefix(prefix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.HasPrefix,
+			Arg: prefix,
+		})
+	}
+}
+
+// GroupHasSuffix is a "has suffix" operator on "group" column
+func GroupHasSuffix(suffix string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.HasSuffix,
+			Arg: suffix,
+		})
+	}
+}
+
+// GroupContains is a "contains" operator on "group" column
+func GroupContains(substr string) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.Contains,
+			Arg: substr,
+		})
+	}
+}
+
+// GroupBetween is a "between" operator on "group" column, the bounds are inclusive
+func GroupBetween(lo, hi user.Group) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// GroupNotBetween is a "not between" operator on "group" column, the bounds are inclusive
+func GroupNotBetween(lo, hi user.Group) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "group",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
 // UpdatedAtEq is a "equal" operator on "updated_at" column
 func UpdatedAtEq(updatedAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -611,6 +999,17 @@ func UpdatedAtEq(updatedAt *time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtEq is a "equal" operator on "updated_at" column
+func UpdatedAtEqCol(col Column) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.Eq,
+			Arg: col,
+		})
+	}
+}
+
 // UpdatedAtNotEq is a "not equal" operator on "updated_at" column
 func UpdatedAtNotEq(updatedAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -622,6 +1021,17 @@ func UpdatedAtNotEq(updatedAt *time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtNotEq is a "not equal" operator on "updated_at" column
+func UpdatedAtNotEqCol(col Column) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.NotEq,
+			Arg: col,
+		})
+	}
+}
+
 // UpdatedAtGt is a "greater than" operator on "updated_at" column
 func UpdatedAtGt(updatedAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -633,6 +1043,17 @@ func UpdatedAtGt(updatedAt *time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtGt is a "greater than" operator on "updated_at" column
+func UpdatedAtGtCol(col Column) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.Gt,
+			Arg: col,
+		})
+	}
+}
+
 // UpdatedAtGtOrEq is a "greater than or equal" operator on "updated_at" column
 func UpdatedAtGtOrEq(updatedAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -644,6 +1065,17 @@ func UpdatedAtGtOrEq(updatedAt *time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtGtOrEq is a "greater than or equal" operator on "updated_at" column
+func UpdatedAtGtOrEqCol(col Column) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.GtOrEq,
+			Arg: col,
+		})
+	}
+}
+
 // UpdatedAtLt is a "less than" operator on "updated_at" column
 func UpdatedAtLt(updatedAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -655,6 +1087,17 @@ func UpdatedAtLt(updatedAt *time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtLt is a "less than" operator on "updated_at" column
+func UpdatedAtLtCol(col Column) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.Lt,
+			Arg: col,
+		})
+	}
+}
+
 // UpdatedAtLtOrEq is a "less than or equal" operator on "updated_at" column
 func UpdatedAtLtOrEq(updatedAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -666,6 +1109,17 @@ func UpdatedAtLtOrEq(updatedAt *time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtLtOrEq is a "less than or equal" operator on "updated_at" column
+func UpdatedAtLtOrEqCol(col Column) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.LtOrEq,
+			Arg: col,
+		})
+	}
+}
+
 // UpdatedAtIsNull is a "is null" operator on "updated_at" column
 func UpdatedAtIsNull() PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -718,6 +1172,28 @@ func UpdatedAtNotIn(updatedAts ...*time.Time) PredFunc {
 	}
 }
 
+// UpdatedAtBetween is a "between" operator on "updated_at" column, the bounds are inclusive
+func UpdatedAtBetween(lo, hi *time.Time) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// UpdatedAtNotBetween is a "not between" operator on "updated_at" column, the bounds are inclusive
+func UpdatedAtNotBetween(lo, hi *time.Time) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "updated_at",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
 // CreatedAtEq is a "equal" operator on "created_at" column
 func CreatedAtEq(createdAt *time.Time) PredFunc {
 	return func(pb *comparison.Predicates) {
@@ -815,3 +1291,49 @@ func CreatedAtNotIn(createdAts ...*time.Time) PredFunc {
 		})
 	}
 }
+
+// CreatedAtBetween is a "between" operator on "created_at" column, the bounds are inclusive
+func CreatedAtBetween(lo, hi *time.Time) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "created_at",
+			Op:  comparison.Between,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// CreatedAtNotBetween is a "not between" operator on "created_at" column, the bounds are inclusive
+func CreatedAtNotBetween(lo, hi *time.Time) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Col: "created_at",
+			Op:  comparison.NotBetween,
+			Arg: []interface{}{lo, hi},
+		})
+	}
+}
+
+// And is an "and" operator on the predicates
+func And(pfs ...PredFunc) PredFunc {
+	return groupPredicates(comparison.And, pfs)
+}
+
+// Or is an "or" operator on the predicates
+func Or(pfs ...PredFunc) PredFunc {
+	return groupPredicates(comparison.Or, pfs)
+}
+
+// Not is a "not" operator on the predicate
+func Not(pf PredFunc) PredFunc {
+	return groupPredicates(comparison.Not, []PredFunc{pf})
+}
+
+func groupPredicates(op comparison.Operator, pfs []PredFunc) PredFunc {
+	return func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{
+			Op:  op,
+			Arg: buildPredicates(pfs).All(),
+		})
+	}
+}
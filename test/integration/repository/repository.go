@@ -8,7 +8,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/segmentio/ksuid"
 	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/aggregate"
+	"github.com/sf9v/nero/comparison"
 	"github.com/sf9v/nero/example"
+	"github.com/sf9v/nero/sort"
 	"github.com/sf9v/nero/test/integration/user"
 )
 
@@ -20,10 +23,22 @@ type Repository interface {
 	Create(context.Context, *Creator) (id string, err error)
 	// CreateTx creates a new type .Type.Name}} inside a transaction
 	CreateTx(context.Context, nero.Tx, *Creator) (id string, err error)
-	// CreateMany creates many User
-	CreateMany(context.Context, ...*Creator) error
-	// CreateManyTx creates many User inside a transaction
-	CreateManyTx(context.Context, nero.Tx, ...*Creator) error
+	// CreateReturning creates a new User and returns the created row
+	CreateReturning(context.Context, *Creator) (*user.User, error)
+	// CreateReturningTx creates a new User inside a transaction and returns the created row
+	CreateReturningTx(context.Context, nero.Tx, *Creator) (*user.User, error)
+	// CreateMany creates many User and returns the identities in order
+	CreateMany(context.Context, ...*Creator) (ids []string, err error)
+	// CreateManyTx creates many User inside a transaction and returns the identities in order
+	CreateManyTx(context.Context, nero.Tx, ...*Creator) (ids []string, err error)
+	// Upsert creates a new User or updates the conflicting one
+	Upsert(context.Context, *Upserter) (id string, err error)
+	// UpsertTx creates a new User or updates the conflicting one inside a transaction
+	UpsertTx(context.Context, nero.Tx, *Upserter) (id string, err error)
+	// UpsertMany creates many User or updates the conflicting ones
+	UpsertMany(context.Context, *Upserter) error
+	// UpsertManyTx creates many User or updates the conflicting ones inside a transaction
+	UpsertManyTx(context.Context, nero.Tx, *Upserter) error
 	// Query queries many User
 	Query(context.Context, *Queryer) ([]*user.User, error)
 	// QueryTx queries many { 000000000000000000000000000   0  map[] [] <nil> <nil>} inside a transaction
@@ -32,14 +47,34 @@ type Repository interface {
 	QueryOne(context.Context, *Queryer) (*user.User, error)
 	// QueryOneTx queries one User inside a transaction
 	QueryOneTx(context.Context, nero.Tx, *Queryer) (*user.User, error)
+	// QueryPage queries a page of User along with the cursors of the adjacent pages
+	QueryPage(context.Context, *Queryer) (*Page, error)
+	// QueryPageTx queries a page of User inside a transaction along with the cursors of the adjacent pages
+	QueryPageTx(context.Context, nero.Tx, *Queryer) (*Page, error)
+	// Count counts the User rows that match the predicates of the query
+	Count(context.Context, *Queryer) (int64, error)
+	// CountTx counts the User rows that match the predicates of the query inside a transaction
+	CountTx(context.Context, nero.Tx, *Queryer) (int64, error)
+	// Exists returns true if any of the User rows matches the predicates of the query
+	Exists(context.Context, *Queryer) (bool, error)
+	// ExistsTx returns true if any of the User rows matches the predicates of the query inside a transaction
+	ExistsTx(context.Context, nero.Tx, *Queryer) (bool, error)
 	// Update updates User
 	Update(context.Context, *Updater) (rowsAffected int64, err error)
 	// UpdateTx updates User inside a transaction
 	UpdateTx(context.Context, nero.Tx, *Updater) (rowsAffected int64, err error)
+	// UpdateReturning updates User and returns the updated rows
+	UpdateReturning(context.Context, *Updater) ([]*user.User, error)
+	// UpdateReturningTx updates User inside a transaction and returns the updated rows
+	UpdateReturningTx(context.Context, nero.Tx, *Updater) ([]*user.User, error)
 	// Delete deletes User
 	Delete(context.Context, *Deleter) (rowsAffected int64, err error)
 	// Delete deletes User inside a transaction
 	DeleteTx(context.Context, nero.Tx, *Deleter) (rowsAffected int64, err error)
+	// DeleteReturning deletes User and returns the deleted rows
+	DeleteReturning(context.Context, *Deleter) ([]*user.User, error)
+	// DeleteReturningTx deletes User inside a transaction and returns the deleted rows
+	DeleteReturningTx(context.Context, nero.Tx, *Deleter) ([]*user.User, error)
 	// Aggregate performs aggregate query
 	Aggregate(context.Context, *Aggregator) error
 	// Aggregate performs aggregate query inside a transaction
@@ -56,6 +91,7 @@ type Creator struct {
 	kv        example.Map
 	tags      []string
 	updatedAt *time.Time
+	columns   map[Column]bool
 }
 
 // NewCreator is a factory for Creator
@@ -66,57 +102,285 @@ func NewCreator() *Creator {
 // UID is a setter for uid
 func (c *Creator) UID(uid ksuid.KSUID) *Creator {
 	c.uid = uid
+	c.set(ColumnUID, false)
 	return c
 }
 
 // Email is a setter for email
 func (c *Creator) Email(email string) *Creator {
 	c.email = email
+	c.set(ColumnEmail, false)
 	return c
 }
 
 // Name is a setter for name
 func (c *Creator) Name(name string) *Creator {
 	c.name = name
+	c.set(ColumnName, false)
 	return c
 }
 
 // Age is a setter for age
 func (c *Creator) Age(age int) *Creator {
 	c.age = age
+	c.set(ColumnAge, false)
 	return c
 }
 
 // Group is a setter for group
 func (c *Creator) Group(group user.Group) *Creator {
 	c.group = group
+	c.set(ColumnGroup, false)
 	return c
 }
 
 // Kv is a setter for kv
 func (c *Creator) Kv(kv example.Map) *Creator {
 	c.kv = kv
+	c.set(ColumnKv, false)
 	return c
 }
 
 // Tags is a setter for tags
 func (c *Creator) Tags(tags []string) *Creator {
 	c.tags = tags
+	c.set(ColumnTags, false)
 	return c
 }
 
 // UpdatedAt is a setter for updatedAt
 func (c *Creator) UpdatedAt(updatedAt *time.Time) *Creator {
 	c.updatedAt = updatedAt
+	c.set(ColumnUpdatedAt, false)
 	return c
 }
 
+// SetNullUpdatedAt sets updatedAt to NULL
+func (c *Creator) SetNullUpdatedAt() *Creator {
+	var updatedAt *time.Time
+	c.updatedAt = updatedAt
+	c.set(ColumnUpdatedAt, true)
+	return c
+}
+
+func (c *Creator) set(col Column, null bool) {
+	if c.columns == nil {
+		c.columns = map[Column]bool{}
+	}
+	c.columns[col] = null
+}
+
+// IsSet returns true if the column was explicitly set
+func (c *Creator) IsSet(col Column) bool {
+	_, ok := c.columns[col]
+	return ok
+}
+
+// IsNull returns true if the column was explicitly set to NULL
+func (c *Creator) IsNull(col Column) bool {
+	return c.columns[col]
+}
+
+// Columns returns the explicitly set columns of the create builder
+func (c *Creator) Columns() []Column {
+	cols := []Column{}
+	if c.IsSet(ColumnUID) {
+		cols = append(cols, ColumnUID)
+	}
+	if c.IsSet(ColumnEmail) {
+		cols = append(cols, ColumnEmail)
+	}
+	if c.IsSet(ColumnName) {
+		cols = append(cols, ColumnName)
+	}
+	if c.IsSet(ColumnAge) {
+		cols = append(cols, ColumnAge)
+	}
+	if c.IsSet(ColumnGroup) {
+		cols = append(cols, ColumnGroup)
+	}
+	if c.IsSet(ColumnKv) {
+		cols = append(cols, ColumnKv)
+	}
+	if c.IsSet(ColumnTags) {
+		cols = append(cols, ColumnTags)
+	}
+	if c.IsSet(ColumnUpdatedAt) {
+		cols = append(cols, ColumnUpdatedAt)
+	}
+	return cols
+}
+
+// groupCreators splits the creators into the runs of consecutive creators
+// that set the same columns, each run can be inserted in a single statement
+func groupCreators(cs []*Creator) [][]*Creator {
+	groups := [][]*Creator{}
+	for i, c := range cs {
+		if i > 0 && sameColumns(cs[i-1], c) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], c)
+			continue
+		}
+		groups = append(groups, []*Creator{c})
+	}
+	return groups
+}
+
+// sameColumns returns true if the creators set the same columns
+func sameColumns(a, b *Creator) bool {
+	if len(a.columns) != len(b.columns) {
+		return false
+	}
+	for col := range a.columns {
+		if !b.IsSet(col) {
+			return false
+		}
+	}
+	return true
+}
+
+// Upserter is an upsert builder for User
+type Upserter struct {
+	cs        []*Creator
+	conflict  []Column
+	updates   []Column
+	doNothing bool
+}
+
+// NewUpserter is a factory for Upserter
+func NewUpserter(cs ...*Creator) *Upserter {
+	return &Upserter{cs: cs}
+}
+
+// Create adds creators to the upsert builder
+func (u *Upserter) Create(cs ...*Creator) *Upserter {
+	u.cs = append(u.cs, cs...)
+	return u
+}
+
+// OnConflict sets the conflict target, defaults to the identity unless
+// it is auto-filled in which case the conflict target is required
+func (u *Upserter) OnConflict(cols ...Column) *Upserter {
+	u.conflict = append(u.conflict, cols...)
+	return u
+}
+
+// Update sets the columns to update on conflict,
+// defaults to the set columns of the creators
+func (u *Upserter) Update(cols ...Column) *Upserter {
+	u.updates = append(u.updates, cols...)
+	return u
+}
+
+// DoNothing leaves the conflicting rows as they are
+func (u *Upserter) DoNothing() *Upserter {
+	u.doNothing = true
+	return u
+}
+
+// Creators returns the creators of the upsert builder
+func (u *Upserter) Creators() []*Creator {
+	return u.cs
+}
+
+// ConflictColumns returns the conflict target of the upsert builder
+func (u *Upserter) ConflictColumns() []Column {
+	// the auto-filled identities can't be set by the creators,
+	// so they are not a default conflict target
+	return u.conflict
+}
+
+// UpdateColumns returns the columns to update on conflict,
+// it is empty if the conflicting rows are left as they are
+func (u *Upserter) UpdateColumns() []Column {
+	if u.doNothing {
+		return []Column{}
+	}
+
+	if len(u.updates) > 0 {
+		return u.updates
+	}
+
+	conflict := map[Column]struct{}{}
+	for _, col := range u.ConflictColumns() {
+		conflict[col] = struct{}{}
+	}
+
+	cols := []Column{}
+	if _, ok := conflict[ColumnUID]; !ok && u.isSet(ColumnUID) {
+		cols = append(cols, ColumnUID)
+	}
+	if _, ok := conflict[ColumnEmail]; !ok && u.isSet(ColumnEmail) {
+		cols = append(cols, ColumnEmail)
+	}
+	if _, ok := conflict[ColumnName]; !ok && u.isSet(ColumnName) {
+		cols = append(cols, ColumnName)
+	}
+	if _, ok := conflict[ColumnAge]; !ok && u.isSet(ColumnAge) {
+		cols = append(cols, ColumnAge)
+	}
+	if _, ok := conflict[ColumnGroup]; !ok && u.isSet(ColumnGroup) {
+		cols = append(cols, ColumnGroup)
+	}
+	if _, ok := conflict[ColumnKv]; !ok && u.isSet(ColumnKv) {
+		cols = append(cols, ColumnKv)
+	}
+	if _, ok := conflict[ColumnTags]; !ok && u.isSet(ColumnTags) {
+		cols = append(cols, ColumnTags)
+	}
+	if _, ok := conflict[ColumnUpdatedAt]; !ok && u.isSet(ColumnUpdatedAt) {
+		cols = append(cols, ColumnUpdatedAt)
+	}
+	return cols
+}
+
+func (u *Upserter) isSet(col Column) bool {
+	for _, c := range u.cs {
+		if c.IsSet(col) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that the conflict columns are set in every creator
+// and that the creators set the same columns
+func (u *Upserter) validate(one bool) error {
+	if one && len(u.cs) != 1 {
+		return errors.Errorf("expecting 1 creator, got %d", len(u.cs))
+	}
+
+	if len(u.ConflictColumns()) == 0 {
+		return errors.New("conflict target required, use OnConflict to set it")
+	}
+
+	for _, c := range u.cs {
+		for _, col := range u.ConflictColumns() {
+			if !c.IsSet(col) {
+				return errors.Errorf("conflict column %q is not set", col)
+			}
+		}
+
+		if !sameColumns(c, u.cs[0]) {
+			return errors.New("expecting the creators to set the same columns")
+		}
+	}
+
+	return nil
+}
+
 // Queryer is a query builder for User
 type Queryer struct {
-	limit  uint
-	offset uint
-	pfs    []PredFunc
-	sfs    []SortFunc
+	limit    uint
+	offset   uint
+	pfs      []PredFunc
+	sfs      []SortFunc
+	cols     []Column
+	after    nero.Cursor
+	before   nero.Cursor
+	paged    bool
+	reversed bool
+	lock     nero.LockStrength
+	wait     nero.LockWait
 }
 
 // NewQueryer is a factory for Queryer
@@ -148,6 +412,446 @@ func (q *Queryer) Offset(offset uint) *Queryer {
 	return q
 }
 
+// Select limits the selected columns, the other fields of the rows are left empty
+func (q *Queryer) Select(cols ...Column) *Queryer {
+	q.cols = append(q.cols, cols...)
+	return q
+}
+
+// Selected returns the selected columns of the query, nil if all the columns are selected
+func (q *Queryer) Selected() []Column {
+	return q.cols
+}
+
+// ForUpdate locks the queried rows for update until the end of the transaction
+func (q *Queryer) ForUpdate() *Queryer {
+	q.lock = nero.ForUpdate
+	return q
+}
+
+// ForNoKeyUpdate locks the queried rows for update, except
+// for the identities, until the end of the transaction
+func (q *Queryer) ForNoKeyUpdate() *Queryer {
+	q.lock = nero.ForNoKeyUpdate
+	return q
+}
+
+// ForShare locks the queried rows against update until the end of the transaction
+func (q *Queryer) ForShare() *Queryer {
+	q.lock = nero.ForShare
+	return q
+}
+
+// NoWait fails the query instead of waiting for the rows that are locked
+// by the other transactions, it has no effect if the rows aren't locked
+func (q *Queryer) NoWait() *Queryer {
+	q.wait = nero.NoWait
+	return q
+}
+
+// SkipLocked skips the rows that are locked by the other
+// transactions, it has no effect if the rows aren't locked
+func (q *Queryer) SkipLocked() *Queryer {
+	q.wait = nero.SkipLocked
+	return q
+}
+
+// Lock returns the row lock of the query
+func (q *Queryer) Lock() (nero.LockStrength, nero.LockWait) {
+	return q.lock, q.wait
+}
+
+// allColumns returns all the columns of User in the order of the schema
+func allColumns() []Column {
+	return []Column{
+		ColumnID,
+		ColumnUID,
+		ColumnEmail,
+		ColumnName,
+		ColumnAge,
+		ColumnGroup,
+		ColumnKv,
+		ColumnTags,
+		ColumnUpdatedAt,
+		ColumnCreatedAt,
+	}
+}
+
+// columns returns the columns to be queried, it includes the columns
+// that are needed for loading the edges and the keyset pagination
+func (q *Queryer) columns() []Column {
+	if len(q.cols) == 0 {
+		return allColumns()
+	}
+
+	cols := []Column{}
+	seen := map[Column]bool{}
+	add := func(col Column) {
+		if !seen[col] {
+			seen[col] = true
+			cols = append(cols, col)
+		}
+	}
+	for _, col := range q.cols {
+		add(col)
+	}
+	if q.paged || q.after != "" || q.before != "" {
+		keys, _ := q.keys()
+		for _, key := range keys {
+			add(columnOf(key.Col))
+		}
+	}
+
+	return cols
+}
+
+// columnOf returns the column with the name
+func columnOf(name string) Column {
+	switch name {
+	case "id":
+		return ColumnID
+	case "uid":
+		return ColumnUID
+	case "email":
+		return ColumnEmail
+	case "name":
+		return ColumnName
+	case "age":
+		return ColumnAge
+	case "group":
+		return ColumnGroup
+	case "kv":
+		return ColumnKv
+	case "tags":
+		return ColumnTags
+	case "updated_at":
+		return ColumnUpdatedAt
+	case "created_at":
+		return ColumnCreatedAt
+	}
+	return Column(-1)
+}
+
+// project returns a copy of the row with only the columns
+func project(row *user.User, cols []Column) *user.User {
+	cp := new(user.User)
+	for _, col := range cols {
+		switch col {
+		case ColumnID:
+			cp.ID = row.ID
+		case ColumnUID:
+			cp.UID = row.UID
+		case ColumnEmail:
+			cp.Email = row.Email
+		case ColumnName:
+			cp.Name = row.Name
+		case ColumnAge:
+			cp.Age = row.Age
+		case ColumnGroup:
+			cp.Group = row.Group
+		case ColumnKv:
+			cp.Kv = row.Kv
+		case ColumnTags:
+			cp.Tags = row.Tags
+		case ColumnUpdatedAt:
+			cp.UpdatedAt = row.UpdatedAt
+		case ColumnCreatedAt:
+			cp.CreatedAt = row.CreatedAt
+		}
+	}
+	return cp
+}
+
+// SelectSummary selects the columns of Summary
+func (q *Queryer) SelectSummary() *Queryer {
+	return q.Select(
+		ColumnID,
+		ColumnName,
+		ColumnEmail,
+	)
+}
+
+// ToSummaries projects the rows into Summary
+func ToSummaries(rows []*user.User) []*user.Summary {
+	result := make([]*user.Summary, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &user.Summary{
+			ID:    row.ID,
+			Name:  row.Name,
+			Email: row.Email,
+		})
+	}
+	return result
+}
+
+// After queries the rows after the cursor, see Page
+func (q *Queryer) After(cursor nero.Cursor) *Queryer {
+	q.after, q.before = cursor, ""
+	return q
+}
+
+// Before queries the rows before the cursor, see Page
+func (q *Queryer) Before(cursor nero.Cursor) *Queryer {
+	q.before, q.after = cursor, ""
+	return q
+}
+
+// Cursors returns the after and before cursors of the query
+func (q *Queryer) Cursors() (after, before nero.Cursor) {
+	return q.after, q.before
+}
+
+// Predicates returns the predicates of the query
+func (q *Queryer) Predicates() *comparison.Predicates {
+	return buildPredicates(q.pfs)
+}
+
+// Sorts returns the sorting expressions of the query
+func (q *Queryer) Sorts() *sort.Sorts {
+	return buildSorts(q.sfs)
+}
+
+// LimitOffset returns the limit and offset of the query
+func (q *Queryer) LimitOffset() (limit, offset uint) {
+	return q.limit, q.offset
+}
+
+// Page is a page of User, pass the cursors to
+// Queryer.After and Queryer.Before to query the adjacent pages
+type Page struct {
+	Rows []*user.User
+	// Next is the cursor of the last row, empty if there are no rows
+	Next nero.Cursor
+	// Prev is the cursor of the first row, empty if there are no rows
+	Prev nero.Cursor
+}
+
+// newPage creates a page of the rows queried by the query
+func newPage(q *Queryer, rows []*user.User) (*Page, error) {
+	page := &Page{Rows: rows}
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	keys, err := q.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	page.Prev, err = newCursor(keys, rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	page.Next, err = newCursor(keys, rows[len(rows)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// filter returns a query with only the predicates of the query
+func (q *Queryer) filter() *Queryer {
+	return &Queryer{pfs: q.pfs}
+}
+
+// page returns a copy of the query that is sorted by the keys of the pagination
+func (q *Queryer) page() *Queryer {
+	cp := *q
+	cp.paged = true
+	return &cp
+}
+
+// keys returns the sorts of the keyset pagination, the identity is the tie-breaker
+func (q *Queryer) keys() ([]*sort.Sort, error) {
+	keys := []*sort.Sort{}
+	for _, s := range buildSorts(q.sfs).All() {
+		if s.Rank != nil {
+			return nil, errors.New("cannot paginate by the full-text search rank")
+		}
+		keys = append(keys, s)
+	}
+
+	if !hasKey(keys, "id") {
+		keys = append(keys, &sort.Sort{Col: "id", Direction: sort.Asc})
+	}
+	return keys, nil
+}
+
+// keyset returns a copy of the query with the predicates and the sorts of the
+// keyset pagination, the sorts are reversed when querying before a cursor
+func (q *Queryer) keyset() (*Queryer, error) {
+	if !q.paged && q.after == "" && q.before == "" {
+		return q, nil
+	}
+
+	keys, err := q.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	cp := *q
+	cp.after, cp.before = "", ""
+	cp.reversed = q.before != ""
+	cp.sfs = []SortFunc{}
+	for _, key := range keys {
+		key := *key
+		if cp.reversed {
+			key.Direction = reverseDirection(key.Direction)
+		}
+		cp.sfs = append(cp.sfs, func(s *sort.Sorts) {
+			s.Add(&key)
+		})
+	}
+
+	cursor := q.after
+	if cp.reversed {
+		cursor = q.before
+	}
+	if cursor == "" {
+		return &cp, nil
+	}
+
+	row := new(user.User)
+	cols, err := cursor.Decode(func(col string) interface{} {
+		return cursorField(row, col)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cols) != len(keys) {
+		return nil, errors.New("cursor doesn't match the sorts of the query")
+	}
+
+	or := []*comparison.Predicate{}
+	for i, key := range keys {
+		if cols[i] != key.Col {
+			return nil, errors.New("cursor doesn't match the sorts of the query")
+		}
+
+		val := cursorValue(row, key.Col)
+		if val == nil {
+			return nil, errors.Errorf("cursor has a null value for column %q", key.Col)
+		}
+
+		// the previous keys are equal and this one is after the cursor
+		and := []*comparison.Predicate{}
+		for _, prev := range keys[:i] {
+			and = append(and, &comparison.Predicate{
+				Col: prev.Col,
+				Op:  comparison.Eq,
+				Arg: cursorValue(row, prev.Col),
+			})
+		}
+
+		op := comparison.Gt
+		if (key.Direction == sort.Desc) != cp.reversed {
+			op = comparison.Lt
+		}
+		and = append(and, &comparison.Predicate{Col: key.Col, Op: op, Arg: val})
+		or = append(or, &comparison.Predicate{Op: comparison.And, Arg: and})
+	}
+
+	cp.pfs = append(append([]PredFunc{}, q.pfs...), func(pb *comparison.Predicates) {
+		pb.Add(&comparison.Predicate{Op: comparison.Or, Arg: or})
+	})
+
+	return &cp, nil
+}
+
+// newCursor creates the cursor of the row
+func newCursor(keys []*sort.Sort, row *user.User) (nero.Cursor, error) {
+	cols := make([]string, 0, len(keys))
+	vals := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		cols = append(cols, key.Col)
+		vals = append(vals, cursorValue(row, key.Col))
+	}
+	return nero.NewCursor(cols, vals)
+}
+
+// cursorValue returns the value of the column, nil if it's null
+func cursorValue(row *user.User, col string) interface{} {
+	switch col {
+	case "id":
+		return row.ID
+	case "uid":
+		return row.UID
+	case "email":
+		return row.Email
+	case "name":
+		return row.Name
+	case "age":
+		return row.Age
+	case "group":
+		return row.Group
+	case "kv":
+		return row.Kv
+	case "tags":
+		return row.Tags
+	case "updated_at":
+		if row.UpdatedAt == nil {
+			return nil
+		}
+		return row.UpdatedAt
+	case "created_at":
+		return row.CreatedAt
+	}
+	return nil
+}
+
+// cursorField returns the pointer to the field of the column
+func cursorField(row *user.User, col string) interface{} {
+	switch col {
+	case "id":
+		return &row.ID
+	case "uid":
+		return &row.UID
+	case "email":
+		return &row.Email
+	case "name":
+		return &row.Name
+	case "age":
+		return &row.Age
+	case "group":
+		return &row.Group
+	case "kv":
+		return &row.Kv
+	case "tags":
+		return &row.Tags
+	case "updated_at":
+		return &row.UpdatedAt
+	case "created_at":
+		return &row.CreatedAt
+	}
+	return nil
+}
+
+func hasKey(keys []*sort.Sort, col string) bool {
+	for _, key := range keys {
+		if key.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+func reverseDirection(direction sort.Direction) sort.Direction {
+	if direction == sort.Desc {
+		return sort.Asc
+	}
+	return sort.Desc
+}
+
+// reverseRows reverses the rows in place
+func reverseRows(rows []*user.User) {
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
+
 // Updater is an update builder for User
 type Updater struct {
 	uid       ksuid.KSUID
@@ -158,7 +862,28 @@ type Updater struct {
 	kv        example.Map
 	tags      []string
 	updatedAt *time.Time
+	columns   map[Column]bool
+	exprs     []*updateExpr
 	pfs       []PredFunc
+	all       bool
+}
+
+// updateOp is the operator of an update expression
+type updateOp int
+
+const (
+	updateIncr updateOp = iota
+	updateDecr
+	updateCopy
+)
+
+// updateExpr is an update expression on a column
+type updateExpr struct {
+	col Column
+	op  updateOp
+	// arg is the amount for increments and decrements
+	// and the source column for copies
+	arg interface{}
 }
 
 // NewUpdater is a factory for Updater
@@ -167,51 +892,196 @@ func NewUpdater() *Updater {
 }
 
 // UID is a setter for uid
-func (c *Updater) UID(uid ksuid.KSUID) *Updater {
-	c.uid = uid
-	return c
+func (u *Updater) UID(uid ksuid.KSUID) *Updater {
+	u.uid = uid
+	u.set(ColumnUID, false)
+	return u
 }
 
 // Email is a setter for email
-func (c *Updater) Email(email string) *Updater {
-	c.email = email
-	return c
+func (u *Updater) Email(email string) *Updater {
+	u.email = email
+	u.set(ColumnEmail, false)
+	return u
 }
 
 // Name is a setter for name
-func (c *Updater) Name(name string) *Updater {
-	c.name = name
-	return c
+func (u *Updater) Name(name string) *Updater {
+	u.name = name
+	u.set(ColumnName, false)
+	return u
 }
 
 // Age is a setter for age
-func (c *Updater) Age(age int) *Updater {
-	c.age = age
-	return c
+func (u *Updater) Age(age int) *Updater {
+	u.age = age
+	u.set(ColumnAge, false)
+	return u
+}
+
+// AgeIncr atomically increments age by n
+func (u *Updater) AgeIncr(n int) *Updater {
+	u.setExpr(ColumnAge, updateIncr, n)
+	return u
+}
+
+// AgeDecr atomically decrements age by n
+func (u *Updater) AgeDecr(n int) *Updater {
+	u.setExpr(ColumnAge, updateDecr, n)
+	return u
 }
 
 // Group is a setter for group
-func (c *Updater) Group(group user.Group) *Updater {
-	c.group = group
-	return c
+func (u *Updater) Group(group user.Group) *Updater {
+	u.group = group
+	u.set(ColumnGroup, false)
+	return u
 }
 
 // Kv is a setter for kv
-func (c *Updater) Kv(kv example.Map) *Updater {
-	c.kv = kv
-	return c
+func (u *Updater) Kv(kv example.Map) *Updater {
+	u.kv = kv
+	u.set(ColumnKv, false)
+	return u
 }
 
 // Tags is a setter for tags
-func (c *Updater) Tags(tags []string) *Updater {
-	c.tags = tags
-	return c
+func (u *Updater) Tags(tags []string) *Updater {
+	u.tags = tags
+	u.set(ColumnTags, false)
+	return u
 }
 
 // UpdatedAt is a setter for updatedAt
-func (c *Updater) UpdatedAt(updatedAt *time.Time) *Updater {
-	c.updatedAt = updatedAt
-	return c
+func (u *Updater) UpdatedAt(updatedAt *time.Time) *Updater {
+	u.updatedAt = updatedAt
+	u.set(ColumnUpdatedAt, false)
+	return u
+}
+
+// SetNullUpdatedAt sets updatedAt to NULL
+func (u *Updater) SetNullUpdatedAt() *Updater {
+	var updatedAt *time.Time
+	u.updatedAt = updatedAt
+	u.set(ColumnUpdatedAt, true)
+	return u
+}
+
+// UpdatedAtFromCol sets updatedAt to the value of col
+func (u *Updater) UpdatedAtFromCol(col Column) *Updater {
+	u.setExpr(ColumnUpdatedAt, updateCopy, col)
+	return u
+}
+
+func (u *Updater) set(col Column, null bool) {
+	u.unsetExpr(col)
+	if u.columns == nil {
+		u.columns = map[Column]bool{}
+	}
+	u.columns[col] = null
+}
+
+func (u *Updater) setExpr(col Column, op updateOp, arg interface{}) {
+	delete(u.columns, col)
+	u.unsetExpr(col)
+	u.exprs = append(u.exprs, &updateExpr{col: col, op: op, arg: arg})
+}
+
+func (u *Updater) unsetExpr(col Column) {
+	exprs := []*updateExpr{}
+	for _, e := range u.exprs {
+		if e.col != col {
+			exprs = append(exprs, e)
+		}
+	}
+	u.exprs = exprs
+}
+
+// apply applies the update expression to dst using the values of src
+func (e *updateExpr) apply(dst, src *user.User) error {
+	switch e.col {
+	case ColumnAge:
+		switch e.op {
+		case updateIncr:
+			dst.Age = src.Age + e.arg.(int)
+			return nil
+		case updateDecr:
+			dst.Age = src.Age - e.arg.(int)
+			return nil
+		}
+	case ColumnUpdatedAt:
+		if e.op == updateCopy {
+			switch e.arg {
+			case ColumnUpdatedAt:
+				dst.UpdatedAt = src.UpdatedAt
+				return nil
+			case ColumnCreatedAt:
+				dst.UpdatedAt = src.CreatedAt
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("unsupported update expression on %s", e.col)
+}
+
+// sameType returns false if the update expression
+// copies a column of a different type
+func (e *updateExpr) sameType() bool {
+	if e.op != updateCopy {
+		return true
+	}
+
+	switch e.col {
+	case ColumnUpdatedAt:
+		switch e.arg {
+		case ColumnUpdatedAt:
+			return true
+		case ColumnCreatedAt:
+			return true
+		}
+	}
+	return false
+}
+
+// IsSet returns true if the column was explicitly set
+func (u *Updater) IsSet(col Column) bool {
+	_, ok := u.columns[col]
+	return ok
+}
+
+// IsNull returns true if the column was explicitly set to NULL
+func (u *Updater) IsNull(col Column) bool {
+	return u.columns[col]
+}
+
+// Columns returns the explicitly set columns of the update builder
+func (u *Updater) Columns() []Column {
+	cols := []Column{}
+	if u.IsSet(ColumnUID) {
+		cols = append(cols, ColumnUID)
+	}
+	if u.IsSet(ColumnEmail) {
+		cols = append(cols, ColumnEmail)
+	}
+	if u.IsSet(ColumnName) {
+		cols = append(cols, ColumnName)
+	}
+	if u.IsSet(ColumnAge) {
+		cols = append(cols, ColumnAge)
+	}
+	if u.IsSet(ColumnGroup) {
+		cols = append(cols, ColumnGroup)
+	}
+	if u.IsSet(ColumnKv) {
+		cols = append(cols, ColumnKv)
+	}
+	if u.IsSet(ColumnTags) {
+		cols = append(cols, ColumnTags)
+	}
+	if u.IsSet(ColumnUpdatedAt) {
+		cols = append(cols, ColumnUpdatedAt)
+	}
+	return cols
 }
 
 // Where adds predicates to the update builder
@@ -220,9 +1090,37 @@ func (u *Updater) Where(pfs ...PredFunc) *Updater {
 	return u
 }
 
+// Predicates returns the predicates of the update builder
+func (u *Updater) Predicates() *comparison.Predicates {
+	return buildPredicates(u.pfs)
+}
+
+// AllRows allows the update builder to update all
+// the rows when there are no predicates
+func (u *Updater) AllRows() *Updater {
+	u.all = true
+	return u
+}
+
+// Guard returns an error if the update builder copies a column of a different
+// type, or has no filtering predicates and AllRows wasn't called
+func (u *Updater) Guard() error {
+	for _, e := range u.exprs {
+		if !e.sameType() {
+			return &nero.ColumnTypeError{Column: e.col.String(), Source: e.arg.(Column).String()}
+		}
+	}
+
+	if !u.all && !comparison.Filters(u.Predicates().All()) {
+		return &nero.UnfilteredError{Op: "update", Collection: "users"}
+	}
+	return nil
+}
+
 // Deleter is a delete builder for User
 type Deleter struct {
 	pfs []PredFunc
+	all bool
 }
 
 // NewDeleter is a factory for Deleter
@@ -236,6 +1134,27 @@ func (d *Deleter) Where(pfs ...PredFunc) *Deleter {
 	return d
 }
 
+// Predicates returns the predicates of the delete builder
+func (d *Deleter) Predicates() *comparison.Predicates {
+	return buildPredicates(d.pfs)
+}
+
+// AllRows allows the delete builder to delete all
+// the rows when there are no predicates
+func (d *Deleter) AllRows() *Deleter {
+	d.all = true
+	return d
+}
+
+// Guard returns an error if the delete builder has no
+// filtering predicates and AllRows wasn't called
+func (d *Deleter) Guard() error {
+	if !d.all && !comparison.Filters(d.Predicates().All()) {
+		return &nero.UnfilteredError{Op: "delete", Collection: "users"}
+	}
+	return nil
+}
+
 // Aggregator is an aggregate builder for User
 type Aggregator struct {
 	v      interface{}
@@ -277,6 +1196,46 @@ func (a *Aggregator) Group(cols ...Column) *Aggregator {
 	return a
 }
 
+// Aggregates returns the aggregate functions of the aggregate builder
+func (a *Aggregator) Aggregates() *aggregate.Aggregates {
+	aggs := &aggregate.Aggregates{}
+	for _, aggf := range a.aggfs {
+		aggf(aggs)
+	}
+	return aggs
+}
+
+// Predicates returns the predicates of the aggregate builder
+func (a *Aggregator) Predicates() *comparison.Predicates {
+	return buildPredicates(a.pfs)
+}
+
+// Sorts returns the sorting expressions of the aggregate builder
+func (a *Aggregator) Sorts() *sort.Sorts {
+	return buildSorts(a.sfs)
+}
+
+// Groups returns the grouping columns of the aggregate builder
+func (a *Aggregator) Groups() []Column {
+	return a.groups
+}
+
+func buildPredicates(pfs []PredFunc) *comparison.Predicates {
+	pb := &comparison.Predicates{}
+	for _, pf := range pfs {
+		pf(pb)
+	}
+	return pb
+}
+
+func buildSorts(sfs []SortFunc) *sort.Sorts {
+	sorts := &sort.Sorts{}
+	for _, sf := range sfs {
+		sf(sorts)
+	}
+	return sorts
+}
+
 // rollback performs a rollback
 func rollback(tx nero.Tx, err error) error {
 	rerr := tx.Rollback()
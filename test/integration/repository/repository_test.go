@@ -2,7 +2,9 @@ package repository
 
 import (
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -36,3 +38,60 @@ func Test_rollback(t *testing.T) {
 	err = rollback(o, errors.New("an error"))
 	assert.Equal(t, "an error", err.Error())
 }
+
+func TestUpdaterColumns(t *testing.T) {
+	u := NewUpdater().Age(0).SetNullUpdatedAt()
+	assert.Equal(t, []Column{ColumnAge, ColumnUpdatedAt}, u.Columns())
+	assert.True(t, u.IsSet(ColumnAge))
+	assert.False(t, u.IsNull(ColumnAge))
+	assert.True(t, u.IsNull(ColumnUpdatedAt))
+	assert.False(t, u.IsSet(ColumnName))
+
+	// setting a value clears the NULL
+	now := time.Now()
+	u.UpdatedAt(&now)
+	assert.False(t, u.IsNull(ColumnUpdatedAt))
+
+	assert.Empty(t, NewCreator().Columns())
+}
+
+func TestPgxString(t *testing.T) {
+	var id string
+	assert.NoError(t, pgxString{&id}.Scan(int64(42)))
+	assert.Equal(t, "42", id)
+	assert.NoError(t, pgxString{&id}.Scan("43"))
+	assert.Equal(t, "43", id)
+	assert.NoError(t, pgxString{&id}.Scan([]byte("44")))
+	assert.Equal(t, "44", id)
+	assert.Error(t, pgxString{&id}.Scan(4.5))
+}
+
+func TestPgxNumber(t *testing.T) {
+	var agg struct {
+		Avg   float64
+		Min   int
+		Sum   int64
+		Count uint
+	}
+	v := reflect.ValueOf(&agg).Elem()
+	assert.True(t, isPgxNumber(v.Field(0)))
+
+	// the numeric average and the integer minimum, sum and count
+	assert.NoError(t, pgxNumber{v.Field(0)}.Scan("425e-1"))
+	assert.Equal(t, 42.5, agg.Avg)
+	assert.NoError(t, pgxNumber{v.Field(1)}.Scan(int64(18)))
+	assert.Equal(t, 18, agg.Min)
+	assert.NoError(t, pgxNumber{v.Field(2)}.Scan("12e2"))
+	assert.Equal(t, int64(1200), agg.Sum)
+	assert.NoError(t, pgxNumber{v.Field(3)}.Scan(int64(3)))
+	assert.Equal(t, uint(3), agg.Count)
+	assert.NoError(t, pgxNumber{v.Field(0)}.Scan(int64(18)))
+	assert.Equal(t, float64(18), agg.Avg)
+
+	assert.Error(t, pgxNumber{v.Field(1)}.Scan("425e-1"))
+	assert.Error(t, pgxNumber{v.Field(3)}.Scan(int64(-1)))
+	assert.Error(t, pgxNumber{v.Field(1)}.Scan(nil))
+
+	var group struct{ Name string }
+	assert.False(t, isPgxNumber(reflect.ValueOf(&group).Elem().Field(0)))
+}
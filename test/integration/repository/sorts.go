@@ -2,6 +2,7 @@
 package repository
 
 import (
+	"github.com/sf9v/nero/comparison"
 	"github.com/sf9v/nero/sort"
 )
 
@@ -27,3 +28,18 @@ func Desc(col Column) SortFunc {
 		})
 	}
 }
+
+// NameRank sorts by the full-text search rank of
+// "name" column against the query, best matches first
+func NameRank(query string) SortFunc {
+	return func(s *sort.Sorts) {
+		s.Add(&sort.Sort{
+			Col:       "name",
+			Direction: sort.Desc,
+			Rank: &comparison.TextSearch{
+				Config: "simple",
+				Query:  query,
+			},
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/sf9v/nero"
+	"github.com/sf9v/nero/test/integration/repository"
+	"github.com/sf9v/nero/test/integration/user"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	dsn := path.Join(t.TempDir(), "nero.db")
+
+	// regular methods
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	require.NoError(t, db.Ping())
+	require.NoError(t, createSQLiteTable(db))
+
+	logger := log.New(os.Stderr, "nero test: ", 0)
+	repo := repository.NewSQLiteRepository(db).Debug().WithLogger(logger)
+	newRepoTestRunner(repo, repoTestOpts{})(t)
+	require.NoError(t, dropTable(db))
+
+	// tx methods
+	require.NoError(t, createSQLiteTable(db))
+	repo = repository.NewSQLiteRepository(db).Debug().WithLogger(logger)
+	newRepoTestRunnerTx(repo, repoTestOpts{})(t)
+
+	// full-text search is not supported
+	_, err = repo.Query(context.Background(), repository.NewQueryer().
+		Where(repository.NameMatches("human")))
+	assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+	_, err = repo.Query(context.Background(), repository.NewQueryer().
+		Sort(repository.NameRank("human")))
+	assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+
+	// row locking is not supported
+	_, err = repo.Query(context.Background(), repository.NewQueryer().ForUpdate())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	tx, err := repo.Tx(context.Background())
+	require.NoError(t, err)
+	_, err = repo.QueryTx(context.Background(), tx, repository.NewQueryer().ForUpdate())
+	assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+	_, err = repo.CountTx(context.Background(), tx, repository.NewQueryer().ForUpdate())
+	assert.IsType(t, &nero.UnsupportedError{}, errors.Cause(err))
+	require.NoError(t, tx.Rollback())
+	_, err = repo.Count(context.Background(), repository.NewQueryer().ForUpdate())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	_, err = repo.Exists(context.Background(), repository.NewQueryer().ForUpdate())
+	assert.Equal(t, nero.ErrLockOutsideTx, err)
+	require.NoError(t, dropTable(db))
+}
+
+func TestSQLiteRepositoryCreateManyDefaults(t *testing.T) {
+	db, err := sql.Open("sqlite", path.Join(t.TempDir(), "nero.db"))
+	require.NoError(t, err)
+	require.NoError(t, createSQLiteTable(db))
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := repository.NewSQLiteRepository(db)
+
+	// the unset columns are left to their default values
+	ids, err := repo.CreateMany(ctx,
+		repository.NewCreator().UID(ksuid.New()).Email("a@gg.io").
+			Name("a").Age(20).Group(user.Charr).Tags([]string{}),
+		repository.NewCreator().UID(ksuid.New()).Email("b@gg.io").
+			Name("b").Age(20).Tags([]string{}),
+	)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	usrs, err := repo.Query(ctx, repository.NewQueryer().
+		Where(repository.IDIn(ids...)).Sort(repository.Asc(repository.ColumnID)))
+	require.NoError(t, err)
+	require.Len(t, usrs, 2)
+	assert.Equal(t, user.Charr, usrs[0].Group)
+	assert.Equal(t, user.Outcast, usrs[1].Group)
+
+	// upserting the creators with different columns is rejected
+	err = repo.UpsertMany(ctx, repository.NewUpserter(
+		repository.NewCreator().UID(ksuid.New()).Email("c@gg.io").
+			Name("c").Age(20).Group(user.Norn).Tags([]string{}),
+		repository.NewCreator().UID(ksuid.New()).Email("d@gg.io").
+			Name("d").Age(20).Tags([]string{})).
+		OnConflict(repository.ColumnEmail).
+		DoNothing())
+	assert.EqualError(t, err, "expecting the creators to set the same columns")
+}
+
+func TestSQLiteRepositoryCreateManyIdentities(t *testing.T) {
+	db, err := sql.Open("sqlite", path.Join(t.TempDir(), "nero.db"))
+	require.NoError(t, err)
+	require.NoError(t, createSQLiteTable(db))
+	defer db.Close()
+
+	// the trigger inserts a row in-between the created rows,
+	// so their rowids are not consecutive
+	_, err = db.Exec(`CREATE TRIGGER users_audit AFTER INSERT ON users
+		WHEN NEW.name = 'a' BEGIN
+			INSERT INTO users(uid, email, "name", age, tags)
+			VALUES ('audit', 'audit@gg.io', 'audit', 0, '[]');
+		END`)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := repository.NewSQLiteRepository(db)
+	names := []string{"a", "b", "c"}
+	cs := []*repository.Creator{}
+	for _, name := range names {
+		cs = append(cs, repository.NewCreator().UID(ksuid.New()).
+			Email(name+"@gg.io").Name(name).Age(20).Tags([]string{}))
+	}
+
+	ids, err := repo.CreateMany(ctx, cs...)
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	// the identities are in the order of the creators
+	for i, id := range ids {
+		usr, err := repo.QueryOne(ctx, repository.NewQueryer().
+			Where(repository.IDEq(id)))
+		require.NoError(t, err)
+		assert.Equal(t, names[i], usr.Name)
+	}
+}
+
+func createSQLiteTable(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE users(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uid VARCHAR(27) NOT NULL UNIQUE,
+		email VARCHAR(255) UNIQUE NOT NULL,
+		"name" VARCHAR(50) NOT NULL,
+		age INTEGER NOT NULL,
+		"group" VARCHAR(20) NOT NULL DEFAULT 'outcast',
+		kv JSON NULL,
+		tags JSON NOT NULL,
+		updated_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/segmentio/ksuid"
 	"github.com/sf9v/nero"
 	"github.com/sf9v/nero/example"
+	"github.com/sf9v/nero/template"
 )
 
 // User is a user
@@ -22,6 +23,13 @@ type User struct {
 	CreatedAt *time.Time
 }
 
+// Summary is a summary of a user
+type Summary struct {
+	ID    string
+	Name  string
+	Email string `nero:"email"`
+}
+
 // Group is a group
 type Group string
 
@@ -45,15 +53,29 @@ func (u *User) Schema() *nero.Schema {
 			nero.NewColumn("uid", u.UID).
 				StructField("UID"),
 			nero.NewColumn("email", u.Email),
-			nero.NewColumn("name", u.Name),
+			nero.NewColumn("name", u.Name).
+				FullText("simple"),
 			nero.NewColumn("age", u.Age),
 			nero.NewColumn("group", u.Group).
 				StructField("Group"),
 			nero.NewColumn("kv", u.Kv),
 			nero.NewColumn("tags", u.Tags),
-			nero.NewColumn("updated_at", u.UpdatedAt).Nullable(),
+			nero.NewColumn("updated_at", u.UpdatedAt).
+				Nullable().ColumnComparable(),
 			nero.NewColumn("created_at", u.CreatedAt).
 				Auto(),
 		},
+		Projections: []interface{}{
+			&Summary{},
+		},
+		Templates: []nero.Templater{
+			template.NewPostgresTemplate(),
+			template.NewSQLiteTemplate(),
+			template.NewMySQLTemplate(),
+			template.NewMemoryTemplate(),
+			template.NewPgxTemplate(),
+			template.NewBoltTemplate(),
+			template.NewMockTemplate(),
+		},
 	}
 }